@@ -0,0 +1,193 @@
+package adaptive
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	DefaultTargetRespTime     = 200 * time.Millisecond
+	DefaultMinBatch           = 16
+	DefaultMaxBatch           = 1024
+	DefaultMinConcurrency     = 1
+	DefaultMaxConcurrency     = 512
+	DefaultInitialBatch       = 128
+	DefaultInitialConcurrency = 32
+
+	// DecreaseFactor is the multiplicative decrease of the batch and the concurrency once overloaded.
+	DecreaseFactor = 0.5
+)
+
+type (
+	// Controller adjusts the batch size and the concurrency by AIMD, it increases them additively while
+	// the average response time of the requests stays under the target, and decreases them multiplicatively
+	// once the average is over the target, or the graphd asks to retry more, such as the raft buffer is full.
+	// The requests are evaluated in windows, each window has as many requests as the concurrency,
+	// so that the changes take effect before the next evaluation.
+	Controller struct {
+		mu             sync.Mutex
+		targetRespTime time.Duration
+		minBatch       int
+		maxBatch       int
+		minConcurrency int
+		maxConcurrency int
+		batch          int
+		concurrency    int
+		onChange       []ChangeFunc
+
+		// The requests of the current window, and whether it's started by an overload.
+		count       int
+		sumRespTime time.Duration
+		overloaded  bool
+	}
+
+	// ChangeFunc is called with the new batch size and concurrency once changed.
+	ChangeFunc func(batch, concurrency int)
+
+	Option func(*Controller)
+)
+
+func New(opts ...Option) *Controller {
+	c := &Controller{
+		targetRespTime: DefaultTargetRespTime,
+		minBatch:       DefaultMinBatch,
+		maxBatch:       DefaultMaxBatch,
+		minConcurrency: DefaultMinConcurrency,
+		maxConcurrency: DefaultMaxConcurrency,
+		batch:          DefaultInitialBatch,
+		concurrency:    DefaultInitialConcurrency,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.maxBatch < c.minBatch {
+		c.maxBatch = c.minBatch
+	}
+	if c.maxConcurrency < c.minConcurrency {
+		c.maxConcurrency = c.minConcurrency
+	}
+	c.batch = clamp(c.batch, c.minBatch, c.maxBatch)
+	c.concurrency = clamp(c.concurrency, c.minConcurrency, c.maxConcurrency)
+	return c
+}
+
+// WithTargetRespTime sets the target of the average response time of the requests.
+func WithTargetRespTime(d time.Duration) Option {
+	return func(c *Controller) {
+		if d > 0 {
+			c.targetRespTime = d
+		}
+	}
+}
+
+// WithBatchRange sets the min and max batch size, zero keeps the default.
+func WithBatchRange(minBatch, maxBatch int) Option {
+	return func(c *Controller) {
+		if minBatch > 0 {
+			c.minBatch = minBatch
+		}
+		if maxBatch > 0 {
+			c.maxBatch = maxBatch
+		}
+	}
+}
+
+// WithConcurrencyRange sets the min and max concurrency, zero keeps the default.
+func WithConcurrencyRange(minConcurrency, maxConcurrency int) Option {
+	return func(c *Controller) {
+		if minConcurrency > 0 {
+			c.minConcurrency = minConcurrency
+		}
+		if maxConcurrency > 0 {
+			c.maxConcurrency = maxConcurrency
+		}
+	}
+}
+
+// WithInitial sets the initial batch size and concurrency, zero keeps the default.
+func WithInitial(batch, concurrency int) Option {
+	return func(c *Controller) {
+		if batch > 0 {
+			c.batch = batch
+		}
+		if concurrency > 0 {
+			c.concurrency = concurrency
+		}
+	}
+}
+
+// OnChange adds fn to be called once the batch size or the concurrency changed,
+// it's called with the lock held, so the changes are observed in order.
+func (c *Controller) OnChange(fn ChangeFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onChange = append(c.onChange, fn)
+}
+
+// Values returns the current batch size and concurrency.
+func (c *Controller) Values() (batch, concurrency int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.batch, c.concurrency
+}
+
+// Succeeded observes the response time of a succeeded request.
+func (c *Controller) Succeeded(respTime time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.count++
+	c.sumRespTime += respTime
+	if c.count < c.concurrency {
+		return
+	}
+	if c.sumRespTime/time.Duration(c.count) > c.targetRespTime {
+		c.decrease()
+	} else {
+		c.increase()
+	}
+}
+
+// Overloaded observes that the graphd asks to retry more, it decreases at most once in each window,
+// since the requests in flight are sent before the decrease.
+func (c *Controller) Overloaded() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.overloaded {
+		return
+	}
+	c.decrease()
+	c.overloaded = true
+}
+
+func (c *Controller) increase() {
+	c.set(c.batch+c.minBatch, c.concurrency+1)
+}
+
+func (c *Controller) decrease() {
+	c.set(int(float64(c.batch)*DecreaseFactor), int(float64(c.concurrency)*DecreaseFactor))
+}
+
+// set starts a new window with the batch size and concurrency.
+func (c *Controller) set(batch, concurrency int) {
+	c.count, c.sumRespTime, c.overloaded = 0, 0, false
+	batch = clamp(batch, c.minBatch, c.maxBatch)
+	concurrency = clamp(concurrency, c.minConcurrency, c.maxConcurrency)
+	if batch == c.batch && concurrency == c.concurrency {
+		return
+	}
+	c.batch, c.concurrency = batch, concurrency
+	for _, fn := range c.onChange {
+		fn(batch, concurrency)
+	}
+}
+
+func clamp(v, minValue, maxValue int) int {
+	if v < minValue {
+		return minValue
+	}
+	if v > maxValue {
+		return maxValue
+	}
+	return v
+}
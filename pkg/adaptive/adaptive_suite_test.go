@@ -0,0 +1,13 @@
+package adaptive
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestAdaptive(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Pkg adaptive Suite")
+}
@@ -0,0 +1,100 @@
+package adaptive
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Controller", func() {
+	It("default", func() {
+		c := New()
+		batch, concurrency := c.Values()
+		Expect(batch).To(Equal(DefaultInitialBatch))
+		Expect(concurrency).To(Equal(DefaultInitialConcurrency))
+	})
+
+	It("options", func() {
+		c := New(
+			WithTargetRespTime(time.Second),
+			WithBatchRange(10, 100),
+			WithConcurrencyRange(2, 4),
+			WithInitial(1000, 1),
+		)
+		Expect(c.targetRespTime).To(Equal(time.Second))
+		batch, concurrency := c.Values()
+		Expect(batch).To(Equal(100))
+		Expect(concurrency).To(Equal(2))
+
+		c = New(WithBatchRange(100, 10), WithConcurrencyRange(4, 2))
+		Expect(c.maxBatch).To(Equal(100))
+		Expect(c.maxConcurrency).To(Equal(4))
+	})
+
+	It("increase and decrease", func() {
+		var changes [][2]int
+		c := New(
+			WithTargetRespTime(100*time.Millisecond),
+			WithBatchRange(10, 40),
+			WithConcurrencyRange(1, 3),
+			WithInitial(20, 2),
+		)
+		c.OnChange(func(batch, concurrency int) {
+			changes = append(changes, [2]int{batch, concurrency})
+		})
+
+		// The window has 2 requests.
+		c.Succeeded(50 * time.Millisecond)
+		Expect(changes).To(BeEmpty())
+		c.Succeeded(100 * time.Millisecond)
+		Expect(changes).To(Equal([][2]int{{30, 3}}))
+
+		// The window has 3 requests, and the max concurrency is reached.
+		c.Succeeded(50 * time.Millisecond)
+		c.Succeeded(50 * time.Millisecond)
+		c.Succeeded(50 * time.Millisecond)
+		Expect(changes).To(Equal([][2]int{{30, 3}, {40, 3}}))
+
+		// Not changed at the max.
+		for i := 0; i < 3; i++ {
+			c.Succeeded(50 * time.Millisecond)
+		}
+		Expect(changes).To(HaveLen(2))
+
+		// The average is over the target.
+		c.Succeeded(50 * time.Millisecond)
+		c.Succeeded(50 * time.Millisecond)
+		c.Succeeded(300 * time.Millisecond)
+		Expect(changes).To(Equal([][2]int{{30, 3}, {40, 3}, {20, 1}}))
+
+		batch, concurrency := c.Values()
+		Expect(batch).To(Equal(20))
+		Expect(concurrency).To(Equal(1))
+	})
+
+	It("overloaded", func() {
+		var changes [][2]int
+		c := New(
+			WithBatchRange(10, 100),
+			WithConcurrencyRange(1, 100),
+			WithInitial(80, 8),
+		)
+		c.OnChange(func(batch, concurrency int) {
+			changes = append(changes, [2]int{batch, concurrency})
+		})
+
+		c.Overloaded()
+		// Decreased once in the window.
+		c.Overloaded()
+		Expect(changes).To(Equal([][2]int{{40, 4}}))
+
+		for i := 0; i < 4; i++ {
+			c.Succeeded(time.Millisecond)
+		}
+		Expect(changes).To(Equal([][2]int{{40, 4}, {50, 5}}))
+
+		c.Overloaded()
+		Expect(changes).To(Equal([][2]int{{40, 4}, {50, 5}, {25, 2}}))
+	})
+})
@@ -0,0 +1,154 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+)
+
+const (
+	DefaultFlushInterval = time.Second
+)
+
+var _ Store = (*fileStore)(nil)
+
+type (
+	// Checkpoint is the committed prefix of a source.
+	Checkpoint struct {
+		// Offset is the bytes of the source committed.
+		Offset int64 `json:"offset"`
+		// Records is the number of records committed.
+		Records int64 `json:"records"`
+	}
+
+	Store interface {
+		// Load returns the checkpoint of the key saved before.
+		Load(key string) (Checkpoint, bool)
+		// Save saves the checkpoint of the key, it may be flushed lazily.
+		Save(key string, cp Checkpoint) error
+		// Close flushes the checkpoints and closes the store.
+		Close() error
+	}
+
+	fileStore struct {
+		path          string
+		hash          string
+		flushInterval time.Duration
+		mu            sync.Mutex
+		checkpoints   map[string]Checkpoint
+		isDirty       bool
+		lastFlush     time.Time
+	}
+
+	fileContent struct {
+		Hash    string                `json:"hash"`
+		Sources map[string]Checkpoint `json:"sources"`
+	}
+
+	Option func(*fileStore)
+)
+
+// NewFileStore returns a store which saves the checkpoints in the local file in json.
+// The checkpoints are keyed by the source name, and discarded if the hash of the config changed.
+func NewFileStore(path, hash string, opts ...Option) (Store, error) {
+	s := &fileStore{
+		path:          path,
+		hash:          hash,
+		flushInterval: DefaultFlushInterval,
+		checkpoints:   map[string]Checkpoint{},
+		lastFlush:     time.Now(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, errors.NewImportError(err, "checkpoint: read file failed").SetFileName(path)
+	}
+	if len(content) == 0 {
+		return s, nil
+	}
+
+	var fc fileContent
+	if err = json.Unmarshal(content, &fc); err != nil {
+		return nil, errors.NewImportError(errors.ErrInvalidCheckpoint, "%s", err).SetFileName(path)
+	}
+	if fc.Hash == hash && fc.Sources != nil {
+		s.checkpoints = fc.Sources
+	}
+	return s, nil
+}
+
+func WithFlushInterval(interval time.Duration) Option {
+	return func(s *fileStore) {
+		s.flushInterval = interval
+	}
+}
+
+func (s *fileStore) Load(key string) (Checkpoint, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp, ok := s.checkpoints[key]
+	return cp, ok
+}
+
+func (s *fileStore) Save(key string, cp Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoints[key] = cp
+	s.isDirty = true
+	if time.Since(s.lastFlush) < s.flushInterval {
+		return nil
+	}
+	return s.flush()
+}
+
+func (s *fileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.flush()
+}
+
+// flush writes to a temporary file and renames it, so that the file is never half written.
+func (s *fileStore) flush() error {
+	if !s.isDirty {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(fileContent{
+		Hash:    s.hash,
+		Sources: s.checkpoints,
+	}, "", "  ")
+	if err != nil {
+		return errors.NewImportError(err, "checkpoint: marshal failed").SetFileName(s.path)
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return errors.NewImportError(err, "checkpoint: create file failed").SetFileName(s.path)
+	}
+	tmpPath := f.Name()
+	_, err = f.Write(content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, s.path)
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return errors.NewImportError(err, "checkpoint: write file failed").SetFileName(s.path)
+	}
+
+	s.isDirty = false
+	s.lastFlush = time.Now()
+	return nil
+}
@@ -0,0 +1,108 @@
+package checkpoint
+
+import (
+	stderrors "errors"
+	"os"
+	"path/filepath"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("fileStore", func() {
+	var (
+		tmpdir string
+		path   string
+	)
+	BeforeEach(func() {
+		var err error
+		tmpdir, err = os.MkdirTemp("", "test")
+		Expect(err).NotTo(HaveOccurred())
+		path = filepath.Join(tmpdir, "checkpoint.json")
+	})
+	AfterEach(func() {
+		err := os.RemoveAll(tmpdir)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("not exists", func() {
+		s, err := NewFileStore(path, "hash")
+		Expect(err).NotTo(HaveOccurred())
+		cp, ok := s.Load("source")
+		Expect(ok).To(BeFalse())
+		Expect(cp).To(Equal(Checkpoint{}))
+		Expect(s.Close()).NotTo(HaveOccurred())
+		_, err = os.Stat(path)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("save and load", func() {
+		s, err := NewFileStore(path, "hash", WithFlushInterval(0))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Save("source1", Checkpoint{Offset: 10, Records: 1})).NotTo(HaveOccurred())
+		Expect(s.Save("source2", Checkpoint{Offset: 20, Records: 2})).NotTo(HaveOccurred())
+		cp, ok := s.Load("source1")
+		Expect(ok).To(BeTrue())
+		Expect(cp).To(Equal(Checkpoint{Offset: 10, Records: 1}))
+
+		s, err = NewFileStore(path, "hash")
+		Expect(err).NotTo(HaveOccurred())
+		cp, ok = s.Load("source2")
+		Expect(ok).To(BeTrue())
+		Expect(cp).To(Equal(Checkpoint{Offset: 20, Records: 2}))
+
+		entries, err := os.ReadDir(tmpdir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+	})
+
+	It("flush on close", func() {
+		s, err := NewFileStore(path, "hash")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Save("source", Checkpoint{Offset: 10, Records: 1})).NotTo(HaveOccurred())
+		_, err = os.Stat(path)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+		Expect(s.Close()).NotTo(HaveOccurred())
+
+		s, err = NewFileStore(path, "hash")
+		Expect(err).NotTo(HaveOccurred())
+		cp, ok := s.Load("source")
+		Expect(ok).To(BeTrue())
+		Expect(cp).To(Equal(Checkpoint{Offset: 10, Records: 1}))
+	})
+
+	It("hash changed", func() {
+		s, err := NewFileStore(path, "hash1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Save("source", Checkpoint{Offset: 10, Records: 1})).NotTo(HaveOccurred())
+		Expect(s.Close()).NotTo(HaveOccurred())
+
+		s, err = NewFileStore(path, "hash2")
+		Expect(err).NotTo(HaveOccurred())
+		_, ok := s.Load("source")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("invalid file", func() {
+		Expect(os.WriteFile(path, []byte("{"), 0o600)).NotTo(HaveOccurred())
+		s, err := NewFileStore(path, "hash")
+		Expect(stderrors.Is(err, errors.ErrInvalidCheckpoint)).To(BeTrue())
+		Expect(s).To(BeNil())
+	})
+
+	It("empty file", func() {
+		Expect(os.WriteFile(path, nil, 0o600)).NotTo(HaveOccurred())
+		s, err := NewFileStore(path, "hash")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s).NotTo(BeNil())
+	})
+
+	It("write failed", func() {
+		s, err := NewFileStore(filepath.Join(tmpdir, "not-exists", "checkpoint.json"), "hash", WithFlushInterval(0))
+		Expect(err).NotTo(HaveOccurred())
+		err = s.Save("source", Checkpoint{Offset: 10, Records: 1})
+		Expect(err).To(HaveOccurred())
+	})
+})
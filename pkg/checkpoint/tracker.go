@@ -0,0 +1,88 @@
+package checkpoint
+
+import (
+	"sync"
+)
+
+type (
+	// Tracker tracks the batches of a source, which may finish out of order in the importer pool.
+	// Only the contiguous prefix of the succeeded batches is committed to the store,
+	// and nothing after a failed batch is committed.
+	Tracker struct {
+		store     Store
+		key       string
+		mu        sync.Mutex
+		committed Checkpoint
+		base      int64
+		next      int64
+		batches   []trackedBatch
+		isFailed  bool
+	}
+
+	trackedBatch struct {
+		nBytes   int64
+		nRecords int64
+		isDone   bool
+	}
+)
+
+func NewTracker(store Store, key string, committed Checkpoint) *Tracker {
+	return &Tracker{
+		store:     store,
+		key:       key,
+		committed: committed,
+	}
+}
+
+// Committed returns the checkpoint committed so far.
+func (t *Tracker) Committed() Checkpoint {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.committed
+}
+
+// Add adds a batch in reading order, and returns its sequence.
+func (t *Tracker) Add(nBytes, nRecords int64) int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	seq := t.next
+	t.next++
+	if !t.isFailed {
+		t.batches = append(t.batches, trackedBatch{
+			nBytes:   nBytes,
+			nRecords: nRecords,
+		})
+	}
+	return seq
+}
+
+// Done marks the batch of the sequence finished, and saves the checkpoint if the committed prefix grows.
+func (t *Tracker) Done(seq int64, isSucceeded bool) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	idx := seq - t.base
+	if idx < 0 || idx >= int64(len(t.batches)) {
+		return nil
+	}
+	if !isSucceeded {
+		// The batches from the failed one can never be committed, but the ones before still can.
+		t.batches = t.batches[:idx]
+		t.isFailed = true
+		return nil
+	}
+	t.batches[idx].isDone = true
+
+	n := 0
+	for n < len(t.batches) && t.batches[n].isDone {
+		t.committed.Offset += t.batches[n].nBytes
+		t.committed.Records += t.batches[n].nRecords
+		n++
+	}
+	if n == 0 {
+		return nil
+	}
+	t.batches = t.batches[n:]
+	t.base += int64(n)
+	return t.store.Save(t.key, t.committed)
+}
@@ -0,0 +1,82 @@
+package checkpoint
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tracker", func() {
+	var (
+		tmpdir string
+		store  Store
+	)
+	BeforeEach(func() {
+		var err error
+		tmpdir, err = os.MkdirTemp("", "test")
+		Expect(err).NotTo(HaveOccurred())
+		store, err = NewFileStore(filepath.Join(tmpdir, "checkpoint.json"), "hash", WithFlushInterval(0))
+		Expect(err).NotTo(HaveOccurred())
+	})
+	AfterEach(func() {
+		err := os.RemoveAll(tmpdir)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("in order", func() {
+		t := NewTracker(store, "source", Checkpoint{Offset: 100, Records: 10})
+		seq0 := t.Add(10, 1)
+		seq1 := t.Add(20, 2)
+		Expect(t.Done(seq0, true)).NotTo(HaveOccurred())
+		Expect(t.Committed()).To(Equal(Checkpoint{Offset: 110, Records: 11}))
+		Expect(t.Done(seq1, true)).NotTo(HaveOccurred())
+		Expect(t.Committed()).To(Equal(Checkpoint{Offset: 130, Records: 13}))
+
+		cp, ok := store.Load("source")
+		Expect(ok).To(BeTrue())
+		Expect(cp).To(Equal(Checkpoint{Offset: 130, Records: 13}))
+	})
+
+	It("out of order", func() {
+		t := NewTracker(store, "source", Checkpoint{})
+		seq0 := t.Add(10, 1)
+		seq1 := t.Add(20, 2)
+		seq2 := t.Add(30, 3)
+
+		Expect(t.Done(seq2, true)).NotTo(HaveOccurred())
+		Expect(t.Committed()).To(Equal(Checkpoint{}))
+		_, ok := store.Load("source")
+		Expect(ok).To(BeFalse())
+
+		Expect(t.Done(seq1, true)).NotTo(HaveOccurred())
+		Expect(t.Committed()).To(Equal(Checkpoint{}))
+
+		Expect(t.Done(seq0, true)).NotTo(HaveOccurred())
+		Expect(t.Committed()).To(Equal(Checkpoint{Offset: 60, Records: 6}))
+		cp, ok := store.Load("source")
+		Expect(ok).To(BeTrue())
+		Expect(cp).To(Equal(Checkpoint{Offset: 60, Records: 6}))
+
+		// unknown sequence
+		Expect(t.Done(seq0, true)).NotTo(HaveOccurred())
+		Expect(t.Done(100, true)).NotTo(HaveOccurred())
+		Expect(t.Committed()).To(Equal(Checkpoint{Offset: 60, Records: 6}))
+	})
+
+	It("failed", func() {
+		t := NewTracker(store, "source", Checkpoint{})
+		seq0 := t.Add(10, 1)
+		seq1 := t.Add(20, 2)
+		seq2 := t.Add(30, 3)
+
+		Expect(t.Done(seq1, false)).NotTo(HaveOccurred())
+		Expect(t.Done(seq2, true)).NotTo(HaveOccurred())
+		Expect(t.Committed()).To(Equal(Checkpoint{}))
+		Expect(t.Done(seq0, true)).NotTo(HaveOccurred())
+		seq3 := t.Add(40, 4)
+		Expect(t.Done(seq3, true)).NotTo(HaveOccurred())
+		Expect(t.Committed()).To(Equal(Checkpoint{Offset: 10, Records: 1}))
+	})
+})
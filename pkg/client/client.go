@@ -2,6 +2,7 @@
 package client
 
 import (
+	"context"
 	"strconv"
 	"strings"
 
@@ -92,6 +93,10 @@ func (c *defaultClient) Execute(statement string) (Response, error) {
 	// * Case 2. retry as much as possible
 	// * Case 3: retry with limit times
 	_ = backoff.Retry(func() error {
+		// Each attempt is limited, so that the retries do not overload the graphd.
+		// The statement is waiting for the result, so it's executed even if the pool is closing.
+		_ = c.rateLimiter.WaitN(context.Background(), 1)
+		_ = c.addressLimiter.WaitN(context.Background(), 1)
 		resp, err = c.session.Execute(statement)
 		if err == nil && resp.IsSucceed() {
 			return nil
@@ -108,6 +113,9 @@ func (c *defaultClient) Execute(statement string) (Response, error) {
 
 			// Case 2. retry as much as possible
 			if resp.IsRetryMoreError() {
+				if c.retryMoreObserver != nil {
+					c.retryMoreObserver()
+				}
 				retry = c.retry
 				return retryErr
 			}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/ratelimit"
 
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
@@ -179,6 +180,27 @@ var _ = Describe("Clientxxx", func() {
 			Expect(resp.IsSucceed()).To(BeTrue())
 		})
 
+		It("retry more observer", func() {
+			var observed int
+			WithRetryMoreObserver(func() {
+				observed++
+			})(c.(*defaultClient).options)
+
+			mockSession.EXPECT().Execute("test Execute statement").Times(3).Return(mockResponse, nil)
+			gomock.InOrder(
+				mockResponse.EXPECT().IsSucceed().Times(2).Return(false),
+				mockResponse.EXPECT().IsSucceed().Times(1).Return(true),
+			)
+			mockResponse.EXPECT().GetError().Times(2).Return(stderrors.New("test error"))
+			mockResponse.EXPECT().IsPermanentError().Times(2).Return(false)
+			mockResponse.EXPECT().IsRetryMoreError().Times(2).Return(true)
+
+			resp, err := c.Execute("test Execute statement")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).NotTo(BeNil())
+			Expect(observed).To(Equal(2))
+		})
+
 		It("retry case3", func() {
 			// * Case 3: retry with limit times
 			mockSession.EXPECT().Execute("test Execute statement").Times(DefaultRetry+1).Return(nil, stderrors.New("execute failed"))
@@ -188,6 +210,20 @@ var _ = Describe("Clientxxx", func() {
 			Expect(resp).To(BeNil())
 		})
 
+		It("rate limiters", func() {
+			c.(*defaultClient).retry = 30
+			c.(*defaultClient).rateLimiter = ratelimit.New(20)
+			c.(*defaultClient).addressLimiter = ratelimit.New(40)
+			mockSession.EXPECT().Execute("test Execute statement").Times(31).Return(nil, stderrors.New("execute failed"))
+
+			start := time.Now()
+			resp, err := c.Execute("test Execute statement")
+			Expect(err).To(HaveOccurred())
+			Expect(resp).To(BeNil())
+			// The first 20 attempts are the burst, and the retries after them are allowed in 550ms.
+			Expect(time.Since(start)).To(BeNumerically(">=", 400*time.Millisecond))
+		})
+
 		It("successfully", func() {
 			mockSession.EXPECT().Execute("test Execute statement").Times(1).Return(mockResponse, nil)
 			mockResponse.EXPECT().IsSucceed().Times(1).Return(true)
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/logger"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/ratelimit"
 )
 
 const (
@@ -26,6 +27,9 @@ const (
 type (
 	Option func(*options)
 
+	// ExecuteObserver is called after each statement executed by the pool, with the graphd address of the client.
+	ExecuteObserver func(address string, resp Response, err error)
+
 	options struct {
 		// for client
 		addresses            []string
@@ -35,12 +39,19 @@ type (
 		retry                int
 		retryInitialInterval time.Duration
 		logger               logger.Logger
+		retryMoreObserver    func()
 		fnNewSession         NewSessionFunc
 		clientInitFunc       func(Client) error
+		// rateLimiter and addressLimiter limit each attempt of the statements,
+		// addressLimiter is the limiter of the address set by the pool.
+		rateLimiter    *ratelimit.Limiter
+		addressLimiter *ratelimit.Limiter
 		// for pool
 		reconnectInitialInterval time.Duration
 		concurrencyPerAddress    int
 		queueSize                int
+		executeObserver          ExecuteObserver
+		addressRateLimiter       *ratelimit.Group
 		fnNewClientWithOptions   func(o *options) Client // for convenience of testing in Pool
 	}
 )
@@ -148,6 +159,35 @@ func WithQueueSize(queueSize int) Option {
 	}
 }
 
+// WithRetryMoreObserver is called each time the graphd asks to retry more, such as the raft buffer is full,
+// which means the cluster is overloaded.
+func WithRetryMoreObserver(fn func()) Option {
+	return func(o *options) {
+		o.retryMoreObserver = fn
+	}
+}
+
+// WithExecuteObserver observes the statements executed by the pool, such as for the metrics.
+func WithExecuteObserver(fn ExecuteObserver) Option {
+	return func(o *options) {
+		o.executeObserver = fn
+	}
+}
+
+// WithRateLimiter limits the statements executed by the clients in total, including the retries.
+func WithRateLimiter(l *ratelimit.Limiter) Option {
+	return func(o *options) {
+		o.rateLimiter = l
+	}
+}
+
+// WithAddressRateLimiter limits the statements executed by the pool on each graphd address.
+func WithAddressRateLimiter(g *ratelimit.Group) Option {
+	return func(o *options) {
+		o.addressRateLimiter = g
+	}
+}
+
 func newOptions(opts ...Option) *options {
 	defaultOptions := &options{
 		user:                     DefaultUser,
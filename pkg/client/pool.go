@@ -18,7 +18,7 @@ type (
 
 	defaultPool struct {
 		*options
-		chExecuteDataQueue chan executeData
+		queue              *executeQueue
 		lock               sync.RWMutex
 		closed             bool
 		done               chan struct{}
@@ -29,6 +29,8 @@ type (
 	NewSessionFunc func(HostAddress) Session
 
 	executeData struct {
+		// space is the graph space to execute the statement in, empty means the current space of the session.
+		space     string
 		statement string
 		ch        chan<- ExecuteResult
 	}
@@ -45,7 +47,7 @@ func NewPool(opts ...Option) Pool {
 		done:    make(chan struct{}),
 	}
 
-	p.chExecuteDataQueue = make(chan executeData, p.queueSize)
+	p.queue = newExecuteQueue(p.queueSize)
 
 	return p
 }
@@ -77,6 +79,10 @@ func (p *defaultPool) Open() error {
 }
 
 func (p *defaultPool) Execute(statement string) (Response, error) {
+	return p.ExecuteInSpace("", statement)
+}
+
+func (p *defaultPool) ExecuteInSpace(space, statement string) (Response, error) {
 	if p.IsClosed() {
 		return nil, ErrClosed
 	}
@@ -85,15 +91,22 @@ func (p *defaultPool) Execute(statement string) (Response, error) {
 
 	ch := make(chan ExecuteResult, 1)
 	data := executeData{
+		space:     space,
 		statement: statement,
 		ch:        ch,
 	}
-	p.chExecuteDataQueue <- data
+	if !p.queue.push(data, true) {
+		return nil, ErrClosed
+	}
 	result := <-ch
 	return result.Response, result.Err
 }
 
 func (p *defaultPool) ExecuteChan(statement string) (<-chan ExecuteResult, bool) {
+	return p.ExecuteChanInSpace("", statement)
+}
+
+func (p *defaultPool) ExecuteChanInSpace(space, statement string) (<-chan ExecuteResult, bool) {
 	if p.IsClosed() {
 		return nil, false
 	}
@@ -102,15 +115,14 @@ func (p *defaultPool) ExecuteChan(statement string) (<-chan ExecuteResult, bool)
 
 	ch := make(chan ExecuteResult, 1)
 	data := executeData{
+		space:     space,
 		statement: statement,
 		ch:        ch,
 	}
-	select {
-	case p.chExecuteDataQueue <- data:
-		return ch, true
-	default:
+	if !p.queue.push(data, false) {
 		return nil, false
 	}
+	return ch, true
 }
 
 func (p *defaultPool) Close() error {
@@ -120,8 +132,8 @@ func (p *defaultPool) Close() error {
 
 	p.wgStatementExecute.Wait()
 	close(p.done)
+	p.queue.close()
 	p.wgSession.Wait()
-	close(p.chExecuteDataQueue)
 	return nil
 }
 
@@ -169,7 +181,7 @@ func (p *defaultPool) worker(address string) {
 			}, exp)
 
 			if err == nil {
-				p.loop(c)
+				p.loop(address, c)
 			}
 		}
 	}
@@ -178,6 +190,7 @@ func (p *defaultPool) worker(address string) {
 func (p *defaultPool) openClient(address string, opts ...Option) (Client, error) {
 	cloneOptions := p.options.clone()
 	cloneOptions.addresses = []string{address}
+	cloneOptions.addressLimiter = p.addressRateLimiter.Get(address)
 	cloneOptions.withOptions(opts...)
 
 	c := p.fnNewClientWithOptions(cloneOptions)
@@ -188,23 +201,36 @@ func (p *defaultPool) openClient(address string, opts ...Option) (Client, error)
 	return c, nil
 }
 
-func (p *defaultPool) loop(c Client) {
+func (p *defaultPool) loop(address string, c Client) {
 	defer func() {
 		_ = c.Close()
 	}()
+	// space is the graph space which the session is switched to, empty means the one of the client init func.
+	var space string
 	for {
-		select {
-		case data, ok := <-p.chExecuteDataQueue:
-			if !ok {
+		// The statements in the space of the session are preferred, see executeQueue.
+		data, ok := p.queue.pop(space)
+		if !ok {
+			<-p.done
+			return
+		}
+		if data.space != "" && data.space != space {
+			if resp, err := useSpace(c, data.space); err != nil || !resp.IsSucceed() {
+				data.ch <- ExecuteResult{
+					Response: resp,
+					Err:      err,
+				}
 				continue
 			}
-			resp, err := c.Execute(data.statement)
-			data.ch <- ExecuteResult{
-				Response: resp,
-				Err:      err,
-			}
-		case <-p.done:
-			return
+			space = data.space
+		}
+		resp, err := c.Execute(data.statement)
+		if p.executeObserver != nil {
+			p.executeObserver(address, resp, err)
+		}
+		data.ch <- ExecuteResult{
+			Response: resp,
+			Err:      err,
 		}
 	}
 }
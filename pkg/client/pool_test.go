@@ -1,12 +1,14 @@
 package client
 
 import (
+	"context"
 	stderrors "errors"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/ratelimit"
 
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
@@ -21,7 +23,7 @@ var _ = Describe("Pool", func() {
 		Expect(p1).NotTo(BeNil())
 		Expect(p1.addresses).To(Equal([]string{"127.0.0.1:9669"}))
 		Expect(p1.done).NotTo(BeNil())
-		Expect(p1.chExecuteDataQueue).NotTo(BeNil())
+		Expect(p1.queue).NotTo(BeNil())
 	})
 
 	Describe(".GetClient", func() {
@@ -292,7 +294,7 @@ var _ = Describe("Pool", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
-		It("chExecuteDataQueue closed", func() {
+		It("queue closed", func() {
 			addresses := []string{"127.0.0.1:9669", "127.0.0.2:9669"}
 			pool := NewPool(
 				WithAddress(addresses...),
@@ -321,7 +323,7 @@ var _ = Describe("Pool", func() {
 			Expect(err).NotTo(HaveOccurred())
 
 			pool1 := pool.(*defaultPool)
-			close(pool1.chExecuteDataQueue)
+			pool1.queue.close()
 
 			wg.Wait()
 
@@ -394,5 +396,214 @@ var _ = Describe("Pool", func() {
 			err = pool.Close()
 			Expect(err).NotTo(HaveOccurred())
 		})
+
+		It("execute observer", func() {
+			var (
+				mu       sync.Mutex
+				observed = map[string]int{}
+			)
+			addresses := []string{"127.0.0.1:9669", "127.0.0.2:9669"}
+			pool := NewPool(
+				WithAddress(addresses...),
+				WithConcurrencyPerAddress(1),
+				WithExecuteObserver(func(address string, resp Response, err error) {
+					defer GinkgoRecover()
+					Expect(resp).NotTo(BeNil())
+					Expect(err).NotTo(HaveOccurred())
+					mu.Lock()
+					observed[address]++
+					mu.Unlock()
+				}),
+				func(o *options) {
+					o.fnNewClientWithOptions = func(o *options) Client {
+						return mockClient
+					}
+				},
+			)
+
+			var wg sync.WaitGroup
+			// 1 for check and 1 for concurrency per address
+			wg.Add(2 * len(addresses))
+			mockClient.EXPECT().Open().Times(2 * len(addresses)).DoAndReturn(func() error {
+				defer wg.Done()
+				return nil
+			})
+			mockClient.EXPECT().Execute("test Execute statement").Times(10).Return(mockResponse, nil)
+			mockClient.EXPECT().Close().Times(2 * len(addresses)).Return(nil)
+
+			Expect(pool.Open()).NotTo(HaveOccurred())
+			for i := 0; i < 10; i++ {
+				_, err := pool.Execute("test Execute statement")
+				Expect(err).NotTo(HaveOccurred())
+			}
+			wg.Wait()
+			Expect(pool.Close()).NotTo(HaveOccurred())
+
+			mu.Lock()
+			defer mu.Unlock()
+			Expect(observed[addresses[0]] + observed[addresses[1]]).To(Equal(10))
+			for address := range observed {
+				Expect(addresses).To(ContainElement(address))
+			}
+		})
+
+		It("address rate limiter", func() {
+			pool := NewPool(
+				WithAddress("127.0.0.1:9669"),
+				WithConcurrencyPerAddress(2),
+				WithAddressRateLimiter(ratelimit.NewGroup(20)),
+				func(o *options) {
+					o.fnNewClientWithOptions = func(o *options) Client {
+						// The limiter of the address is passed to the client, which limits the retries too.
+						Expect(o.addressLimiter).NotTo(BeNil())
+						Expect(o.addressLimiter.Limit()).To(Equal(20.0))
+						limiter := o.addressLimiter
+						mockClient.EXPECT().Execute("test Execute statement").AnyTimes().DoAndReturn(func(string) (Response, error) {
+							_ = limiter.WaitN(context.Background(), 1)
+							return mockResponse, nil
+						})
+						return mockClient
+					}
+				},
+			)
+
+			var wg sync.WaitGroup
+			// 1 for check and 2 for concurrency per address
+			wg.Add(3)
+			mockClient.EXPECT().Open().Times(3).DoAndReturn(func() error {
+				defer wg.Done()
+				return nil
+			})
+			mockClient.EXPECT().Close().Times(3).Return(nil)
+
+			Expect(pool.Open()).NotTo(HaveOccurred())
+			start := time.Now()
+			for i := 0; i < 30; i++ {
+				_, err := pool.Execute("test Execute statement")
+				Expect(err).NotTo(HaveOccurred())
+			}
+			// The first 20 are the burst, and the others are allowed in 500ms.
+			Expect(time.Since(start)).To(BeNumerically(">=", 400*time.Millisecond))
+			wg.Wait()
+			Expect(pool.Close()).NotTo(HaveOccurred())
+		})
+		It("execute in space", func() {
+			pool := NewPool(
+				WithAddress("127.0.0.1:9669"),
+				WithConcurrencyPerAddress(1),
+				func(o *options) {
+					o.fnNewClientWithOptions = func(o *options) Client {
+						return mockClient
+					}
+				},
+			)
+
+			var wg sync.WaitGroup
+			// 1 for check and 1 for concurrency per address
+			wg.Add(2)
+			mockClient.EXPECT().Open().Times(2).DoAndReturn(func() error {
+				defer wg.Done()
+				return nil
+			})
+			failedResponse := NewMockResponse(ctrl)
+			gomock.InOrder(
+				mockClient.EXPECT().Execute("USE `s1`").Return(mockResponse, nil),
+				mockClient.EXPECT().Execute("statement 1").Times(2).Return(mockResponse, nil),
+				mockClient.EXPECT().Execute("statement 0").Return(mockResponse, nil),
+				mockClient.EXPECT().Execute("USE `s2`").Return(mockResponse, nil),
+				mockClient.EXPECT().Execute("statement 2").Return(mockResponse, nil),
+				mockClient.EXPECT().Execute("USE `s3`").Return(failedResponse, nil),
+				mockClient.EXPECT().Execute("USE `s1`").Return(nil, stderrors.New("test error")),
+			)
+			mockResponse.EXPECT().IsSucceed().Times(2).Return(true)
+			failedResponse.EXPECT().IsSucceed().Return(false)
+			mockClient.EXPECT().Close().Times(2).Return(nil)
+
+			Expect(pool.Open()).NotTo(HaveOccurred())
+			p1, p2, p3 := NewSpacePool(pool, "s1"), NewSpacePool(pool, "s2"), NewSpacePool(pool, "s3")
+			Expect(NewSpacePool(pool, "")).To(Equal(pool))
+			mockPool := NewMockPool(ctrl)
+			Expect(NewSpacePool(mockPool, "s1")).To(Equal(mockPool))
+
+			resp, err := p1.Execute("statement 1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(Equal(mockResponse))
+			chExecuteResult, ok := p1.ExecuteChan("statement 1")
+			Expect(ok).To(BeTrue())
+			Expect((<-chExecuteResult).Err).NotTo(HaveOccurred())
+			// The statements without space are executed in the current space.
+			_, err = pool.Execute("statement 0")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = p2.Execute("statement 2")
+			Expect(err).NotTo(HaveOccurred())
+			// The statements are not executed if failed to switch the space.
+			resp, err = p3.Execute("statement 3")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(Equal(failedResponse))
+			_, err = p1.Execute("statement 1")
+			Expect(err).To(HaveOccurred())
+
+			wg.Wait()
+			Expect(pool.Close()).NotTo(HaveOccurred())
+		})
+
+		It("prefer the statements in the space of the session", func() {
+			pool := NewPool(
+				WithAddress("127.0.0.1:9669"),
+				WithConcurrencyPerAddress(1),
+				func(o *options) {
+					o.fnNewClientWithOptions = func(o *options) Client {
+						return mockClient
+					}
+				},
+			)
+
+			var (
+				wg       sync.WaitGroup
+				received = make(chan struct{})
+				release  = make(chan struct{})
+			)
+			// 1 for check and 1 for concurrency per address
+			wg.Add(2)
+			mockClient.EXPECT().Open().Times(2).DoAndReturn(func() error {
+				defer wg.Done()
+				return nil
+			})
+			gomock.InOrder(
+				mockClient.EXPECT().Execute("USE `s1`").Return(mockResponse, nil),
+				mockClient.EXPECT().Execute("statement 1").DoAndReturn(func(string) (Response, error) {
+					close(received)
+					<-release
+					return mockResponse, nil
+				}),
+				// The statements of s1 are executed before the one of s2 queued ahead.
+				mockClient.EXPECT().Execute("statement 1").Times(2).Return(mockResponse, nil),
+				mockClient.EXPECT().Execute("USE `s2`").Return(mockResponse, nil),
+				mockClient.EXPECT().Execute("statement 2").Return(mockResponse, nil),
+			)
+			mockResponse.EXPECT().IsSucceed().Times(2).Return(true)
+			mockClient.EXPECT().Close().Times(2).Return(nil)
+
+			Expect(pool.Open()).NotTo(HaveOccurred())
+			p1, p2 := NewSpacePool(pool, "s1"), NewSpacePool(pool, "s2")
+
+			ch, ok := p1.ExecuteChan("statement 1")
+			Expect(ok).To(BeTrue())
+			<-received
+			ch2, ok := p2.ExecuteChan("statement 2")
+			Expect(ok).To(BeTrue())
+			ch11, ok := p1.ExecuteChan("statement 1")
+			Expect(ok).To(BeTrue())
+			ch12, ok := p1.ExecuteChan("statement 1")
+			Expect(ok).To(BeTrue())
+			close(release)
+			Expect((<-ch).Err).NotTo(HaveOccurred())
+			for _, ch := range []<-chan ExecuteResult{ch11, ch12, ch2} {
+				Expect((<-ch).Err).NotTo(HaveOccurred())
+			}
+
+			wg.Wait()
+			Expect(pool.Close()).NotTo(HaveOccurred())
+		})
 	})
 })
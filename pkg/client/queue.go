@@ -0,0 +1,98 @@
+package client
+
+import "sync"
+
+// maxHeadSkips is the max times the head of the queue is skipped for the statements in the space of the workers,
+// so that the statements of the other spaces are not starved.
+const maxHeadSkips = 16
+
+// executeQueue is the queue of the statements to execute. The workers prefer the statements in the space
+// which their sessions are switched to, so that the sessions are not switched back and forth between the spaces.
+type executeQueue struct {
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	items    []executeData
+	size     int
+	skipped  int // the times the head is skipped in a row
+	closed   bool
+}
+
+func newExecuteQueue(size int) *executeQueue {
+	q := &executeQueue{
+		items: make([]executeData, 0, size),
+		size:  size,
+	}
+	q.notEmpty = sync.NewCond(&q.mu)
+	q.notFull = sync.NewCond(&q.mu)
+	return q
+}
+
+// push appends the statement to the queue, it returns false if the queue is closed,
+// or the queue is full and block is false.
+func (q *executeQueue) push(data executeData, block bool) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for len(q.items) >= q.size && !q.closed {
+		if !block {
+			return false
+		}
+		q.notFull.Wait()
+	}
+	if q.closed {
+		return false
+	}
+	q.items = append(q.items, data)
+	q.notEmpty.Signal()
+	return true
+}
+
+// pop waits for a statement, the first one in the space or without space is preferred to the head,
+// unless the head is skipped maxHeadSkips times. It returns false if the queue is closed.
+func (q *executeQueue) pop(space string) (executeData, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for len(q.items) == 0 && !q.closed {
+		q.notEmpty.Wait()
+	}
+	if q.closed {
+		return executeData{}, false
+	}
+
+	index := 0
+	if head := q.items[0]; head.space != "" && head.space != space && q.skipped < maxHeadSkips {
+		for i := 1; i < len(q.items); i++ {
+			if s := q.items[i].space; s == "" || s == space {
+				index = i
+				break
+			}
+		}
+	}
+	if index == 0 {
+		q.skipped = 0
+	} else {
+		q.skipped++
+	}
+
+	data := q.items[index]
+	copy(q.items[index:], q.items[index+1:])
+	q.items[len(q.items)-1] = executeData{}
+	q.items = q.items[:len(q.items)-1]
+	q.notFull.Signal()
+	return data, true
+}
+
+// close wakes up all the waiting pushes and pops, the statements left fail with ErrClosed.
+func (q *executeQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	for _, data := range q.items {
+		data.ch <- ExecuteResult{Err: ErrClosed}
+	}
+	q.items = nil
+	q.notEmpty.Broadcast()
+	q.notFull.Broadcast()
+}
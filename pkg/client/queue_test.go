@@ -0,0 +1,66 @@
+package client
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("executeQueue", func() {
+	newData := func(space, statement string) executeData {
+		return executeData{space: space, statement: statement, ch: make(chan ExecuteResult, 1)}
+	}
+	popStatements := func(q *executeQueue, space string, n int) []string {
+		statements := make([]string, 0, n)
+		for i := 0; i < n; i++ {
+			data, ok := q.pop(space)
+			Expect(ok).To(BeTrue())
+			statements = append(statements, data.statement)
+		}
+		return statements
+	}
+
+	It("prefer the space", func() {
+		q := newExecuteQueue(10)
+		for _, data := range []executeData{
+			newData("s2", "a"),
+			newData("s1", "b"),
+			newData("", "c"),
+			newData("s1", "d"),
+		} {
+			Expect(q.push(data, false)).To(BeTrue())
+		}
+		Expect(popStatements(q, "s1", 2)).To(Equal([]string{"b", "c"}))
+		Expect(popStatements(q, "s3", 1)).To(Equal([]string{"a"}))
+		Expect(popStatements(q, "s3", 1)).To(Equal([]string{"d"}))
+	})
+
+	It("the head is not starved", func() {
+		q := newExecuteQueue(maxHeadSkips + 2)
+		Expect(q.push(newData("s2", "head"), false)).To(BeTrue())
+		for i := 0; i < maxHeadSkips+1; i++ {
+			Expect(q.push(newData("s1", "s1"), false)).To(BeTrue())
+		}
+		statements := popStatements(q, "s1", maxHeadSkips+1)
+		Expect(statements[:maxHeadSkips]).To(HaveEach("s1"))
+		Expect(statements[maxHeadSkips]).To(Equal("head"))
+	})
+
+	It("full and closed", func() {
+		q := newExecuteQueue(1)
+		ch := make(chan ExecuteResult, 1)
+		Expect(q.push(executeData{statement: "a", ch: ch}, false)).To(BeTrue())
+		Expect(q.push(newData("", "b"), false)).To(BeFalse())
+
+		chPushed := make(chan bool, 1)
+		go func() {
+			chPushed <- q.push(newData("", "c"), true)
+		}()
+		Consistently(chPushed).ShouldNot(Receive())
+
+		q.close()
+		Eventually(chPushed).Should(Receive(BeFalse()))
+		Expect(<-ch).To(Equal(ExecuteResult{Err: ErrClosed}))
+		_, ok := q.pop("")
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -12,4 +12,8 @@ type Response interface {
 	GetError() error
 	IsPermanentError() bool
 	IsRetryMoreError() bool
+	// GetColNames returns the column names of the result.
+	GetColNames() []string
+	// GetRowValues returns the values of the result rows, the strings are unquoted and the nulls are empty.
+	GetRowValues() ([][]string, error)
 }
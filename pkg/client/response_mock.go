@@ -34,6 +34,20 @@ func (m *MockResponse) EXPECT() *MockResponseMockRecorder {
 	return m.recorder
 }
 
+// GetColNames mocks base method.
+func (m *MockResponse) GetColNames() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetColNames")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// GetColNames indicates an expected call of GetColNames.
+func (mr *MockResponseMockRecorder) GetColNames() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetColNames", reflect.TypeOf((*MockResponse)(nil).GetColNames))
+}
+
 // GetError mocks base method.
 func (m *MockResponse) GetError() error {
 	m.ctrl.T.Helper()
@@ -76,6 +90,21 @@ func (mr *MockResponseMockRecorder) GetRespTime() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRespTime", reflect.TypeOf((*MockResponse)(nil).GetRespTime))
 }
 
+// GetRowValues mocks base method.
+func (m *MockResponse) GetRowValues() ([][]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRowValues")
+	ret0, _ := ret[0].([][]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRowValues indicates an expected call of GetRowValues.
+func (mr *MockResponseMockRecorder) GetRowValues() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRowValues", reflect.TypeOf((*MockResponse)(nil).GetRowValues))
+}
+
 // IsPermanentError mocks base method.
 func (m *MockResponse) IsPermanentError() bool {
 	m.ctrl.T.Helper()
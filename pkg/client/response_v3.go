@@ -53,3 +53,32 @@ func (resp defaultResponseV3) IsRetryMoreError() bool {
 	// Can not get the E_RAFT_BUFFER_OVERFLOW inside storage now.
 	return strings.Contains(errorMsg, "raft buffer is full")
 }
+
+func (resp defaultResponseV3) GetRowValues() ([][]string, error) {
+	n := resp.ResultSet.GetRowSize()
+	rows := make([][]string, 0, n)
+	for i := 0; i < n; i++ {
+		record, err := resp.ResultSet.GetRowValuesByIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		row := make([]string, 0, resp.ResultSet.GetColSize())
+		for j := 0; j < resp.ResultSet.GetColSize(); j++ {
+			val, err := record.GetValueByIndex(j)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case val.IsNull():
+				row = append(row, "")
+			case val.IsString():
+				s, _ := val.AsString()
+				row = append(row, s)
+			default:
+				row = append(row, val.String())
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
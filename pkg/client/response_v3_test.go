@@ -3,6 +3,7 @@
 package client
 
 import (
+	stderrors "errors"
 	"time"
 
 	nebula "github.com/vesoft-inc/nebula-go/v3"
@@ -75,4 +76,23 @@ var _ = Describe("defaultResponseV3", func() {
 		Entry(nil, "x raft buffer is full x", true),
 		Entry(nil, "x x", false),
 	)
+
+	It("GetRowValues", func() {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+
+		rs := nebula.ResultSet{}
+		resp := newResponseV3(&rs, time.Second)
+
+		patches.ApplyMethodReturn(rs, "GetRowSize", 0)
+		rows, err := resp.GetRowValues()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rows).To(BeEmpty())
+
+		patches.ApplyMethodReturn(rs, "GetRowSize", 1)
+		patches.ApplyMethodReturn(rs, "GetRowValuesByIndex", nil, stderrors.New("test error"))
+		rows, err = resp.GetRowValues()
+		Expect(err).To(HaveOccurred())
+		Expect(rows).To(BeNil())
+	})
 })
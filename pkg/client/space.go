@@ -0,0 +1,52 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/utils"
+)
+
+var (
+	_ SpaceExecutor = (*defaultPool)(nil)
+	_ Pool          = (*spacePool)(nil)
+)
+
+type (
+	// SpaceExecutor executes the statements in the graph spaces, the sessions are switched to the space if needed.
+	SpaceExecutor interface {
+		ExecuteInSpace(space, statement string) (Response, error)
+		ExecuteChanInSpace(space, statement string) (<-chan ExecuteResult, bool)
+	}
+
+	spacePool struct {
+		Pool
+		executor SpaceExecutor
+		space    string
+	}
+)
+
+// NewSpacePool returns the pool which executes the statements in the graph space, so that the sessions of p are
+// shared by the spaces. It returns p if the space is empty, or p does not implement SpaceExecutor.
+func NewSpacePool(p Pool, space string) Pool {
+	executor, ok := p.(SpaceExecutor)
+	if !ok || space == "" {
+		return p
+	}
+	return &spacePool{
+		Pool:     p,
+		executor: executor,
+		space:    space,
+	}
+}
+
+func (p *spacePool) Execute(statement string) (Response, error) {
+	return p.executor.ExecuteInSpace(p.space, statement)
+}
+
+func (p *spacePool) ExecuteChan(statement string) (<-chan ExecuteResult, bool) {
+	return p.executor.ExecuteChanInSpace(p.space, statement)
+}
+
+func useSpace(c Client, space string) (Response, error) {
+	return c.Execute(fmt.Sprintf("USE %s", utils.ConvertIdentifier(space)))
+}
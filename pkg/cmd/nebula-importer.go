@@ -1,30 +1,54 @@
 package cmd
 
 import (
+	stderrors "errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/client"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/cmd/common"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/cmd/util"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/config"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/logger"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/manager"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/report"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/stats"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/version"
 
 	"github.com/spf13/cobra"
 )
 
+// The exit codes of the process, the others failures exit with 1.
+const (
+	// ExitCodeConfigError is the exit code if the config is invalid, or mismatches the schema.
+	ExitCodeConfigError = 2
+	// ExitCodeConnectionError is the exit code if the import is not started,
+	// such as failed to connect to the graphd or to execute the before hooks.
+	ExitCodeConnectionError = 3
+	// ExitCodePartialFailure is the exit code if the import finished, but some records failed,
+	// or some sources are skipped since their dependencies failed.
+	ExitCodePartialFailure = 4
+	// ExitCodeAborted is the exit code if the import is aborted since the failures exceed the thresholds.
+	ExitCodeAborted = 5
+)
+
 type (
 	ImporterOptions struct {
 		common.IOStreams
 		Arguments    []string
 		ConfigFile   string
+		Resume       bool
+		Replay       bool
+		DryRun       bool
+		ReportFile   string
 		cfg          config.Configurator
 		logger       logger.Logger
 		useNopLogger bool // for test
 		pool         client.Pool
 		mgr          manager.Manager
+		recorder     *report.Recorder
 	}
 )
 
@@ -45,39 +69,9 @@ func NewDefaultImporterCommand() *cobra.Command {
 
 func NewImporterCommand(o *ImporterOptions) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "nebula-importer",
-		Short: `The NebulaGraph Importer Tool.`,
-		RunE: func(cmd *cobra.Command, args []string) (err error) {
-			defer func() {
-				if err != nil {
-					l := o.logger
-
-					if l == nil || o.useNopLogger {
-						l = logger.NopLogger
-					}
-
-					e := errors.NewImportError(err)
-					fields := logger.MapToFields(e.Fields())
-					l.SkipCaller(1).WithError(e.Cause()).Error("failed to execute", fields...)
-				}
-				if o.pool != nil {
-					_ = o.pool.Close()
-				}
-				if o.logger != nil {
-					_ = o.logger.Sync()
-					_ = o.logger.Close()
-				}
-			}()
-			err = o.Complete(cmd, args)
-			if err != nil {
-				return err
-			}
-			err = o.Validate()
-			if err != nil {
-				return err
-			}
-			return o.Run(cmd, args)
-		},
+		Use:           "nebula-importer",
+		Short:         `The NebulaGraph Importer Tool.`,
+		RunE:          o.runE,
 		Version:       version.GetVersion().String(),
 		SilenceErrors: true,
 		SilenceUsage:  true,
@@ -86,9 +80,65 @@ func NewImporterCommand(o *ImporterOptions) *cobra.Command {
 	cmd.SetVersionTemplate("{{.Version}}")
 
 	o.AddFlags(cmd)
+
+	replayOptions := NewImporterOptions(o.IOStreams)
+	replayOptions.Replay = true
+	cmd.AddCommand(NewReplayCommand(replayOptions))
+	return cmd
+}
+
+func NewReplayCommand(o *ImporterOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "replay",
+		Short:         `Re-import the dead letters of the sources with the original configuration.`,
+		RunE:          o.runE,
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+
+	cmd.Flags().StringVarP(&o.ConfigFile, "config", "c", o.ConfigFile,
+		"specify nebula-importer configure file")
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", o.DryRun,
+		"write the statements to stdout or the configured output instead of executing them")
+	cmd.Flags().StringVar(&o.ReportFile, "report", o.ReportFile,
+		"write the report of the import in JSON to the file at the end")
 	return cmd
 }
 
+func (o *ImporterOptions) runE(cmd *cobra.Command, args []string) (err error) {
+	startTime := time.Now()
+	defer func() {
+		l := o.getLogger()
+		if err != nil {
+			e := errors.NewImportError(err)
+			fields := logger.MapToFields(e.Fields())
+			l.SkipCaller(1).WithError(e.Cause()).Error("failed to execute", fields...)
+		}
+		if o.ReportFile != "" {
+			if rerr := o.writeReport(startTime, err); rerr != nil {
+				e := errors.AsOrNewImportError(rerr)
+				l.WithError(e.Cause()).Error("failed to write report", logger.MapToFields(e.Fields())...)
+			}
+		}
+		if o.pool != nil {
+			_ = o.pool.Close()
+		}
+		if o.logger != nil {
+			_ = o.logger.Sync()
+			_ = o.logger.Close()
+		}
+	}()
+	err = o.Complete(cmd, args)
+	if err != nil {
+		return util.NewExitError(err, ExitCodeConfigError)
+	}
+	err = o.Validate()
+	if err != nil {
+		return util.NewExitError(err, ExitCodeConfigError)
+	}
+	return o.Run(cmd, args)
+}
+
 func (*ImporterOptions) Complete(_ *cobra.Command, _ []string) error {
 	return nil
 }
@@ -103,7 +153,23 @@ func (o *ImporterOptions) Validate() error {
 		return err
 	}
 
-	if err = cfg.Build(); err != nil {
+	if o.Replay {
+		if err = cfg.Replay(); err != nil {
+			return err
+		}
+	}
+
+	if o.DryRun {
+		cfg.DryRun(o.Out)
+	}
+
+	opts := []manager.Option{manager.WithResume(o.Resume)}
+	if o.ReportFile != "" {
+		o.recorder = report.NewRecorder()
+		opts = append(opts, manager.WithRecorder(o.recorder))
+	}
+
+	if err = cfg.Build(opts...); err != nil {
 		return err
 	}
 
@@ -116,20 +182,72 @@ func (o *ImporterOptions) Validate() error {
 }
 
 func (o *ImporterOptions) Run(_ *cobra.Command, _ []string) error {
+	// The signals received while starting, such as during the before hooks, cancel the starting.
+	signals := o.handleSignals()
+	defer signals.Stop()
+
 	if err := o.mgr.Start(); err != nil {
-		return err
+		if sig := signals.Received(); sig != nil && stderrors.Is(err, errors.ErrInterrupted) {
+			return util.NewExitError(err, signalExitCode(sig))
+		}
+		if stderrors.Is(err, errors.ErrSchemaMismatch) {
+			return util.NewExitError(err, ExitCodeConfigError)
+		}
+		return util.NewExitError(err, ExitCodeConnectionError)
 	}
-	//revive:disable-next-line:if-return
+
 	if err := o.mgr.Wait(); err != nil {
+		if stderrors.Is(err, errors.ErrFailureThreshold) {
+			return util.NewExitError(err, ExitCodeAborted)
+		}
+		if stderrors.Is(err, errors.ErrDependencyFailed) {
+			return util.NewExitError(err, ExitCodePartialFailure)
+		}
+		if sig := signals.Received(); sig != nil && stderrors.Is(err, errors.ErrStopTimeout) {
+			return util.NewExitError(err, signalExitCode(sig))
+		}
 		return err
 	}
+	if sig := signals.Received(); sig != nil {
+		return util.NewExitError(errors.NewImportError(errors.ErrInterrupted, "received signal %s", sig), signalExitCode(sig))
+	}
 	if o.mgr.Stats().IsFailed() {
-		return fmt.Errorf("failed to import")
+		return util.NewExitError(fmt.Errorf("failed to import"), ExitCodePartialFailure)
 	}
 	return nil
 }
 
+// writeReport writes the report of the import, it's written even if the config is invalid.
+func (o *ImporterOptions) writeReport(startTime time.Time, err error) error {
+	var s *stats.Stats
+	if o.mgr != nil {
+		s = o.mgr.Stats()
+	}
+	rpt := report.New(o.recorder, s).SetConfigFile(o.ConfigFile)
+	rpt.Version = version.GetVersion().Version
+	rpt.StartTime, rpt.EndTime = startTime, time.Now()
+	rpt.Succeeded = err == nil
+	rpt.ExitCode = util.ExitCode(err)
+	if err != nil {
+		rpt.Error = err.Error()
+	}
+	return rpt.WriteFile(o.ReportFile)
+}
+
+func (o *ImporterOptions) getLogger() logger.Logger {
+	if o.logger == nil || o.useNopLogger {
+		return logger.NopLogger
+	}
+	return o.logger
+}
+
 func (o *ImporterOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.ConfigFile, "config", "c", o.ConfigFile,
 		"specify nebula-importer configure file")
+	cmd.Flags().BoolVar(&o.Resume, "resume", o.Resume,
+		"skip the records committed in the checkpoint of the last import")
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", o.DryRun,
+		"write the statements to stdout or the configured output instead of executing them")
+	cmd.Flags().StringVar(&o.ReportFile, "report", o.ReportFile,
+		"write the report of the import in JSON to the file at the end")
 }
@@ -3,13 +3,23 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
 	stderrors "errors"
 	"os"
+	"path/filepath"
+	"syscall"
 	"time"
 
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/client"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/cmd/common"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/cmd/util"
+	configbase "github.com/vesoft-inc/nebula-importer/v4/pkg/config/base"
+	configv3 "github.com/vesoft-inc/nebula-importer/v4/pkg/config/v3"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/manager"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/report"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/stats"
 
 	"github.com/agiledragon/gomonkey/v2"
 	"github.com/golang/mock/gomock"
@@ -86,6 +96,49 @@ var _ = Describe("ImporterCommand", func() {
 		Expect(err).To(HaveOccurred())
 	})
 
+	It("unsupported envelope", func() {
+		command := NewDefaultImporterCommand()
+		command.SetArgs([]string{"-c", "testdata/unsupported-envelope.yaml"})
+		err := command.Execute()
+		Expect(stderrors.Is(err, errors.ErrUnsupportedEnvelope)).To(BeTrue())
+		Expect(util.ExitCode(err)).To(Equal(ExitCodeConfigError))
+	})
+
+	It("resume without checkpoint", func() {
+		command := NewDefaultImporterCommand()
+		command.SetArgs([]string{"-c", "testdata/nebula-importer.v3.yaml", "--resume"})
+		err := command.Execute()
+		Expect(err).To(HaveOccurred())
+		Expect(stderrors.Is(err, errors.ErrNoCheckpoint)).To(BeTrue())
+	})
+
+	It("replay without dead letter", func() {
+		command := NewDefaultImporterCommand()
+		command.SetArgs([]string{"replay", "-c", "testdata/nebula-importer.v3.yaml"})
+		err := command.Execute()
+		Expect(err).To(HaveOccurred())
+		Expect(stderrors.Is(err, errors.ErrNoDeadLetter)).To(BeTrue())
+	})
+
+	It("dry run", func() {
+		out := &bytes.Buffer{}
+		o := NewImporterOptions(common.IOStreams{
+			In:     os.Stdin,
+			Out:    out,
+			ErrOut: os.Stderr,
+		})
+		o.useNopLogger = true
+		command := NewImporterCommand(o)
+		command.SetArgs([]string{"-c", "testdata/nebula-importer.v3.yaml", "--dry-run"})
+		err := command.Execute()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.String()).To(HavePrefix("USE `graphName`;\n"))
+		Expect(out.String()).To(ContainSubstring("statement1;\n"))
+		Expect(out.String()).To(ContainSubstring("INSERT VERTEX IGNORE_EXISTED_INDEX `node1`"))
+		Expect(out.String()).To(ContainSubstring("INSERT EDGE IGNORE_EXISTED_INDEX `edge2`"))
+		Expect(o.mgr.Stats().TotalRecords).To(BeNumerically(">", 0))
+	})
+
 	It("complete failed", func() {
 		o := NewImporterOptions(common.IOStreams{
 			In:     os.Stdin,
@@ -101,7 +154,8 @@ var _ = Describe("ImporterCommand", func() {
 
 		err := command.Execute()
 		Expect(err).To(HaveOccurred())
-		Expect(err).To(Equal(stderrors.New("test error")))
+		Expect(err).To(MatchError("test error"))
+		Expect(util.ExitCode(err)).To(Equal(ExitCodeConfigError))
 	})
 
 	It("manager start failed", func() {
@@ -121,7 +175,255 @@ var _ = Describe("ImporterCommand", func() {
 
 		err := command.Execute()
 		Expect(err).To(HaveOccurred())
-		Expect(err).To(Equal(stderrors.New("test error")))
+		Expect(err).To(MatchError("test error"))
+		Expect(util.ExitCode(err)).To(Equal(ExitCodeConnectionError))
+	})
+
+	It("manager start schema mismatch", func() {
+		patches.ApplyFuncReturn(manager.NewWithOpts, mockManager)
+		mockManager.EXPECT().Import(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+		mockManager.EXPECT().Start().Return(errors.NewImportError(errors.ErrSchemaMismatch))
+
+		o := NewImporterOptions(common.IOStreams{
+			In:     os.Stdin,
+			Out:    os.Stdout,
+			ErrOut: os.Stderr,
+		})
+
+		o.useNopLogger = true
+		command := NewImporterCommand(o)
+		command.SetArgs([]string{"-c", "testdata/nebula-importer.v3.yaml"})
+
+		err := command.Execute()
+		Expect(stderrors.Is(err, errors.ErrSchemaMismatch)).To(BeTrue())
+		Expect(util.ExitCode(err)).To(Equal(ExitCodeConfigError))
+	})
+
+	It("partially failed", func() {
+		patches.ApplyFuncReturn(manager.NewWithOpts, mockManager)
+		mockManager.EXPECT().Import(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+		mockManager.EXPECT().Start().Return(nil)
+		mockManager.EXPECT().Wait().Return(nil)
+		mockManager.EXPECT().Stats().Return(&stats.Stats{FailedRecords: 1, TotalRecords: 2})
+
+		o := NewImporterOptions(common.IOStreams{
+			In:     os.Stdin,
+			Out:    os.Stdout,
+			ErrOut: os.Stderr,
+		})
+
+		o.useNopLogger = true
+		command := NewImporterCommand(o)
+		command.SetArgs([]string{"-c", "testdata/nebula-importer.v3.yaml"})
+
+		err := command.Execute()
+		Expect(err).To(HaveOccurred())
+		Expect(util.ExitCode(err)).To(Equal(ExitCodePartialFailure))
+	})
+
+	It("aborted", func() {
+		patches.ApplyFuncReturn(manager.NewWithOpts, mockManager)
+		mockManager.EXPECT().Import(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+		mockManager.EXPECT().Start().Return(nil)
+		mockManager.EXPECT().Wait().Return(errors.NewImportError(errors.ErrFailureThreshold, "10 records failed"))
+
+		o := NewImporterOptions(common.IOStreams{
+			In:     os.Stdin,
+			Out:    os.Stdout,
+			ErrOut: os.Stderr,
+		})
+
+		o.useNopLogger = true
+		command := NewImporterCommand(o)
+		command.SetArgs([]string{"-c", "testdata/nebula-importer.v3.yaml"})
+
+		err := command.Execute()
+		Expect(stderrors.Is(err, errors.ErrFailureThreshold)).To(BeTrue())
+		Expect(util.ExitCode(err)).To(Equal(ExitCodeAborted))
+	})
+
+	It("dependency failed", func() {
+		patches.ApplyFuncReturn(manager.NewWithOpts, mockManager)
+		mockManager.EXPECT().Import(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+		mockManager.EXPECT().Start().Return(nil)
+		mockManager.EXPECT().Wait().Return(errors.NewImportError(errors.ErrDependencyFailed, "1 sources are skipped"))
+
+		o := NewImporterOptions(common.IOStreams{
+			In:     os.Stdin,
+			Out:    os.Stdout,
+			ErrOut: os.Stderr,
+		})
+
+		o.useNopLogger = true
+		command := NewImporterCommand(o)
+		command.SetArgs([]string{"-c", "testdata/nebula-importer.v3.yaml"})
+
+		err := command.Execute()
+		Expect(stderrors.Is(err, errors.ErrDependencyFailed)).To(BeTrue())
+		Expect(util.ExitCode(err)).To(Equal(ExitCodePartialFailure))
+	})
+
+	It("interrupted", func() {
+		var signals chan<- os.Signal
+		fnNotifyOld := fnNotify
+		defer func() {
+			fnNotify = fnNotifyOld
+		}()
+		fnNotify = func(c chan<- os.Signal, _ ...os.Signal) {
+			signals = c
+		}
+
+		stopped := make(chan struct{})
+		patches.ApplyFuncReturn(manager.NewWithOpts, mockManager)
+		mockManager.EXPECT().Import(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+		mockManager.EXPECT().Start().Return(nil)
+		mockManager.EXPECT().Wait().DoAndReturn(func() error {
+			signals <- syscall.SIGTERM
+			<-stopped
+			return nil
+		})
+		mockManager.EXPECT().Stop().DoAndReturn(func() error {
+			close(stopped)
+			return nil
+		})
+
+		o := NewImporterOptions(common.IOStreams{
+			In:     os.Stdin,
+			Out:    os.Stdout,
+			ErrOut: os.Stderr,
+		})
+
+		o.useNopLogger = true
+		command := NewImporterCommand(o)
+		command.SetArgs([]string{"-c", "testdata/nebula-importer.v3.yaml"})
+
+		err := command.Execute()
+		Expect(stderrors.Is(err, errors.ErrInterrupted)).To(BeTrue())
+		Expect(util.ExitCode(err)).To(Equal(128 + int(syscall.SIGTERM)))
+	})
+
+	It("interrupted while starting", func() {
+		var signals chan<- os.Signal
+		fnNotifyOld := fnNotify
+		defer func() {
+			fnNotify = fnNotifyOld
+		}()
+		fnNotify = func(c chan<- os.Signal, _ ...os.Signal) {
+			signals = c
+		}
+
+		stopped := make(chan struct{})
+		patches.ApplyFuncReturn(manager.NewWithOpts, mockManager)
+		mockManager.EXPECT().Import(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+		mockManager.EXPECT().Start().DoAndReturn(func() error {
+			signals <- syscall.SIGINT
+			<-stopped
+			return errors.NewImportError(errors.ErrInterrupted, "manager: stopped while starting")
+		})
+		mockManager.EXPECT().Stop().DoAndReturn(func() error {
+			close(stopped)
+			return nil
+		})
+
+		o := NewImporterOptions(common.IOStreams{
+			In:     os.Stdin,
+			Out:    os.Stdout,
+			ErrOut: os.Stderr,
+		})
+
+		o.useNopLogger = true
+		command := NewImporterCommand(o)
+		command.SetArgs([]string{"-c", "testdata/nebula-importer.v3.yaml"})
+
+		err := command.Execute()
+		Expect(stderrors.Is(err, errors.ErrInterrupted)).To(BeTrue())
+		Expect(util.ExitCode(err)).To(Equal(128 + int(syscall.SIGINT)))
+	})
+
+	It("interrupted twice", func() {
+		var signals chan<- os.Signal
+		exited := make(chan int, 1)
+		fnNotifyOld, fnExitOld := fnNotify, fnExit
+		defer func() {
+			fnNotify, fnExit = fnNotifyOld, fnExitOld
+		}()
+		fnNotify = func(c chan<- os.Signal, _ ...os.Signal) {
+			signals = c
+		}
+		fnExit = func(code int) {
+			exited <- code
+		}
+
+		patches.ApplyFuncReturn(manager.NewWithOpts, mockManager)
+		mockManager.EXPECT().Import(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+		mockManager.EXPECT().Start().Return(nil)
+		stopping := make(chan struct{})
+		mockManager.EXPECT().Wait().DoAndReturn(func() error {
+			signals <- syscall.SIGINT
+			<-stopping
+			signals <- syscall.SIGINT
+			Eventually(exited).Should(Receive(Equal(128 + int(syscall.SIGINT))))
+			return nil
+		})
+		mockManager.EXPECT().Stop().DoAndReturn(func() error {
+			close(stopping)
+			return nil
+		})
+
+		o := NewImporterOptions(common.IOStreams{
+			In:     os.Stdin,
+			Out:    os.Stdout,
+			ErrOut: os.Stderr,
+		})
+
+		o.useNopLogger = true
+		command := NewImporterCommand(o)
+		command.SetArgs([]string{"-c", "testdata/nebula-importer.v3.yaml"})
+
+		err := command.Execute()
+		Expect(util.ExitCode(err)).To(Equal(128 + int(syscall.SIGINT)))
+	})
+
+	It("reload", func() {
+		var signals chan<- os.Signal
+		fnNotifyOld := fnNotify
+		defer func() {
+			fnNotify = fnNotifyOld
+		}()
+		fnNotify = func(c chan<- os.Signal, _ ...os.Signal) {
+			signals = c
+		}
+
+		reloaded := make(chan error, 2)
+		patches.ApplyMethod(&configv3.Config{}, "Reload", func(_ *configv3.Config, c configbase.Configurator) error {
+			_, ok := c.(*configv3.Config)
+			if !ok {
+				reloaded <- stderrors.New("unexpected config")
+			}
+			reloaded <- nil
+			return nil
+		})
+		patches.ApplyFuncReturn(manager.NewWithOpts, mockManager)
+		mockManager.EXPECT().Import(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+		mockManager.EXPECT().Start().Return(nil)
+		mockManager.EXPECT().Wait().DoAndReturn(func() error {
+			signals <- syscall.SIGHUP
+			Eventually(reloaded).Should(Receive(BeNil()))
+			return nil
+		})
+		mockManager.EXPECT().Stats().Return(&stats.Stats{TotalRecords: 2})
+
+		o := NewImporterOptions(common.IOStreams{
+			In:     os.Stdin,
+			Out:    os.Stdout,
+			ErrOut: os.Stderr,
+		})
+
+		o.useNopLogger = true
+		command := NewImporterCommand(o)
+		command.SetArgs([]string{"-c", "testdata/nebula-importer.v3.yaml"})
+
+		Expect(command.Execute()).NotTo(HaveOccurred())
 	})
 
 	It("manager wait failed", func() {
@@ -144,4 +446,85 @@ var _ = Describe("ImporterCommand", func() {
 		Expect(err).To(HaveOccurred())
 		Expect(err).To(Equal(stderrors.New("test error")))
 	})
+
+	Describe("report", func() {
+		var reportFile string
+		BeforeEach(func() {
+			reportFile = filepath.Join(GinkgoT().TempDir(), "report.json")
+		})
+
+		readReport := func() *report.RunReport {
+			content, err := os.ReadFile(reportFile)
+			Expect(err).NotTo(HaveOccurred())
+			rpt := &report.RunReport{}
+			Expect(json.Unmarshal(content, rpt)).NotTo(HaveOccurred())
+			return rpt
+		}
+
+		It("successfully", func() {
+			patches.ApplyFuncReturn(client.NewPool, mockClientPool)
+
+			mockClientPool.EXPECT().GetClient(gomock.Any()).AnyTimes().Return(mockClient, nil)
+			mockClientPool.EXPECT().Open().AnyTimes().Return(nil)
+			mockClientPool.EXPECT().Execute(gomock.Any()).AnyTimes().Return(mockResponse, nil)
+			mockClientPool.EXPECT().Close().AnyTimes().Return(nil)
+
+			mockClient.EXPECT().Execute(gomock.Any()).AnyTimes().Return(mockResponse, nil)
+			mockClient.EXPECT().Close().AnyTimes().Return(nil)
+
+			mockResponse.EXPECT().IsSucceed().AnyTimes().Return(true)
+			mockResponse.EXPECT().GetLatency().AnyTimes().Return(time.Microsecond * 2)
+			mockResponse.EXPECT().GetRespTime().AnyTimes().Return(time.Microsecond * 2)
+
+			o := NewImporterOptions(common.IOStreams{
+				In:     os.Stdin,
+				Out:    os.Stdout,
+				ErrOut: os.Stderr,
+			})
+			o.useNopLogger = true
+			command := NewImporterCommand(o)
+			command.SetArgs([]string{"-c", "testdata/nebula-importer.v3.yaml", "--report", reportFile})
+			Expect(command.Execute()).NotTo(HaveOccurred())
+
+			rpt := readReport()
+			Expect(rpt.Succeeded).To(BeTrue())
+			Expect(rpt.ExitCode).To(Equal(0))
+			Expect(rpt.ConfigFile).To(Equal("testdata/nebula-importer.v3.yaml"))
+			Expect(rpt.ConfigDigest).To(HavePrefix("sha256:"))
+			Expect(rpt.EndTime).NotTo(BeTemporally("<", rpt.StartTime))
+			Expect(rpt.Total.TotalRecords).To(BeNumerically(">", 0))
+			Expect(rpt.Sources).To(HaveLen(2))
+			Expect(rpt.Targets).To(ContainElement(HaveField("Name", "node1")))
+			Expect(rpt.Failures).To(BeEmpty())
+			Expect(rpt.Hooks).To(Equal([]*report.HookOutcome{
+				{Hook: "before", Statement: "statement1", Succeeded: true},
+				{Hook: "before", Statement: "statement2", Succeeded: true},
+			}))
+		})
+
+		It("config error", func() {
+			command := NewDefaultImporterCommand()
+			command.SetArgs([]string{"-c", "testdata/optimize-failed.yaml", "--report", reportFile})
+			err := command.Execute()
+			Expect(util.ExitCode(err)).To(Equal(ExitCodeConfigError))
+
+			rpt := readReport()
+			Expect(rpt.Succeeded).To(BeFalse())
+			Expect(rpt.ExitCode).To(Equal(ExitCodeConfigError))
+			Expect(rpt.Error).To(Equal(err.Error()))
+			Expect(rpt.Total).To(BeNil())
+		})
+
+		It("write failed", func() {
+			o := NewImporterOptions(common.IOStreams{
+				In:     os.Stdin,
+				Out:    &bytes.Buffer{},
+				ErrOut: os.Stderr,
+			})
+			o.useNopLogger = true
+			command := NewImporterCommand(o)
+			command.SetArgs([]string{"-c", "testdata/nebula-importer.v3.yaml", "--dry-run", "--report", filepath.Join(reportFile, "not-exists")})
+			Expect(command.Execute()).NotTo(HaveOccurred())
+		})
+	})
 })
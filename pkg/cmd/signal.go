@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/config"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/logger"
+)
+
+var (
+	fnNotify = signal.Notify
+	fnExit   = os.Exit
+)
+
+type (
+	// signalHandler stops the manager gracefully on the first SIGINT or SIGTERM, and exits the process on the second one.
+	// SIGHUP reloads the config file.
+	signalHandler struct {
+		ch       chan os.Signal
+		done     chan struct{}
+		stopOnce sync.Once
+		mu       sync.Mutex
+		received os.Signal
+	}
+)
+
+func (o *ImporterOptions) handleSignals() *signalHandler {
+	h := &signalHandler{
+		ch:   make(chan os.Signal, 2),
+		done: make(chan struct{}),
+	}
+	fnNotify(h.ch, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+
+	go func() {
+		for {
+			select {
+			case sig := <-h.ch:
+				if sig == syscall.SIGHUP {
+					o.reload()
+					continue
+				}
+				if h.Received() == nil {
+					h.mu.Lock()
+					h.received = sig
+					h.mu.Unlock()
+
+					o.getLogger().Warn(fmt.Sprintf("received signal %s, stopping, send again to exit immediately", sig))
+					go func() {
+						_ = o.mgr.Stop()
+					}()
+					continue
+				}
+				l := o.getLogger()
+				l.Error(fmt.Sprintf("received signal %s again, exit immediately", sig))
+				_ = l.Sync()
+				fnExit(signalExitCode(sig)) //revive:disable-line:deep-exit
+				return
+			case <-h.done:
+				return
+			}
+		}
+	}()
+	return h
+}
+
+// reload re-reads the config file, and applies the settings which can be changed while importing, such as the rate limits.
+func (o *ImporterOptions) reload() {
+	l := o.getLogger()
+	cfg, err := config.FromFile(o.ConfigFile)
+	if err == nil {
+		err = o.cfg.Reload(cfg)
+	}
+	if err != nil {
+		e := errors.AsOrNewImportError(err)
+		l.WithError(e.Cause()).Error("failed to reload config", logger.MapToFields(e.Fields())...)
+		return
+	}
+	l.Info("config reloaded")
+}
+
+// Received returns the first signal received, it's nil if not received.
+func (h *signalHandler) Received() os.Signal {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.received
+}
+
+func (h *signalHandler) Stop() {
+	h.stopOnce.Do(func() {
+		signal.Stop(h.ch)
+		close(h.done)
+	})
+}
+
+// signalExitCode returns 128 plus the signal number, the same as the shells.
+func signalExitCode(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+	return 1
+}
@@ -1,6 +1,7 @@
 package util
 
 import (
+	stderrors "errors"
 	"fmt"
 	"os"
 	"strings"
@@ -11,12 +12,47 @@ var (
 	fnExit   = os.Exit
 )
 
+type (
+	// ExitError sets the exit code of the process for the error.
+	ExitError struct {
+		Err  error
+		Code int
+	}
+)
+
+// NewExitError returns nil if err is nil.
+func NewExitError(err error, code int) error {
+	if err == nil {
+		return nil
+	}
+	return &ExitError{Err: err, Code: code}
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code of the error, it's 0 if err is nil, and 1 if err is not an ExitError.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if e := new(ExitError); stderrors.As(err, &e) {
+		return e.Code
+	}
+	return 1
+}
+
 func CheckErr(err error) {
 	switch err.(type) {
 	case nil:
 		return
 	default:
-		fatal(fmt.Sprintf("%+v", err), 1)
+		fatal(fmt.Sprintf("%+v", err), ExitCode(err))
 	}
 }
 
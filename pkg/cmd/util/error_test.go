@@ -2,6 +2,7 @@ package util
 
 import (
 	stderrors "errors"
+	"fmt"
 	"io"
 
 	"github.com/agiledragon/gomonkey/v2"
@@ -35,3 +36,36 @@ var _ = Describe("CheckErr", func() {
 		Expect(exitCode).To(Equal(1))
 	})
 })
+
+var _ = Describe("ExitError", func() {
+	It("nil", func() {
+		Expect(NewExitError(nil, 2)).To(BeNil())
+		Expect(ExitCode(nil)).To(Equal(0))
+	})
+
+	It("exit code", func() {
+		testErr := stderrors.New("test error")
+		err := NewExitError(testErr, 3)
+		Expect(err).To(MatchError("test error"))
+		Expect(stderrors.Is(err, testErr)).To(BeTrue())
+		Expect(ExitCode(err)).To(Equal(3))
+		Expect(ExitCode(testErr)).To(Equal(1))
+		Expect(ExitCode(fmt.Errorf("wrapped: %w", err))).To(Equal(3))
+	})
+
+	It("CheckErr", func() {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+
+		var exitCode int
+		patches.ApplyGlobalVar(&fnFprint, func(io.Writer, ...any) (int, error) {
+			return 0, nil
+		})
+		patches.ApplyGlobalVar(&fnExit, func(code int) {
+			exitCode = code
+		})
+
+		CheckErr(NewExitError(stderrors.New("test error"), 4))
+		Expect(exitCode).To(Equal(4))
+	})
+})
@@ -21,15 +21,16 @@ const (
 
 type (
 	Client struct {
-		Version                  string        `yaml:"version"`
-		Address                  string        `yaml:"address"`
-		User                     string        `yaml:"user,omitempty"`
-		Password                 string        `yaml:"password,omitempty"`
-		ConcurrencyPerAddress    int           `yaml:"concurrencyPerAddress,omitempty"`
-		ReconnectInitialInterval time.Duration `yaml:"reconnectInitialInterval,omitempty"`
-		Retry                    int           `yaml:"retry,omitempty"`
-		RetryInitialInterval     time.Duration `yaml:"retryInitialInterval,omitempty"`
-		SSL                      *SSL          `yaml:"ssl,omitempty"`
+		Version                  string           `yaml:"version"`
+		Address                  string           `yaml:"address"`
+		User                     string           `yaml:"user,omitempty"`
+		Password                 string           `yaml:"password,omitempty"`
+		ConcurrencyPerAddress    int              `yaml:"concurrencyPerAddress,omitempty"`
+		ReconnectInitialInterval time.Duration    `yaml:"reconnectInitialInterval,omitempty"`
+		Retry                    int              `yaml:"retry,omitempty"`
+		RetryInitialInterval     time.Duration    `yaml:"retryInitialInterval,omitempty"`
+		SSL                      *SSL             `yaml:"ssl,omitempty"`
+		RateLimit                *ClientRateLimit `yaml:"rateLimit,omitempty"`
 	}
 
 	SSL struct {
@@ -1,6 +1,8 @@
 package configbase
 
 import (
+	"io"
+
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/client"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/logger"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/manager"
@@ -8,8 +10,12 @@ import (
 
 type Configurator interface {
 	Optimize(configPath string) error
-	Build() error
+	Replay() error
+	DryRun(w io.Writer)
+	Build(opts ...manager.Option) error
 	GetLogger() logger.Logger
 	GetClientPool() client.Pool
 	GetManager() manager.Manager
+	// Reload applies the settings of c which can be changed while importing, such as the rate limits.
+	Reload(c Configurator) error
 }
@@ -1,9 +1,13 @@
 package configbase
 
 import (
+	"path/filepath"
 	"time"
 
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/adaptive"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/checkpoint"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/manager"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/utils"
 )
 
 type (
@@ -12,6 +16,97 @@ type (
 		ReaderConcurrency   int           `yaml:"readerConcurrency,omitempty"`
 		ImporterConcurrency int           `yaml:"importerConcurrency,omitempty"`
 		StatsInterval       time.Duration `yaml:"statsInterval,omitempty"`
+		StopTimeout         time.Duration `yaml:"stopTimeout,omitempty"`
 		Hooks               manager.Hooks `yaml:"hooks,omitempty"`
+		Checkpoint          *Checkpoint   `yaml:"checkpoint,omitempty"`
+		DeadLetter          *DeadLetter   `yaml:"deadLetter,omitempty"`
+		Bisect              bool          `yaml:"bisect,omitempty"`
+		Schema              *Schema       `yaml:"schema,omitempty"`
+		RateLimit           *RateLimit    `yaml:"rateLimit,omitempty"`
+		Adaptive            *Adaptive     `yaml:"adaptive,omitempty"`
+
+		// The failure thresholds to abort the import early, zero means no limit.
+		MaxFailedRecords             int64   `yaml:"maxFailedRecords,omitempty"`
+		MaxFailedRatio               float64 `yaml:"maxFailedRatio,omitempty"`
+		MinRecordsForRatio           int64   `yaml:"minRecordsForRatio,omitempty"`
+		MaxConsecutiveFailedRequests int64   `yaml:"maxConsecutiveFailedRequests,omitempty"`
+	}
+
+	Checkpoint struct {
+		Path          string        `yaml:"path,omitempty"`
+		FlushInterval time.Duration `yaml:"flushInterval,omitempty"`
+	}
+
+	Schema struct {
+		// Create creates the space, tags and edges which do not exist before importing.
+		Create bool `yaml:"create,omitempty"`
+		// Alter adds the props missing in the existing tags and edges, it works with Create.
+		Alter bool `yaml:"alter,omitempty"`
+		// Validate checks the tags and edges against the schema of the space before importing.
+		Validate    bool          `yaml:"validate,omitempty"`
+		Space       *SchemaSpace  `yaml:"space,omitempty"`
+		WaitTimeout time.Duration `yaml:"waitTimeout,omitempty"`
+		// HeartbeatDelay is the time to wait after the schema is created or altered,
+		// until the graph and storage services load it in their heartbeats.
+		HeartbeatDelay time.Duration `yaml:"heartbeatDelay,omitempty"`
+	}
+
+	// SchemaSpace is the options to create the space.
+	SchemaSpace struct {
+		PartitionNum  int    `yaml:"partitionNum,omitempty"`
+		ReplicaFactor int    `yaml:"replicaFactor,omitempty"`
+		VidType       string `yaml:"vidType,omitempty"`
+	}
+
+	// Adaptive adjusts the batch and the importer concurrency by the response times, the batch and
+	// the importer concurrency are the initial values.
+	Adaptive struct {
+		Enable         bool          `yaml:"enable,omitempty"`
+		TargetRespTime time.Duration `yaml:"targetRespTime,omitempty"`
+		MinBatch       int           `yaml:"minBatch,omitempty"`
+		MaxBatch       int           `yaml:"maxBatch,omitempty"`
+		MinConcurrency int           `yaml:"minConcurrency,omitempty"`
+		MaxConcurrency int           `yaml:"maxConcurrency,omitempty"`
+	}
+
+	DeadLetter struct {
+		// Dir is the directory of the dead letter files, one file for each source.
+		Dir string `yaml:"dir,omitempty"`
 	}
 )
+
+// OptimizePath optimizes relative paths base to the configuration file path
+func (m *Manager) OptimizePath(configPath string) error {
+	if m.Checkpoint != nil && m.Checkpoint.Path != "" {
+		m.Checkpoint.Path = utils.RelativePathBaseOn(filepath.Dir(configPath), m.Checkpoint.Path)
+	}
+	if m.DeadLetter != nil && m.DeadLetter.Dir != "" {
+		m.DeadLetter.Dir = utils.RelativePathBaseOn(filepath.Dir(configPath), m.DeadLetter.Dir)
+	}
+	return nil
+}
+
+// BuildCheckpointStore returns nil if the checkpoint is not configured.
+func (m *Manager) BuildCheckpointStore(hash string) (checkpoint.Store, error) {
+	if m.Checkpoint == nil || m.Checkpoint.Path == "" {
+		return nil, nil
+	}
+	var opts []checkpoint.Option
+	if m.Checkpoint.FlushInterval > 0 {
+		opts = append(opts, checkpoint.WithFlushInterval(m.Checkpoint.FlushInterval))
+	}
+	return checkpoint.NewFileStore(m.Checkpoint.Path, hash, opts...)
+}
+
+// BuildAdaptiveController returns nil if the adaptive is not enabled.
+func (m *Manager) BuildAdaptiveController() *adaptive.Controller {
+	if m.Adaptive == nil || !m.Adaptive.Enable {
+		return nil
+	}
+	return adaptive.New(
+		adaptive.WithTargetRespTime(m.Adaptive.TargetRespTime),
+		adaptive.WithBatchRange(m.Adaptive.MinBatch, m.Adaptive.MaxBatch),
+		adaptive.WithConcurrencyRange(m.Adaptive.MinConcurrency, m.Adaptive.MaxConcurrency),
+		adaptive.WithInitial(m.Batch, m.ImporterConcurrency),
+	)
+}
@@ -0,0 +1,111 @@
+package configbase
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/checkpoint"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Manager", func() {
+	DescribeTable(".OptimizePath",
+		func(configPath string, checkpointConfig, expect *Checkpoint) {
+			m := Manager{Checkpoint: checkpointConfig}
+			Expect(m.OptimizePath(configPath)).NotTo(HaveOccurred())
+			Expect(m.Checkpoint).To(Equal(expect))
+		},
+		EntryDescription("%[1]s : %[2]v => %[3]v"),
+		Entry(nil, "f.yaml", nil, nil),
+		Entry(nil, "f.yaml", &Checkpoint{}, &Checkpoint{}),
+		Entry(nil, "f.yaml", &Checkpoint{Path: "cp.json"}, &Checkpoint{Path: "cp.json"}),
+		Entry(nil, "./f.yaml", &Checkpoint{Path: "cp.json"}, &Checkpoint{Path: "cp.json"}),
+		Entry(nil, "./rel/f.yaml", &Checkpoint{Path: "cp.json"}, &Checkpoint{Path: "rel/cp.json"}),
+		Entry(nil, "/abs/f.yaml", &Checkpoint{Path: "cp.json"}, &Checkpoint{Path: "/abs/cp.json"}),
+		Entry(nil, "/abs/f.yaml", &Checkpoint{Path: "/cp.json"}, &Checkpoint{Path: "/cp.json"}),
+	)
+
+	DescribeTable(".OptimizePath DeadLetter",
+		func(configPath string, deadLetterConfig, expect *DeadLetter) {
+			m := Manager{DeadLetter: deadLetterConfig}
+			Expect(m.OptimizePath(configPath)).NotTo(HaveOccurred())
+			Expect(m.DeadLetter).To(Equal(expect))
+		},
+		EntryDescription("%[1]s : %[2]v => %[3]v"),
+		Entry(nil, "f.yaml", nil, nil),
+		Entry(nil, "f.yaml", &DeadLetter{}, &DeadLetter{}),
+		Entry(nil, "./rel/f.yaml", &DeadLetter{Dir: "dead"}, &DeadLetter{Dir: "rel/dead"}),
+		Entry(nil, "/abs/f.yaml", &DeadLetter{Dir: "dead"}, &DeadLetter{Dir: "/abs/dead"}),
+		Entry(nil, "/abs/f.yaml", &DeadLetter{Dir: "/dead"}, &DeadLetter{Dir: "/dead"}),
+	)
+
+	Describe(".BuildCheckpointStore", func() {
+		var tmpdir string
+
+		BeforeEach(func() {
+			var err error
+			tmpdir, err = os.MkdirTemp("", "test")
+			Expect(err).NotTo(HaveOccurred())
+		})
+		AfterEach(func() {
+			err := os.RemoveAll(tmpdir)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("not configured", func() {
+			m := Manager{}
+			store, err := m.BuildCheckpointStore("hash")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(store).To(BeNil())
+		})
+
+		It("failed", func() {
+			path := filepath.Join(tmpdir, "checkpoint.json")
+			Expect(os.WriteFile(path, []byte("{"), 0o600)).NotTo(HaveOccurred())
+			m := Manager{Checkpoint: &Checkpoint{Path: path}}
+			store, err := m.BuildCheckpointStore("hash")
+			Expect(err).To(HaveOccurred())
+			Expect(store).To(BeNil())
+		})
+
+		It("successfully", func() {
+			path := filepath.Join(tmpdir, "checkpoint.json")
+			m := Manager{Checkpoint: &Checkpoint{Path: path, FlushInterval: 1}}
+			store, err := m.BuildCheckpointStore("hash")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(store).NotTo(BeNil())
+			Expect(store.Save("source", checkpoint.Checkpoint{Offset: 1, Records: 1})).NotTo(HaveOccurred())
+			Expect(store.Close()).NotTo(HaveOccurred())
+			_, err = os.Stat(path)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+	Describe(".BuildAdaptiveController", func() {
+		It("not configured", func() {
+			m := Manager{}
+			Expect(m.BuildAdaptiveController()).To(BeNil())
+			m.Adaptive = &Adaptive{}
+			Expect(m.BuildAdaptiveController()).To(BeNil())
+		})
+
+		It("successfully", func() {
+			m := Manager{
+				Batch:               64,
+				ImporterConcurrency: 1000,
+				Adaptive: &Adaptive{
+					Enable:         true,
+					TargetRespTime: time.Second,
+					MaxConcurrency: 100,
+				},
+			}
+			c := m.BuildAdaptiveController()
+			Expect(c).NotTo(BeNil())
+			batch, concurrency := c.Values()
+			Expect(batch).To(Equal(64))
+			Expect(concurrency).To(Equal(100))
+		})
+	})
+})
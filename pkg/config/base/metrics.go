@@ -0,0 +1,22 @@
+package configbase
+
+import (
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/metrics"
+)
+
+type Metrics struct {
+	// Listen is the address to serve the Prometheus metrics on, such as ":9090".
+	Listen string `yaml:"listen,omitempty"`
+}
+
+// BuildMetrics returns the metrics served on the listen address, or nil if it is not configured.
+func (m *Metrics) BuildMetrics() (metrics.Metrics, error) {
+	if m == nil || m.Listen == "" {
+		return nil, nil
+	}
+	mt := metrics.New()
+	if err := mt.Serve(m.Listen); err != nil {
+		return nil, err
+	}
+	return mt, nil
+}
@@ -0,0 +1,31 @@
+package configbase
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Metrics", func() {
+	It(".BuildMetrics", func() {
+		var m *Metrics
+		mt, err := m.BuildMetrics()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mt).To(BeNil())
+
+		m = &Metrics{}
+		mt, err = m.BuildMetrics()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mt).To(BeNil())
+
+		m = &Metrics{Listen: "127.0.0.1:0"}
+		mt, err = m.BuildMetrics()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mt).NotTo(BeNil())
+		Expect(mt.Close()).NotTo(HaveOccurred())
+
+		m = &Metrics{Listen: "invalid address"}
+		mt, err = m.BuildMetrics()
+		Expect(err).To(HaveOccurred())
+		Expect(mt).To(BeNil())
+	})
+})
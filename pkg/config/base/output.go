@@ -0,0 +1,33 @@
+package configbase
+
+import (
+	"io"
+	"path/filepath"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/client"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/output"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/utils"
+)
+
+type Output struct {
+	// Dir is the directory of the statement files, one file for each tag and edge.
+	Dir string `yaml:"dir,omitempty"`
+}
+
+// OptimizePath optimizes relative paths base to the configuration file path
+func (o *Output) OptimizePath(configPath string) error {
+	if o == nil || o.Dir == "" {
+		return nil
+	}
+
+	o.Dir = utils.RelativePathBaseOn(filepath.Dir(configPath), o.Dir)
+	return nil
+}
+
+// BuildOutputPool returns the pool writing the statements to the files in the directory, or to w if the directory is not set.
+func (o *Output) BuildOutputPool(graphName string, w io.Writer) client.Pool {
+	if o != nil && o.Dir != "" {
+		return output.NewFilePool(o.Dir, graphName)
+	}
+	return output.NewWriterPool(w, graphName)
+}
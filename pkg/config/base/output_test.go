@@ -0,0 +1,43 @@
+package configbase
+
+import (
+	"bytes"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/output"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Output", func() {
+	DescribeTable(".OptimizePath",
+		func(configPath string, o, expect *Output) {
+			Expect(o.OptimizePath(configPath)).NotTo(HaveOccurred())
+			Expect(o).To(Equal(expect))
+		},
+		EntryDescription("%[1]s : %[2]v => %[3]v"),
+		Entry(nil, "f.yaml", nil, nil),
+		Entry(nil, "f.yaml", &Output{}, &Output{}),
+		Entry(nil, "./rel/f.yaml", &Output{Dir: "ngql"}, &Output{Dir: "rel/ngql"}),
+		Entry(nil, "/abs/f.yaml", &Output{Dir: "ngql"}, &Output{Dir: "/abs/ngql"}),
+		Entry(nil, "/abs/f.yaml", &Output{Dir: "/ngql"}, &Output{Dir: "/ngql"}),
+	)
+
+	It(".BuildOutputPool", func() {
+		var o *Output
+		buf := &bytes.Buffer{}
+		p := o.BuildOutputPool("graphName", buf)
+		_, ok := p.(output.Pool)
+		Expect(ok).To(BeTrue())
+		_, err := p.Execute("statement")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.Close()).NotTo(HaveOccurred())
+		Expect(buf.String()).To(ContainSubstring("statement;\n"))
+
+		o = &Output{Dir: GinkgoT().TempDir()}
+		p = o.BuildOutputPool("graphName", buf)
+		_, ok = p.(output.Pool)
+		Expect(ok).To(BeTrue())
+		Expect(p.Close()).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,70 @@
+package configbase
+
+import (
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/client"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/manager"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/ratelimit"
+)
+
+type (
+	// RateLimit is the limits per second of the import, zero means no limit.
+	RateLimit struct {
+		Records  float64 `yaml:"records,omitempty"`
+		Requests float64 `yaml:"requests,omitempty"`
+		Bytes    float64 `yaml:"bytes,omitempty"`
+	}
+
+	// ClientRateLimit is the limits per second of each graphd address, zero means no limit.
+	ClientRateLimit struct {
+		Requests float64 `yaml:"requests,omitempty"`
+	}
+
+	// RateLimiters are built even if not limited, so that the limits can be changed while importing.
+	RateLimiters struct {
+		Records            *ratelimit.Limiter
+		Requests           *ratelimit.Limiter
+		Bytes              *ratelimit.Limiter
+		RequestsPerAddress *ratelimit.Group
+	}
+)
+
+func NewRateLimiters(m *RateLimit, c *ClientRateLimit) *RateLimiters {
+	l := &RateLimiters{
+		Records:            ratelimit.New(0),
+		Requests:           ratelimit.New(0),
+		Bytes:              ratelimit.New(0),
+		RequestsPerAddress: ratelimit.NewGroup(0),
+	}
+	l.Set(m, c)
+	return l
+}
+
+// Set sets the limits, nil means no limit.
+func (l *RateLimiters) Set(m *RateLimit, c *ClientRateLimit) {
+	if m == nil {
+		m = &RateLimit{}
+	}
+	if c == nil {
+		c = &ClientRateLimit{}
+	}
+	l.Records.SetLimit(m.Records)
+	l.Requests.SetLimit(m.Requests)
+	l.Bytes.SetLimit(m.Bytes)
+	l.RequestsPerAddress.SetLimit(c.Requests)
+}
+
+func (l *RateLimiters) ManagerOptions() []manager.Option {
+	return []manager.Option{
+		manager.WithRecordsRateLimiter(l.Records),
+		manager.WithBytesRateLimiter(l.Bytes),
+	}
+}
+
+// ClientOptions limits the requests where each statement is executed, including the retries,
+// the sub-requests of the bisected batches and the USE statements.
+func (l *RateLimiters) ClientOptions() []client.Option {
+	return []client.Option{
+		client.WithRateLimiter(l.Requests),
+		client.WithAddressRateLimiter(l.RequestsPerAddress),
+	}
+}
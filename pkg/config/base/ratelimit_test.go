@@ -0,0 +1,34 @@
+package configbase
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RateLimiters", func() {
+	It("not limited", func() {
+		l := NewRateLimiters(nil, nil)
+		Expect(l.Records.Limit()).To(Equal(0.0))
+		Expect(l.Requests.Limit()).To(Equal(0.0))
+		Expect(l.Bytes.Limit()).To(Equal(0.0))
+		Expect(l.RequestsPerAddress.Limit()).To(Equal(0.0))
+		Expect(l.ManagerOptions()).To(HaveLen(2))
+		Expect(l.ClientOptions()).To(HaveLen(2))
+	})
+
+	It(".Set", func() {
+		l := NewRateLimiters(&RateLimit{Records: 1000, Requests: 10, Bytes: 1024}, &ClientRateLimit{Requests: 5})
+		Expect(l.Records.Limit()).To(Equal(1000.0))
+		Expect(l.Requests.Limit()).To(Equal(10.0))
+		Expect(l.Bytes.Limit()).To(Equal(1024.0))
+		Expect(l.RequestsPerAddress.Limit()).To(Equal(5.0))
+
+		records := l.Records
+		l.Set(&RateLimit{Records: 2000}, nil)
+		Expect(l.Records).To(BeIdenticalTo(records))
+		Expect(l.Records.Limit()).To(Equal(2000.0))
+		Expect(l.Requests.Limit()).To(Equal(0.0))
+		Expect(l.Bytes.Limit()).To(Equal(0.0))
+		Expect(l.RequestsPerAddress.Limit()).To(Equal(0.0))
+	})
+})
@@ -27,16 +27,34 @@ func (s *Source) BuildSourceAndReader(opts ...reader.Option) (
 	if err != nil {
 		return nil, nil, err
 	}
+	// Decompress the data transparently, the compression is detected when open.
+	src = source.NewDecompressSource(src)
 	if s.Batch > 0 {
 		// Override the batch in the manager.
 		opts = append(opts, reader.WithBatch(s.Batch))
 	}
 
-	rr := reader.NewRecordReader(src)
+	rr := reader.NewRecordReader(src, opts...)
 	brr := reader.NewBatchRecordReader(rr, opts...)
 	return src, brr, nil
 }
 
+// Header opens the source to read the names of the columns, see reader.ReadHeader for details.
+func (s *Source) Header() ([]string, error) {
+	sourceConfig := s.SourceConfig
+	src, err := sourceNew(&sourceConfig)
+	if err != nil {
+		return nil, err
+	}
+	src = source.NewDecompressSource(src)
+	if err = src.Open(); err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	return reader.ReadHeader(src)
+}
+
 func (s *Source) Glob() ([]*Source, bool, error) {
 	sourceConfig := s.SourceConfig
 	src, err := sourceNew(&sourceConfig)
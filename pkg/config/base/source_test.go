@@ -2,6 +2,8 @@ package configbase
 
 import (
 	stderrors "errors"
+	"os"
+	"path/filepath"
 
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/source"
 
@@ -73,6 +75,56 @@ var _ = Describe("Source", func() {
 		})
 	})
 
+	Describe(".Header", func() {
+		var patches *gomonkey.Patches
+		BeforeEach(func() {
+			patches = gomonkey.NewPatches()
+		})
+		AfterEach(func() {
+			patches.Reset()
+		})
+
+		It("successfully", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "header.csv")
+			Expect(os.WriteFile(path, []byte("id,name\n1,a\n"), 0o600)).NotTo(HaveOccurred())
+			s := &Source{
+				SourceConfig: source.Config{
+					Local: &source.LocalConfig{
+						Path: path,
+					},
+					CSV: &source.CSVConfig{
+						WithHeader: true,
+					},
+				},
+			}
+			header, err := s.Header()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(header).To(Equal([]string{"id", "name"}))
+		})
+
+		It("open failed", func() {
+			s := &Source{
+				SourceConfig: source.Config{
+					Local: &source.LocalConfig{
+						Path: "not-exists.csv",
+					},
+				},
+			}
+			header, err := s.Header()
+			Expect(err).To(HaveOccurred())
+			Expect(header).To(BeNil())
+		})
+
+		It("new failed", func() {
+			patches.ApplyGlobalVar(&sourceNew, func(_ *source.Config) (source.Source, error) {
+				return nil, stderrors.New("test error")
+			})
+			header, err := (&Source{}).Header()
+			Expect(err).To(HaveOccurred())
+			Expect(header).To(BeNil())
+		})
+	})
+
 	Describe(".Glob", func() {
 		var (
 			s           *Source
@@ -2,29 +2,41 @@ package configv3
 
 import (
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/client"
 	configbase "github.com/vesoft-inc/nebula-importer/v4/pkg/config/base"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/logger"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/manager"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/metrics"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/utils"
 )
 
 var _ configbase.Configurator = (*Config)(nil)
 
 type (
-	Client = configbase.Client
-	Log    = configbase.Log
+	Client  = configbase.Client
+	Log     = configbase.Log
+	Output  = configbase.Output
+	Metrics = configbase.Metrics
 
 	Config struct {
 		Client  `yaml:"client"`
 		Manager `yaml:"manager"`
 		Sources `yaml:"sources"`
 		*Log    `yaml:"log,omitempty"`
+		Output  *Output  `yaml:"output,omitempty"`
+		Metrics *Metrics `yaml:"metrics,omitempty"`
 
-		logger logger.Logger
-		pool   client.Pool
-		mgr    manager.Manager
+		// dryRunWriter is where the statements are written to in dry run mode if the output is not configured.
+		dryRunWriter io.Writer
+
+		logger       logger.Logger
+		pool         client.Pool
+		mgr          manager.Manager
+		rateLimiters *configbase.RateLimiters
 	}
 )
 
@@ -33,10 +45,18 @@ func (c *Config) Optimize(configPath string) error {
 		return err
 	}
 
+	if err := c.Manager.OptimizePath(configPath); err != nil {
+		return err
+	}
+
 	if err := c.Log.OptimizePath(configPath); err != nil {
 		return err
 	}
 
+	if err := c.Output.OptimizePath(configPath); err != nil {
+		return err
+	}
+
 	if err := c.Sources.OptimizePath(configPath); err != nil {
 		return err
 	}
@@ -49,15 +69,55 @@ func (c *Config) Optimize(configPath string) error {
 	return nil
 }
 
-func (c *Config) Build() error {
+// Replay replaces the sources by their dead letter files, the sources without dead letters are removed.
+// The hooks and the checkpoint are disabled, since they are for the original import.
+func (c *Config) Replay() error {
+	if c.Manager.DeadLetter == nil || c.Manager.DeadLetter.Dir == "" {
+		return errors.ErrNoDeadLetter
+	}
+
+	paths := c.Sources.deadLetterPaths(c.Manager.DeadLetter.Dir)
+	sources := make(Sources, 0, len(c.Sources))
+	for i := range c.Sources {
+		if _, err := os.Stat(paths[i]); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		sources = append(sources, c.Sources[i].replay(paths[i]))
+	}
+
+	sources.pruneDependencies()
+	c.Sources = sources
+	c.Manager.Hooks = manager.Hooks{}
+	c.Manager.Checkpoint = nil
+	return nil
+}
+
+// DryRun writes the statements to w instead of executing them, unless the output is configured.
+func (c *Config) DryRun(w io.Writer) {
+	c.dryRunWriter = w
+}
+
+// IsDryRun returns whether the statements are written to the output instead of the client.
+func (c *Config) IsDryRun() bool {
+	return c.dryRunWriter != nil || (c.Output != nil && c.Output.Dir != "")
+}
+
+func (c *Config) Build(opts ...manager.Option) error {
 	var (
 		err  error
 		l    logger.Logger
 		pool client.Pool
 		mgr  manager.Manager
+		mt   metrics.Metrics
 	)
 	defer func() {
 		if err != nil {
+			if mt != nil {
+				_ = mt.Close()
+			}
 			if pool != nil {
 				_ = pool.Close()
 			}
@@ -67,20 +127,61 @@ func (c *Config) Build() error {
 		}
 	}()
 
-	l, err = c.BuildLogger()
-	if err != nil {
-		return err
+	var loggerOptions []logger.Option
+	if c.IsDryRun() && (c.Output == nil || c.Output.Dir == "") {
+		// Keep the statements apart from the console logs.
+		loggerOptions = append(loggerOptions, logger.WithConsoleWriter(os.Stderr))
 	}
-	pool, err = c.BuildClientPool(
-		client.WithLogger(l),
-		client.WithClientInitFunc(c.clientInitFunc),
-	)
+	l, err = c.BuildLogger(loggerOptions...)
 	if err != nil {
 		return err
 	}
-	mgr, err = c.Manager.BuildManager(l, pool, c.Sources,
-		manager.WithGetClientOptions(client.WithClientInitFunc(nil)), // clean the USE SPACE in 3.x
-	)
+	rateLimiters := configbase.NewRateLimiters(c.Manager.RateLimit, c.Client.RateLimit)
+	adaptiveController := c.Manager.BuildAdaptiveController()
+	if c.IsDryRun() {
+		pool = c.Output.BuildOutputPool(c.Manager.GraphName, c.dryRunWriter)
+	} else {
+		mt, err = c.Metrics.BuildMetrics()
+		if err != nil {
+			return err
+		}
+		clientOptions := []client.Option{
+			client.WithLogger(l),
+			client.WithClientInitFunc(c.clientInitFunc),
+		}
+		if mt != nil {
+			clientOptions = append(clientOptions, client.WithExecuteObserver(mt.ObserveExecute))
+		}
+		clientOptions = append(clientOptions, rateLimiters.ClientOptions()...)
+		if adaptiveController != nil {
+			clientOptions = append(clientOptions, client.WithRetryMoreObserver(adaptiveController.Overloaded))
+		}
+		pool, err = c.BuildClientPool(clientOptions...)
+		if err != nil {
+			return err
+		}
+	}
+	options := make([]manager.Option, 0, 6+len(opts))
+	options = append(options, manager.WithGetClientOptions(client.WithClientInitFunc(nil))) // clean the USE SPACE in 3.x
+	if mt != nil {
+		options = append(options, manager.WithMetrics(mt))
+	}
+	options = append(options, rateLimiters.ManagerOptions()...)
+	if adaptiveController != nil {
+		options = append(options, manager.WithAdaptiveController(adaptiveController))
+	}
+	options = append(options, opts...)
+	m, sources := c.Manager, c.Sources
+	if c.IsDryRun() {
+		// The checkpoints and the dead letters are kept for the real import, and the schema is not checked.
+		m.Checkpoint, m.DeadLetter, m.Schema = nil, nil, nil
+		sources = make(Sources, len(c.Sources))
+		for i := range c.Sources {
+			sources[i] = c.Sources[i]
+			sources[i].deadLetterPath = ""
+		}
+	}
+	mgr, err = m.BuildManager(l, pool, sources, options...)
 	if err != nil {
 		return err
 	}
@@ -88,10 +189,23 @@ func (c *Config) Build() error {
 	c.logger = l
 	c.pool = pool
 	c.mgr = mgr
+	c.rateLimiters = rateLimiters
 
 	return nil
 }
 
+// Reload applies the rate limits of other while importing, the other changes are ignored.
+func (c *Config) Reload(other configbase.Configurator) error {
+	o, ok := other.(*Config)
+	if !ok {
+		return errors.NewImportError(errors.ErrUnsupportedReload, "the client version of the config is changed")
+	}
+	if c.rateLimiters != nil {
+		c.rateLimiters.Set(o.Manager.RateLimit, o.Client.RateLimit)
+	}
+	return nil
+}
+
 func (c *Config) GetLogger() logger.Logger {
 	return c.logger
 }
@@ -2,10 +2,16 @@ package configv3
 
 import (
 	stderrors "errors"
+	"io"
+	"os"
 	"path/filepath"
 
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/adaptive"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/client"
 	configbase "github.com/vesoft-inc/nebula-importer/v4/pkg/config/base"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/manager"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/output"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/source"
 	specv3 "github.com/vesoft-inc/nebula-importer/v4/pkg/spec/v3"
 
@@ -51,6 +57,63 @@ var _ = Describe("Config", func() {
 		})
 	})
 
+	Describe(".Replay", func() {
+		It("no dead letter", func() {
+			c := &Config{}
+			err := c.Replay()
+			Expect(err).To(HaveOccurred())
+			Expect(stderrors.Is(err, errors.ErrNoDeadLetter)).To(BeTrue())
+		})
+
+		It("successfully", func() {
+			dir := GinkgoT().TempDir()
+			Expect(os.WriteFile(filepath.Join(dir, "node.csv"), []byte("1,a\n"), 0o600)).NotTo(HaveOccurred())
+
+			c := &Config{
+				Sources: Sources{
+					Source{
+						Source: configbase.Source{
+							SourceConfig: source.Config{
+								Local:       &source.LocalConfig{Path: "data/node.csv.gz"},
+								Compression: source.CompressionGzip,
+								CSV:         &source.CSVConfig{Delimiter: "|"},
+							},
+						},
+						Name:      "node",
+						DependsOn: []string{"edge"},
+					},
+					Source{
+						Source: configbase.Source{
+							SourceConfig: source.Config{
+								Local: &source.LocalConfig{Path: "data/edge.csv"},
+							},
+						},
+						Name: "edge",
+					},
+				},
+				Manager: Manager{
+					Manager: configbase.Manager{
+						DeadLetter: &configbase.DeadLetter{Dir: dir},
+						Checkpoint: &configbase.Checkpoint{Path: "checkpoint.json"},
+						Hooks: manager.Hooks{
+							Before: []*manager.Hook{{Statements: []string{"DROP SPACE x"}}},
+						},
+					},
+				},
+			}
+			Expect(c.Replay()).NotTo(HaveOccurred())
+			Expect(c.Sources).To(HaveLen(1))
+			Expect(c.Sources[0].SourceConfig.Path()).To(Equal(filepath.Join(dir, "node.csv")))
+			Expect(c.Sources[0].SourceConfig.Compression).To(Equal(source.CompressionNone))
+			Expect(c.Sources[0].SourceConfig.CSV.Delimiter).To(Equal("|"))
+			Expect(c.Sources[0].isReplay).To(BeTrue())
+			Expect(c.Sources[0].Name).To(Equal("node"))
+			Expect(c.Sources[0].DependsOn).To(BeEmpty())
+			Expect(c.Manager.Checkpoint).To(BeNil())
+			Expect(c.Manager.Hooks.Before).To(BeEmpty())
+		})
+	})
+
 	Describe(".Build", func() {
 		var c Config
 		BeforeEach(func() {
@@ -105,6 +168,88 @@ var _ = Describe("Config", func() {
 			Expect(c.GetClientPool()).NotTo(BeNil())
 			Expect(c.GetManager()).NotTo(BeNil())
 		})
+
+		It("dry run", func() {
+			c.Client.Version = "v"
+			c.Manager.Checkpoint = &configbase.Checkpoint{Path: filepath.Join("testdata", "not-exists", "checkpoint.json")}
+			c.Sources[0].deadLetterPath = filepath.Join("testdata", "not-exists", "file10")
+			Expect(c.IsDryRun()).To(BeFalse())
+			c.DryRun(io.Discard)
+			Expect(c.IsDryRun()).To(BeTrue())
+			Expect(c.Build()).NotTo(HaveOccurred())
+			_, ok := c.GetClientPool().(output.Pool)
+			Expect(ok).To(BeTrue())
+			Expect(c.Manager.Checkpoint).NotTo(BeNil())
+			Expect(c.Sources[0].deadLetterPath).NotTo(BeEmpty())
+		})
+
+		It("output", func() {
+			c.Output = &Output{Dir: GinkgoT().TempDir()}
+			Expect(c.IsDryRun()).To(BeTrue())
+			Expect(c.Build()).NotTo(HaveOccurred())
+			_, ok := c.GetClientPool().(output.Pool)
+			Expect(ok).To(BeTrue())
+		})
+
+		It("metrics", func() {
+			c.Metrics = &Metrics{Listen: "127.0.0.1:0"}
+			Expect(c.Build()).NotTo(HaveOccurred())
+			Expect(c.GetManager()).NotTo(BeNil())
+		})
+
+		It("adaptive", func() {
+			c.Manager.Adaptive = &configbase.Adaptive{Enable: true}
+			Expect(c.Build()).NotTo(HaveOccurred())
+			Expect(c.GetManager().Stats().Batch).To(Equal(adaptive.DefaultInitialBatch))
+		})
+
+		It("metrics failed", func() {
+			c.Metrics = &Metrics{Listen: "invalid address"}
+			Expect(c.Build()).To(HaveOccurred())
+		})
+
+		It("metrics BuildManager failed", func() {
+			c.Manager.GraphName = ""
+			c.Metrics = &Metrics{Listen: "127.0.0.1:0"}
+			Expect(c.Build()).To(HaveOccurred())
+		})
+	})
+
+	Describe(".Reload", func() {
+		var c Config
+		BeforeEach(func() {
+			c = Config{
+				Manager: Manager{
+					GraphName: "graphName",
+				},
+			}
+		})
+
+		It("unsupported", func() {
+			err := c.Reload(struct{ configbase.Configurator }{})
+			Expect(stderrors.Is(err, errors.ErrUnsupportedReload)).To(BeTrue())
+		})
+
+		It("not built", func() {
+			Expect(c.Reload(&Config{})).NotTo(HaveOccurred())
+		})
+
+		It("rate limits", func() {
+			c.Manager.RateLimit = &configbase.RateLimit{Records: 1000}
+			Expect(c.Build()).NotTo(HaveOccurred())
+			Expect(c.rateLimiters.Records.Limit()).To(Equal(1000.0))
+			Expect(c.rateLimiters.RequestsPerAddress.Limit()).To(Equal(0.0))
+
+			other := &Config{
+				Client:  Client{RateLimit: &configbase.ClientRateLimit{Requests: 10}},
+				Manager: Manager{Manager: configbase.Manager{RateLimit: &configbase.RateLimit{Records: 500, Bytes: 1024}}},
+			}
+			Expect(c.Reload(other)).NotTo(HaveOccurred())
+			Expect(c.rateLimiters.Records.Limit()).To(Equal(500.0))
+			Expect(c.rateLimiters.Bytes.Limit()).To(Equal(1024.0))
+			Expect(c.rateLimiters.RequestsPerAddress.Limit()).To(Equal(10.0))
+			Expect(c.rateLimiters.Requests.Limit()).To(Equal(0.0))
+		})
 	})
 })
 
@@ -1,11 +1,18 @@
 package configv3
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/client"
 	configbase "github.com/vesoft-inc/nebula-importer/v4/pkg/config/base"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/importer"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/logger"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/manager"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/reader"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/schema"
+
+	"gopkg.in/yaml.v3"
 )
 
 type (
@@ -21,29 +28,116 @@ func (m *Manager) BuildManager(
 	sources Sources,
 	opts ...manager.Option,
 ) (manager.Manager, error) {
-	options := make([]manager.Option, 0, 8+len(opts))
+	if err := sources.validateDependencies(); err != nil {
+		return nil, err
+	}
+
+	options := make([]manager.Option, 0, 14+len(opts))
 	options = append(options,
 		manager.WithClientPool(pool),
 		manager.WithBatch(m.Batch),
 		manager.WithReaderConcurrency(m.ReaderConcurrency),
 		manager.WithImporterConcurrency(m.ImporterConcurrency),
 		manager.WithStatsInterval(m.StatsInterval),
+		manager.WithStopTimeout(m.StopTimeout),
 		manager.WithBeforeHooks(m.Hooks.Before...),
 		manager.WithAfterHooks(m.Hooks.After...),
+		manager.WithOnFailureHooks(m.Hooks.OnFailure...),
+		manager.WithMaxFailedRecords(m.MaxFailedRecords),
+		manager.WithMaxFailedRatio(m.MaxFailedRatio),
+		manager.WithMinRecordsForRatio(m.MinRecordsForRatio),
+		manager.WithMaxConsecutiveFailedRequests(m.MaxConsecutiveFailedRequests),
 		manager.WithLogger(l),
 	)
+	if m.Checkpoint != nil && m.Checkpoint.Path != "" {
+		hash, err := m.checkpointHash(sources)
+		if err != nil {
+			return nil, err
+		}
+		store, err := m.BuildCheckpointStore(hash)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, manager.WithCheckpointStore(store))
+	}
+	if m.Schema != nil && m.Schema.Create {
+		createOptions := []schema.CreateOption{
+			schema.WithWaitTimeout(m.Schema.WaitTimeout),
+			schema.WithHeartbeatDelay(m.Schema.HeartbeatDelay),
+		}
+		if m.Schema.Alter {
+			createOptions = append(createOptions, schema.WithAlter())
+		}
+		if space := m.Schema.Space; space != nil {
+			createOptions = append(createOptions,
+				schema.WithPartitionNum(space.PartitionNum),
+				schema.WithReplicaFactor(space.ReplicaFactor),
+				schema.WithVidType(space.VidType),
+			)
+		}
+		options = append(options, manager.WithPreflight(func(cli client.Client) error {
+			for _, space := range sources.spaces(m.GraphName) {
+				nodes, edges := sources.nodesAndEdges(m.GraphName, space)
+				if err := schema.Create(cli, space, nodes, edges, createOptions...); err != nil {
+					return err
+				}
+			}
+			return nil
+		}))
+	}
+	if m.Schema != nil && m.Schema.Validate {
+		validate := func(cli client.Client) error {
+			for _, space := range sources.spaces(m.GraphName) {
+				nodes, edges := sources.nodesAndEdges(m.GraphName, space)
+				if err := schema.Validate(cli, space, nodes, edges); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		if m.Schema.Create {
+			// The created schema is validated after it.
+			options = append(options, manager.WithPreflight(validate))
+		} else {
+			options = append(options, manager.WithEarlyPreflight(validate))
+		}
+	}
 	options = append(options, opts...)
 
 	mgr := manager.NewWithOpts(options...)
 
+	var importerOptions []importer.Option
+	if m.Bisect {
+		importerOptions = append(importerOptions, importer.WithBisect())
+	}
+
+	var deadLetterPaths []string
+	if m.DeadLetter != nil && m.DeadLetter.Dir != "" {
+		deadLetterPaths = sources.deadLetterPaths(m.DeadLetter.Dir)
+	}
+
 	for i := range sources {
 		s := sources[i]
-		src, brr, err := s.BuildSourceAndReader(reader.WithBatch(m.Batch), reader.WithLogger(l))
+		if deadLetterPaths != nil && s.deadLetterPath == "" {
+			s.deadLetterPath = deadLetterPaths[i]
+		}
+		if err := s.ResolveColumns(); err != nil {
+			return nil, err
+		}
+		readerOptions := []reader.Option{reader.WithBatch(m.Batch), reader.WithLogger(l)}
+		// The sidecar columns of the dead letters are used to filter the records when replay.
+		if indices, ok := s.Indices(); ok && !s.isReplay {
+			readerOptions = append(readerOptions, reader.WithUsedIndices(indices...))
+		}
+		src, brr, err := s.BuildSourceAndReader(readerOptions...)
 		if err != nil {
 			return nil, err
 		}
+		if s.Name != "" || len(s.DependsOn) > 0 {
+			src = manager.NewDependentSource(src, s.dependency())
+		}
 
-		importers, err := s.BuildImporters(m.GraphName, pool)
+		importers, err := s.BuildImporters(m.GraphName, pool, importerOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -54,3 +148,19 @@ func (m *Manager) BuildManager(
 
 	return mgr, nil
 }
+
+// checkpointHash hashes the space and sources, the checkpoints are discarded once they changed.
+func (m *Manager) checkpointHash(sources Sources) (string, error) {
+	content, err := yaml.Marshal(struct {
+		GraphName string  `yaml:"spaceName"`
+		Sources   Sources `yaml:"sources"`
+	}{
+		GraphName: m.GraphName,
+		Sources:   sources,
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
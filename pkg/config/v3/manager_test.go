@@ -1,9 +1,14 @@
 package configv3
 
 import (
+	stderrors "errors"
+	"os"
 	"path/filepath"
+	"time"
 
 	configbase "github.com/vesoft-inc/nebula-importer/v4/pkg/config/base"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/manager"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/source"
 	specv3 "github.com/vesoft-inc/nebula-importer/v4/pkg/spec/v3"
 
@@ -56,5 +61,135 @@ var _ = Describe("Manager", func() {
 		It("successfully", func() {
 			Expect(c.Build()).NotTo(HaveOccurred())
 		})
+
+		It("checkpoint failed", func() {
+			tmpdir, err := os.MkdirTemp("", "test")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(tmpdir)
+			path := filepath.Join(tmpdir, "checkpoint.json")
+			Expect(os.WriteFile(path, []byte("{"), 0o600)).NotTo(HaveOccurred())
+
+			c.Manager.Checkpoint = &configbase.Checkpoint{Path: path}
+			Expect(c.Build()).To(HaveOccurred())
+		})
+
+		It("checkpoint successfully", func() {
+			tmpdir, err := os.MkdirTemp("", "test")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(tmpdir)
+
+			c.Manager.Checkpoint = &configbase.Checkpoint{Path: filepath.Join(tmpdir, "checkpoint.json")}
+			Expect(c.Build(manager.WithResume(true))).NotTo(HaveOccurred())
+		})
+
+		It("bisect", func() {
+			c.Manager.Bisect = true
+			Expect(c.Build()).NotTo(HaveOccurred())
+		})
+
+		It("schema validate", func() {
+			c.Manager.Schema = &configbase.Schema{Validate: true}
+			Expect(c.Build()).NotTo(HaveOccurred())
+		})
+
+		It("schema create", func() {
+			c.Manager.Schema = &configbase.Schema{
+				Create: true,
+				Alter:  true,
+				Space: &configbase.SchemaSpace{
+					PartitionNum:  10,
+					ReplicaFactor: 1,
+					VidType:       "FIXED_STRING(32)",
+				},
+			}
+			Expect(c.Build()).NotTo(HaveOccurred())
+		})
+
+		It("schema create and validate", func() {
+			c.Manager.Schema = &configbase.Schema{Create: true, Validate: true}
+			Expect(c.Build()).NotTo(HaveOccurred())
+		})
+
+		It("failure thresholds", func() {
+			c.Manager.MaxFailedRecords = 10
+			c.Manager.MaxFailedRatio = 0.1
+			c.Manager.MaxConsecutiveFailedRequests = 3
+			c.Manager.Hooks.OnFailure = []*manager.Hook{{Statements: []string{"statement"}}}
+			Expect(c.Build()).NotTo(HaveOccurred())
+		})
+
+		It("spaces", func() {
+			s := c.Sources[0]
+			s.Space = "s2"
+			c.Sources = append(c.Sources, s)
+			c.Manager.Schema = &configbase.Schema{Validate: true}
+			Expect(c.Build()).NotTo(HaveOccurred())
+		})
+
+		It("stop timeout", func() {
+			c.Manager.StopTimeout = 10 * time.Second
+			Expect(c.Build()).NotTo(HaveOccurred())
+		})
+
+		It("dependencies", func() {
+			s := c.Sources[0]
+			s.Name, s.DependsOn = "s2", []string{"s1"}
+			c.Sources[0].Name = "s1"
+			c.Sources = append(c.Sources, s)
+			Expect(c.Build()).NotTo(HaveOccurred())
+		})
+
+		It("dependency cycle", func() {
+			s := c.Sources[0]
+			s.Name, s.DependsOn = "s2", []string{"s1"}
+			c.Sources[0].Name, c.Sources[0].DependsOn = "s1", []string{"s2"}
+			c.Sources = append(c.Sources, s)
+			err := c.Build()
+			Expect(stderrors.Is(err, errors.ErrDependencyCycle)).To(BeTrue())
+		})
+
+		It("resume without checkpoint", func() {
+			Expect(c.Build(manager.WithResume(true))).To(HaveOccurred())
+		})
+	})
+
+	Describe(".checkpointHash", func() {
+		It("changed", func() {
+			m := Manager{GraphName: "graphName"}
+			sources := Sources{
+				{
+					Source: configbase.Source{
+						SourceConfig: source.Config{
+							Local: &source.LocalConfig{
+								Path: filepath.Join("testdata", "file10"),
+							},
+						},
+					},
+				},
+			}
+			hash1, err := m.checkpointHash(sources)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hash1).NotTo(BeEmpty())
+
+			hash2, err := m.checkpointHash(sources)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hash2).To(Equal(hash1))
+
+			m.Batch = 100
+			hash2, err = m.checkpointHash(sources)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hash2).To(Equal(hash1))
+
+			m.GraphName = "graphName2"
+			hash2, err = m.checkpointHash(sources)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hash2).NotTo(Equal(hash1))
+
+			m.GraphName = "graphName"
+			sources[0].SourceConfig.Local.Path = filepath.Join("testdata", "file11")
+			hash2, err = m.checkpointHash(sources)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hash2).NotTo(Equal(hash1))
+		})
 	})
 })
@@ -1,11 +1,20 @@
 package configv3
 
 import (
+	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/client"
 	configbase "github.com/vesoft-inc/nebula-importer/v4/pkg/config/base"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/deadletter"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/importer"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/manager"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/output"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/source"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/spec"
+	specbase "github.com/vesoft-inc/nebula-importer/v4/pkg/spec/base"
 	specv3 "github.com/vesoft-inc/nebula-importer/v4/pkg/spec/v3"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/utils"
 )
@@ -15,6 +24,17 @@ type (
 		configbase.Source `yaml:",inline"`
 		Nodes             specv3.Nodes `yaml:"tags,omitempty"`
 		Edges             specv3.Edges `yaml:"edges,omitempty"`
+		// Space overrides the spaceName of the manager for the tags and edges of the source.
+		Space string `yaml:"space,omitempty"`
+
+		// Name identifies the source in the dependencies of the others, the sources with the same name
+		// are completed together, DependsOn are the names of the sources to be completed before this one.
+		Name             string   `yaml:"name,omitempty"`
+		DependsOn        []string `yaml:"dependsOn,omitempty"`
+		DependencyPolicy string   `yaml:"dependencyPolicy,omitempty"`
+
+		deadLetterPath string
+		isReplay       bool
 	}
 
 	Sources []Source
@@ -39,28 +59,180 @@ func (s *Source) BuildGraph(graphName string, opts ...specv3.GraphOption) (*spec
 	return graph, nil
 }
 
-func (s *Source) BuildImporters(graphName string, pool client.Pool) ([]importer.Importer, error) {
+// BuildImporters builds the importers of the tags and edges in the space of the source, graphName is the default space.
+// The statements are executed in the space by the sessions of the pool shared with the other spaces.
+func (s *Source) BuildImporters(graphName string, pool client.Pool, opts ...importer.Option) ([]importer.Importer, error) {
+	graphName = s.spaceName(graphName)
 	graph, err := s.BuildGraph(graphName)
 	if err != nil {
 		return nil, err
 	}
+	if s.deadLetterPath != "" {
+		opts = append(opts, importer.WithDeadLetter(s.buildDeadLetter()))
+	}
 	importers := make([]importer.Importer, 0, len(s.Nodes)+len(s.Edges))
 	for k := range s.Nodes {
 		node := s.Nodes[k]
 		builder := graph.NodeStatementBuilder(node)
-		i := importer.New(builder, pool)
+		options := append([]importer.Option{importer.WithNodeName(node.Name), importer.WithMode(modeName(node.Modes()))}, opts...)
+		if node.Props.HasSet() {
+			// The set expressions are not idempotent, a request is retried without the other records.
+			options = append(options, importer.WithRecordPerRequest())
+		}
+		if s.isReplay {
+			options = append(options, importer.WithFilter(func(record spec.Record) bool {
+				return deadletter.IsSidecarRecord(record, deadletter.FieldTag, node.Name)
+			}))
+		}
+		i := importer.New(builder, client.NewSpacePool(namedPool(pool, node.Name), graphName), options...)
 		importers = append(importers, i)
 	}
 
 	for k := range s.Edges {
 		edge := s.Edges[k]
 		builder := graph.EdgeStatementBuilder(edge)
-		i := importer.New(builder, pool)
+		options := append([]importer.Option{importer.WithEdgeName(edge.Name), importer.WithMode(modeName(edge.Modes()))}, opts...)
+		if edge.Props.HasSet() {
+			options = append(options, importer.WithRecordPerRequest())
+		}
+		if s.isReplay {
+			options = append(options, importer.WithFilter(func(record spec.Record) bool {
+				return deadletter.IsSidecarRecord(record, deadletter.FieldEdge, edge.Name)
+			}))
+		}
+		i := importer.New(builder, client.NewSpacePool(namedPool(pool, edge.Name), graphName), options...)
 		importers = append(importers, i)
 	}
 	return importers, nil
 }
 
+// modeName returns the mode in the stats, the modes of the mode column are joined by "|", such as DELETE|INSERT.
+func modeName(modes []specbase.Mode) string {
+	names := make([]string, len(modes))
+	for i, mode := range modes {
+		names[i] = string(mode)
+	}
+	return strings.Join(names, "|")
+}
+
+// namedPool returns the pool of the tag or edge if the statements are written to the output.
+func namedPool(pool client.Pool, name string) client.Pool {
+	if p, ok := pool.(output.Pool); ok {
+		return p.Named(name)
+	}
+	return pool
+}
+
+func (s *Source) buildDeadLetter() deadletter.Writer {
+	opts := []deadletter.Option{deadletter.WithHeaderFunc(s.Header)}
+	if s.isReplay {
+		opts = append(opts, deadletter.WithReplay())
+	}
+	sourceConfig := s.SourceConfig
+	return deadletter.New(s.deadLetterPath, &sourceConfig, opts...)
+}
+
+// replay returns a copy of the source which reads the dead letter file,
+// the sidecar columns are appended to the columns of the dead letters.
+func (s *Source) replay(path string) Source {
+	sourceConfig := source.Config{
+		Local:       &source.LocalConfig{Path: path},
+		Compression: source.CompressionNone,
+	}
+	switch {
+	case s.SourceConfig.JSON != nil:
+		fields := append(append([]string(nil), s.SourceConfig.JSON.Fields...), deadletter.SidecarFields...)
+		sourceConfig.JSON = &source.JSONConfig{Fields: fields}
+	case s.SourceConfig.Parquet != nil:
+		var columns []string
+		if len(s.SourceConfig.Parquet.Columns) > 0 {
+			columns = append(append(columns, s.SourceConfig.Parquet.Columns...), deadletter.SidecarFields...)
+		}
+		sourceConfig.Parquet = &source.ParquetConfig{Columns: columns}
+	case s.SourceConfig.CSV != nil:
+		csvConfig := *s.SourceConfig.CSV
+		sourceConfig.CSV = &csvConfig
+	}
+
+	cpy := *s
+	cpy.SourceConfig = sourceConfig
+	cpy.deadLetterPath = path
+	cpy.isReplay = true
+	return cpy
+}
+
+// HasColumns returns whether the tags or edges refer to the columns by names.
+func (s *Source) HasColumns() bool {
+	for _, node := range s.Nodes {
+		if node.HasColumns() {
+			return true
+		}
+	}
+	for _, edge := range s.Edges {
+		if edge.HasColumns() {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveColumns resolves the column names to the record indices by the header of the source.
+// The tags and edges are replaced by the resolved copies, since they are shared by the globbed sources.
+func (s *Source) ResolveColumns() error {
+	if !s.HasColumns() {
+		return nil
+	}
+
+	fileName := s.SourceConfig.Path()
+	header, err := s.Header()
+	if err != nil {
+		return errors.AsOrNewImportError(err, "read header failed").SetFileName(fileName)
+	}
+	columns := specv3.NewColumns(header)
+
+	nodes := make(specv3.Nodes, 0, len(s.Nodes))
+	for _, node := range s.Nodes {
+		resolved, err := node.ResolveColumns(columns)
+		if err != nil {
+			return errors.AsOrNewImportError(err).SetFileName(fileName)
+		}
+		nodes = append(nodes, resolved)
+	}
+
+	edges := make(specv3.Edges, 0, len(s.Edges))
+	for _, edge := range s.Edges {
+		resolved, err := edge.ResolveColumns(columns)
+		if err != nil {
+			return errors.AsOrNewImportError(err).SetFileName(fileName)
+		}
+		edges = append(edges, resolved)
+	}
+
+	s.Nodes, s.Edges = nodes, edges
+	return nil
+}
+
+// Indices returns the record indices referenced by the tags and edges.
+// It returns false if they cannot be determined.
+func (s *Source) Indices() ([]int, bool) {
+	var indices []int
+	for _, node := range s.Nodes {
+		nodeIndices, ok := node.Indices()
+		if !ok {
+			return nil, false
+		}
+		indices = append(indices, nodeIndices...)
+	}
+	for _, edge := range s.Edges {
+		edgeIndices, ok := edge.Indices()
+		if !ok {
+			return nil, false
+		}
+		indices = append(indices, edgeIndices...)
+	}
+	return indices, true
+}
+
 // OptimizePath optimizes relative paths base to the configuration file path
 func (ss Sources) OptimizePath(configPath string) error {
 	configPathDir := filepath.Dir(configPath)
@@ -72,6 +244,99 @@ func (ss Sources) OptimizePath(configPath string) error {
 	return nil
 }
 
+// deadLetterPaths returns the dead letter file of each source in the directory, named by the base name of the source.
+func (ss Sources) deadLetterPaths(dir string) []string {
+	paths := make([]string, len(ss))
+	names := make(map[string]int, len(ss))
+	for i := range ss {
+		name := filepath.Base(source.TrimCompressionExt(ss[i].SourceConfig.Path()))
+		if name == "." || name == string(filepath.Separator) {
+			name = "source"
+		}
+		names[name]++
+		if n := names[name]; n > 1 {
+			ext := filepath.Ext(name)
+			name = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(name, ext), n, ext)
+		}
+		paths[i] = filepath.Join(dir, name)
+	}
+	return paths
+}
+
+// spaceName returns the space of the source, which is graphName if not overridden.
+func (s *Source) spaceName(graphName string) string {
+	if s.Space != "" {
+		return s.Space
+	}
+	return graphName
+}
+
+// spaces returns the spaces of all the sources in order, graphName is the default space.
+func (ss Sources) spaces(graphName string) []string {
+	var spaces []string
+	seen := make(map[string]struct{}, 1)
+	for i := range ss {
+		space := ss[i].spaceName(graphName)
+		if _, ok := seen[space]; !ok {
+			seen[space] = struct{}{}
+			spaces = append(spaces, space)
+		}
+	}
+	return spaces
+}
+
+// nodesAndEdges returns the tags and edges of the sources in the space, graphName is the default space.
+func (ss Sources) nodesAndEdges(graphName, space string) (specv3.Nodes, specv3.Edges) {
+	var (
+		nodes specv3.Nodes
+		edges specv3.Edges
+	)
+	for i := range ss {
+		if ss[i].spaceName(graphName) == space {
+			nodes = append(nodes, ss[i].Nodes...)
+			edges = append(edges, ss[i].Edges...)
+		}
+	}
+	return nodes, edges
+}
+
+// dependency returns the dependency of the source in the DAG of the sources.
+func (s *Source) dependency() manager.Dependency {
+	return manager.Dependency{
+		Name:      s.Name,
+		DependsOn: s.DependsOn,
+		Policy:    manager.DependencyPolicy(s.DependencyPolicy),
+	}
+}
+
+// validateDependencies checks the dependencies of the sources, and that they have no cycles.
+func (ss Sources) validateDependencies() error {
+	deps := make([]manager.Dependency, 0, len(ss))
+	for i := range ss {
+		deps = append(deps, ss[i].dependency())
+	}
+	return manager.ValidateDependencies(deps...)
+}
+
+// pruneDependencies removes the dependencies on the sources which do not exist, such as the ones removed when replay.
+func (ss Sources) pruneDependencies() {
+	names := make(map[string]struct{}, len(ss))
+	for i := range ss {
+		if ss[i].Name != "" {
+			names[ss[i].Name] = struct{}{}
+		}
+	}
+	for i := range ss {
+		var dependsOn []string
+		for _, name := range ss[i].DependsOn {
+			if _, ok := names[name]; ok {
+				dependsOn = append(dependsOn, name)
+			}
+		}
+		ss[i].DependsOn = dependsOn
+	}
+}
+
 // OptimizePathWildCard optimizes the wildcards in the paths
 func (ss *Sources) OptimizePathWildCard() error {
 	nss := make(Sources, 0, len(*ss))
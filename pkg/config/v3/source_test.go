@@ -1,10 +1,18 @@
 package configv3
 
 import (
+	"bytes"
+	stderrors "errors"
 	"os"
 	"path/filepath"
 
+	configbase "github.com/vesoft-inc/nebula-importer/v4/pkg/config/base"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/importer"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/output"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/source"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/spec"
+	specbase "github.com/vesoft-inc/nebula-importer/v4/pkg/spec/base"
 	specv3 "github.com/vesoft-inc/nebula-importer/v4/pkg/spec/v3"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -68,6 +76,137 @@ var _ = Describe("Source", func() {
 		})
 	})
 
+	Describe(".Indices", func() {
+		It("successfully", func() {
+			s := &Source{
+				Nodes: specv3.Nodes{
+					&specv3.Node{
+						Name: "n1",
+						ID:   &specv3.NodeID{Index: 0},
+					},
+				},
+				Edges: specv3.Edges{
+					&specv3.Edge{
+						Name: "e1",
+						Src:  &specv3.EdgeNodeRef{ID: &specv3.NodeID{Index: 0}},
+						Dst:  &specv3.EdgeNodeRef{ID: &specv3.NodeID{Index: 2}},
+					},
+				},
+			}
+			indices, ok := s.Indices()
+			Expect(ok).To(BeTrue())
+			Expect(indices).To(Equal([]int{0, 0, 2}))
+		})
+
+		It("undetermined", func() {
+			s := &Source{
+				Edges: specv3.Edges{
+					&specv3.Edge{
+						Name:   "e1",
+						Filter: &specbase.Filter{Expr: "len(Record) > 0"},
+					},
+				},
+			}
+			indices, ok := s.Indices()
+			Expect(ok).To(BeFalse())
+			Expect(indices).To(BeNil())
+		})
+	})
+
+	Describe(".ResolveColumns", func() {
+		var dir string
+		BeforeEach(func() {
+			dir = GinkgoT().TempDir()
+			Expect(os.WriteFile(filepath.Join(dir, "a.csv"), []byte("id,name,age\n1,a,18\n"), 0o600)).NotTo(HaveOccurred())
+			Expect(os.WriteFile(filepath.Join(dir, "b.csv"), []byte("age,id,name\n20,2,b\n"), 0o600)).NotTo(HaveOccurred())
+		})
+
+		newSource := func(path string, props ...*specv3.Prop) Source {
+			var s Source
+			s.SourceConfig = source.Config{
+				Local: &source.LocalConfig{
+					Path: path,
+				},
+				CSV: &source.CSVConfig{
+					WithHeader: true,
+				},
+			}
+			s.Nodes = specv3.Nodes{
+				specv3.NewNode("n",
+					specv3.WithNodeID(&specv3.NodeID{Name: "id", Column: "id"}),
+					specv3.WithNodeProps(props...),
+				),
+			}
+			return s
+		}
+
+		It("globbed files with reordered columns", func() {
+			ss := Sources{newSource(filepath.Join(dir, "*.csv"), &specv3.Prop{Name: "name", Column: "name"})}
+			Expect(ss.OptimizePathWildCard()).NotTo(HaveOccurred())
+			Expect(ss).To(HaveLen(2))
+			Expect(ss[0].Nodes[0]).To(BeIdenticalTo(ss[1].Nodes[0]))
+
+			var indices [][]int
+			for i := range ss {
+				Expect(ss[i].HasColumns()).To(BeTrue())
+				Expect(ss[i].ResolveColumns()).NotTo(HaveOccurred())
+				nodeIndices, ok := ss[i].Indices()
+				Expect(ok).To(BeTrue())
+				indices = append(indices, nodeIndices)
+			}
+			Expect(indices).To(Equal([][]int{{0, 1}, {1, 2}}))
+		})
+
+		It("no columns", func() {
+			s := newSource("not-exists.csv")
+			s.Nodes[0].ID = &specv3.NodeID{Name: "id", Index: 1}
+			Expect(s.HasColumns()).To(BeFalse())
+			Expect(s.ResolveColumns()).NotTo(HaveOccurred())
+		})
+
+		It("edges", func() {
+			s := newSource(filepath.Join(dir, "b.csv"))
+			s.Nodes = nil
+			s.Edges = specv3.Edges{
+				specv3.NewEdge("e",
+					specv3.WithEdgeSrc(&specv3.EdgeNodeRef{ID: &specv3.NodeID{Column: "id"}}),
+					specv3.WithEdgeDst(&specv3.EdgeNodeRef{ID: &specv3.NodeID{Column: "name"}}),
+				),
+			}
+			Expect(s.HasColumns()).To(BeTrue())
+			Expect(s.ResolveColumns()).NotTo(HaveOccurred())
+			indices, ok := s.Indices()
+			Expect(ok).To(BeTrue())
+			Expect(indices).To(Equal([]int{1, 2}))
+
+			s.Edges[0].Rank = &specv3.Rank{Column: "not-exists"}
+			err := s.ResolveColumns()
+			Expect(stderrors.Is(err, errors.ErrNoColumn)).To(BeTrue())
+		})
+
+		It("missing column", func() {
+			path := filepath.Join(dir, "b.csv")
+			s := newSource(path, &specv3.Prop{Name: "email", Column: "email"})
+			err := s.ResolveColumns()
+			Expect(stderrors.Is(err, errors.ErrNoColumn)).To(BeTrue())
+			e, ok := errors.AsImportError(err)
+			Expect(ok).To(BeTrue())
+			Expect(e.FileName()).To(Equal(path))
+			Expect(e.NodeName()).To(Equal("n"))
+			Expect(e.PropName()).To(Equal("email"))
+		})
+
+		It("read header failed", func() {
+			path := filepath.Join(dir, "not-exists.csv")
+			s := newSource(path)
+			err := s.ResolveColumns()
+			Expect(err).To(HaveOccurred())
+			e, ok := errors.AsImportError(err)
+			Expect(ok).To(BeTrue())
+			Expect(e.FileName()).To(Equal(path))
+		})
+	})
+
 	Describe(".BuildImporters", func() {
 		It("BuildGraph failed", func() {
 			s := &Source{}
@@ -123,10 +262,188 @@ var _ = Describe("Source", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(importers).To(HaveLen(3))
 		})
+
+		It("space", func() {
+			s := &Source{
+				Nodes: specv3.Nodes{
+					&specv3.Node{
+						Name: "n1",
+						ID: &specv3.NodeID{
+							Name:  "id",
+							Type:  specv3.ValueTypeString,
+							Index: 0,
+						},
+					},
+				},
+				Space: "s2",
+			}
+
+			buf := &bytes.Buffer{}
+			pool := output.NewWriterPool(buf, "graphName")
+			importers, err := s.BuildImporters("graphName", pool)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(importers).To(HaveLen(1))
+			_, err = importers[0].Import(spec.Record{"id1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pool.Close()).NotTo(HaveOccurred())
+			Expect(buf.String()).To(HavePrefix("USE `graphName`;\nUSE `s2`;\nINSERT VERTEX "))
+		})
+
+		It("mode column", func() {
+			s := &Source{
+				Nodes: specv3.Nodes{
+					&specv3.Node{
+						Name: "n1",
+						ID: &specv3.NodeID{
+							Name:  "id",
+							Type:  specv3.ValueTypeString,
+							Index: 0,
+						},
+						ModeColumn: &specv3.ModeColumn{
+							Index: 1,
+							Modes: map[string]specbase.Mode{"I": specbase.InsertMode, "D": specbase.DeleteMode},
+						},
+					},
+				},
+			}
+
+			importers, err := s.BuildImporters("graphName", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(importers).To(HaveLen(1))
+			Expect(importer.TargetOf(importers[0]).Mode).To(Equal("DELETE|INSERT"))
+		})
+
+		It("debezium", func() {
+			s := &Source{
+				Source: configbase.Source{
+					SourceConfig: source.Config{
+						Local: &source.LocalConfig{Path: filepath.Join("testdata", "debezium.jsonl")},
+						JSON: &source.JSONConfig{
+							Fields:   []string{"id", "name", "__op", "__ts"},
+							Envelope: source.JSONEnvelopeDebezium,
+						},
+					},
+				},
+				Nodes: specv3.Nodes{
+					&specv3.Node{
+						Name: "person",
+						ID:   &specv3.NodeID{Index: 0},
+						Props: specv3.Props{
+							&specv3.Prop{Name: "name", Index: 1},
+							&specv3.Prop{Name: "updated_at", Type: specv3.ValueTypeTimestamp, Index: 3},
+						},
+						ModeColumn: &specv3.ModeColumn{
+							Index: 2,
+							Modes: map[string]specbase.Mode{"c": "INSERT", "u": "UPSERT", "d": "DELETE_VERTEX"},
+						},
+					},
+				},
+			}
+
+			src, brr, err := s.BuildSourceAndReader()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(src.Open()).NotTo(HaveOccurred())
+			defer src.Close()
+			_, records, err := brr.ReadBatch()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(HaveLen(3))
+
+			buf := &bytes.Buffer{}
+			pool := output.NewWriterPool(buf, "graphName")
+			importers, err := s.BuildImporters("graphName", pool)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(importers).To(HaveLen(1))
+			_, err = importers[0].Import(records...)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pool.Close()).NotTo(HaveOccurred())
+			Expect(buf.String()).To(Equal("USE `graphName`;\n" +
+				"INSERT VERTEX IGNORE_EXISTED_INDEX `person`(`name`, `updated_at`) VALUES \"p1\":(\"a\", TIMESTAMP(1700000000));" +
+				"UPSERT VERTEX ON `person` \"p1\" SET `name` = \"b\", `updated_at` = TIMESTAMP(1700000001);" +
+				"DELETE VERTEX \"p1\" WITH EDGE;\n"))
+		})
+
+		It("set expressions", func() {
+			s := &Source{
+				Nodes: specv3.Nodes{
+					&specv3.Node{
+						Name: "player",
+						Mode: specbase.UpsertMode,
+						ID:   &specv3.NodeID{Index: 0},
+						Props: specv3.Props{
+							&specv3.Prop{Name: "score", Type: specv3.ValueTypeInt, Index: 1, Set: "score + {value}"},
+						},
+					},
+				},
+			}
+
+			buf := &bytes.Buffer{}
+			pool := output.NewWriterPool(buf, "graphName")
+			importers, err := s.BuildImporters("graphName", pool)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(importers).To(HaveLen(1))
+			resp, err := importers[0].Import(spec.Record{"p1", "1"}, spec.Record{"p2", "2"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.RecordNum).To(Equal(2))
+			Expect(pool.Close()).NotTo(HaveOccurred())
+			// The set expressions are not idempotent, so each record is a request.
+			Expect(buf.String()).To(Equal("USE `graphName`;\n" +
+				"UPSERT VERTEX ON `player` \"p1\" SET `score` = score + 1;\n" +
+				"UPSERT VERTEX ON `player` \"p2\" SET `score` = score + 2;\n"))
+		})
+	})
+
+	Describe(".replay", func() {
+		It("json", func() {
+			s := &Source{
+				Source: configbase.Source{
+					SourceConfig: source.Config{
+						Local: &source.LocalConfig{Path: "node.jsonl"},
+						JSON:  &source.JSONConfig{Fields: []string{"id", "a.b"}},
+					},
+				},
+			}
+			cpy := s.replay("dead/node.jsonl")
+			Expect(cpy.SourceConfig.Path()).To(Equal("dead/node.jsonl"))
+			Expect(cpy.SourceConfig.JSON.Fields).To(Equal([]string{"id", "a.b", "_error", "_tag", "_edge", "_statement"}))
+			Expect(cpy.deadLetterPath).To(Equal("dead/node.jsonl"))
+			Expect(cpy.isReplay).To(BeTrue())
+			Expect(s.SourceConfig.JSON.Fields).To(Equal([]string{"id", "a.b"}))
+		})
+
+		It("parquet", func() {
+			s := &Source{
+				Source: configbase.Source{
+					SourceConfig: source.Config{
+						Local:   &source.LocalConfig{Path: "node.parquet"},
+						Parquet: &source.ParquetConfig{},
+					},
+				},
+			}
+			cpy := s.replay("dead/node.parquet")
+			Expect(cpy.SourceConfig.Parquet).NotTo(BeNil())
+			Expect(cpy.SourceConfig.Parquet.Columns).To(BeEmpty())
+		})
 	})
 })
 
 var _ = Describe("Sources", func() {
+	It(".spaces", func() {
+		sources := Sources{
+			{Nodes: specv3.Nodes{&specv3.Node{Name: "n1"}}},
+			{Nodes: specv3.Nodes{&specv3.Node{Name: "n2"}}, Space: "s2"},
+			{Edges: specv3.Edges{&specv3.Edge{Name: "e1"}}, Space: "graphName"},
+			{Edges: specv3.Edges{&specv3.Edge{Name: "e2"}}, Space: "s2"},
+		}
+		Expect(sources.spaces("graphName")).To(Equal([]string{"graphName", "s2"}))
+
+		nodes, edges := sources.nodesAndEdges("graphName", "graphName")
+		Expect(nodes).To(Equal(specv3.Nodes{sources[0].Nodes[0]}))
+		Expect(edges).To(Equal(specv3.Edges{sources[2].Edges[0]}))
+		nodes, edges = sources.nodesAndEdges("graphName", "s2")
+		Expect(nodes).To(Equal(specv3.Nodes{sources[1].Nodes[0]}))
+		Expect(edges).To(Equal(specv3.Edges{sources[3].Edges[0]}))
+	})
+
 	DescribeTable(".OptimizePath",
 		func(configPath string, files, expectFiles []string) {
 			var sources Sources
@@ -282,4 +599,18 @@ var _ = Describe("Sources", func() {
 			Expect(sources.OptimizePathWildCard()).To(HaveOccurred())
 		})
 	})
+
+	It(".deadLetterPaths", func() {
+		sources := make(Sources, 4)
+		sources[0].SourceConfig.Local = &source.LocalConfig{Path: "a/node.csv.gz"}
+		sources[1].SourceConfig.Local = &source.LocalConfig{Path: "b/node.csv"}
+		sources[2].SourceConfig.Local = &source.LocalConfig{Path: "c/node.csv"}
+		sources[3].SourceConfig.Local = &source.LocalConfig{Path: "edge.jsonl.zst"}
+		Expect(sources.deadLetterPaths("dead")).To(Equal([]string{
+			filepath.Join("dead", "node.csv"),
+			filepath.Join("dead", "node-2.csv"),
+			filepath.Join("dead", "node-3.csv"),
+			filepath.Join("dead", "edge.jsonl"),
+		}))
+	})
 })
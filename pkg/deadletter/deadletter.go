@@ -0,0 +1,259 @@
+//go:generate mockgen -source=deadletter.go -destination deadletter_mock.go -package deadletter Writer
+package deadletter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/source"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/spec"
+)
+
+const (
+	FieldError     = "_error"
+	FieldTag       = "_tag"
+	FieldEdge      = "_edge"
+	FieldStatement = "_statement"
+
+	replaySuffix = ".replay"
+)
+
+var (
+	_ Writer    = (*defaultWriter)(nil)
+	_ Committer = (*defaultWriter)(nil)
+
+	// SidecarFields are appended to the columns of the dead letters in order.
+	SidecarFields = []string{FieldError, FieldTag, FieldEdge, FieldStatement}
+)
+
+type (
+	// Writer writes the failed records to the dead letter file in the format of the source.
+	Writer interface {
+		Write(err error, records ...spec.Record) error
+		Close() error
+	}
+
+	// Committer is implemented by the writers which replay the dead letters.
+	Committer interface {
+		// Commit marks the replay is complete, all the records are read and imported or written again,
+		// so that the replayed file is replaced on close, otherwise it's kept.
+		Commit()
+	}
+
+	// HeaderFunc returns the column names of the source, it is called when the first dead letter is written.
+	HeaderFunc func() ([]string, error)
+
+	Option func(*defaultWriter)
+
+	defaultWriter struct {
+		path     string
+		c        *source.Config
+		header   HeaderFunc
+		isReplay bool
+
+		mu       sync.Mutex
+		f        *os.File
+		fw       formatWriter
+		isClosed bool
+		// isCommitted is set once all the dead letters are replayed.
+		isCommitted bool
+	}
+
+	formatWriter interface {
+		WriteRecord(record spec.Record, sidecar []string) error
+		Flush() error
+		Close() error
+	}
+)
+
+// New returns a writer, the file is created when the first dead letter is written.
+func New(path string, c *source.Config, opts ...Option) Writer {
+	w := &defaultWriter{
+		path: path,
+		c:    c,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if w.c == nil {
+		w.c = &source.Config{}
+	}
+	return w
+}
+
+func WithHeaderFunc(fn HeaderFunc) Option {
+	return func(w *defaultWriter) {
+		w.header = fn
+	}
+}
+
+// WithReplay is used when the dead letter file is replayed, the records and the header carry the sidecar columns.
+// The dead letters are written to a new file, which replaces the replayed one on close if the replay is committed,
+// otherwise the replayed one is kept and the new file is removed, such as when the replay is interrupted.
+func WithReplay() Option {
+	return func(w *defaultWriter) {
+		w.isReplay = true
+	}
+}
+
+// IsSidecarRecord returns whether the record of a replayed dead letter is failed by the tag or edge.
+func IsSidecarRecord(record spec.Record, field, name string) bool {
+	n := len(record) - len(SidecarFields)
+	if n < 0 {
+		return false
+	}
+	for i, f := range SidecarFields {
+		if f == field {
+			return record[n+i] == name
+		}
+	}
+	return false
+}
+
+func (w *defaultWriter) Write(err error, records ...spec.Record) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.isClosed {
+		return os.ErrClosed
+	}
+	if w.fw == nil {
+		if err := w.open(); err != nil {
+			return err
+		}
+	}
+
+	sidecar := sidecarValues(err)
+	for _, record := range records {
+		if w.isReplay {
+			record = trimSidecar(record)
+		}
+		if err := w.fw.WriteRecord(record, sidecar); err != nil {
+			return errors.NewImportError(err, "write dead letter failed").SetFileName(w.path)
+		}
+	}
+	if err := w.fw.Flush(); err != nil {
+		return errors.NewImportError(err, "write dead letter failed").SetFileName(w.path)
+	}
+	return nil
+}
+
+func (w *defaultWriter) Commit() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.isCommitted = true
+}
+
+func (w *defaultWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.isClosed {
+		return nil
+	}
+	w.isClosed = true
+
+	if w.fw == nil {
+		if w.isReplay && w.isCommitted {
+			// All the dead letters are replayed successfully.
+			if err := os.Remove(w.path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		return nil
+	}
+
+	err := w.fw.Close()
+	if closeErr := w.f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.NewImportError(err, "close dead letter failed").SetFileName(w.path)
+	}
+	if !w.isReplay {
+		return nil
+	}
+	if !w.isCommitted {
+		return os.Remove(w.path + replaySuffix)
+	}
+	return os.Rename(w.path+replaySuffix, w.path)
+}
+
+func (w *defaultWriter) open() error {
+	path := w.path
+	if w.isReplay {
+		path += replaySuffix
+	}
+
+	var header []string
+	if w.needHeader() {
+		if w.header == nil {
+			return errors.NewImportError(errors.ErrNoHeader, "dead letter").SetFileName(w.path)
+		}
+		var err error
+		if header, err = w.header(); err != nil {
+			return errors.AsOrNewImportError(err, "dead letter").SetFileName(w.path)
+		}
+		if w.isReplay {
+			header = trimSidecar(header)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return errors.NewImportError(err, "create dead letter failed").SetFileName(w.path)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.NewImportError(err, "create dead letter failed").SetFileName(w.path)
+	}
+
+	var fw formatWriter
+	switch {
+	case w.c.JSON != nil:
+		fw = newJSONWriter(f, header)
+	case w.c.Parquet != nil:
+		fw, err = newParquetWriter(f, header)
+	default:
+		fw, err = newCSVWriter(f, w.c.CSV, header)
+	}
+	if err != nil {
+		_ = f.Close()
+		return errors.AsOrNewImportError(err, "create dead letter failed").SetFileName(w.path)
+	}
+
+	w.f, w.fw = f, fw
+	return nil
+}
+
+func (w *defaultWriter) needHeader() bool {
+	switch {
+	case w.c.JSON != nil, w.c.Parquet != nil:
+		return true
+	case w.c.CSV != nil:
+		return w.c.CSV.WithHeader
+	}
+	return false
+}
+
+// sidecarValues returns the values of SidecarFields of the error.
+func sidecarValues(err error) []string {
+	if err == nil {
+		return make([]string, len(SidecarFields))
+	}
+	e := errors.AsOrNewImportError(err)
+	messages := append([]string(nil), e.Messages...)
+	if e.Err != nil {
+		messages = append(messages, e.Err.Error())
+	}
+	return []string{strings.Join(messages, ": "), e.NodeName(), e.EdgeName(), e.Statement()}
+}
+
+func trimSidecar(record []string) []string {
+	if n := len(record) - len(SidecarFields); n >= 0 {
+		return record[:n]
+	}
+	return record
+}
@@ -0,0 +1,133 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: deadletter.go
+
+// Package deadletter is a generated GoMock package.
+package deadletter
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	spec "github.com/vesoft-inc/nebula-importer/v4/pkg/spec"
+)
+
+// MockWriter is a mock of Writer interface.
+type MockWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockWriterMockRecorder
+}
+
+// MockWriterMockRecorder is the mock recorder for MockWriter.
+type MockWriterMockRecorder struct {
+	mock *MockWriter
+}
+
+// NewMockWriter creates a new mock instance.
+func NewMockWriter(ctrl *gomock.Controller) *MockWriter {
+	mock := &MockWriter{ctrl: ctrl}
+	mock.recorder = &MockWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWriter) EXPECT() *MockWriterMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockWriter) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockWriterMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockWriter)(nil).Close))
+}
+
+// Write mocks base method.
+func (m *MockWriter) Write(err error, records ...spec.Record) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{err}
+	for _, a := range records {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Write", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Write indicates an expected call of Write.
+func (mr *MockWriterMockRecorder) Write(err interface{}, records ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{err}, records...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockWriter)(nil).Write), varargs...)
+}
+
+// MockformatWriter is a mock of formatWriter interface.
+type MockformatWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockformatWriterMockRecorder
+}
+
+// MockformatWriterMockRecorder is the mock recorder for MockformatWriter.
+type MockformatWriterMockRecorder struct {
+	mock *MockformatWriter
+}
+
+// NewMockformatWriter creates a new mock instance.
+func NewMockformatWriter(ctrl *gomock.Controller) *MockformatWriter {
+	mock := &MockformatWriter{ctrl: ctrl}
+	mock.recorder = &MockformatWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockformatWriter) EXPECT() *MockformatWriterMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockformatWriter) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockformatWriterMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockformatWriter)(nil).Close))
+}
+
+// Flush mocks base method.
+func (m *MockformatWriter) Flush() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Flush")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Flush indicates an expected call of Flush.
+func (mr *MockformatWriterMockRecorder) Flush() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Flush", reflect.TypeOf((*MockformatWriter)(nil).Flush))
+}
+
+// WriteRecord mocks base method.
+func (m *MockformatWriter) WriteRecord(record spec.Record, sidecar []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteRecord", record, sidecar)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteRecord indicates an expected call of WriteRecord.
+func (mr *MockformatWriterMockRecorder) WriteRecord(record, sidecar interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteRecord", reflect.TypeOf((*MockformatWriter)(nil).WriteRecord), record, sidecar)
+}
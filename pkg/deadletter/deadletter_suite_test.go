@@ -0,0 +1,13 @@
+package deadletter
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestDeadLetter(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Pkg deadletter Suite")
+}
@@ -0,0 +1,261 @@
+package deadletter
+
+import (
+	stderrors "errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/reader"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/source"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/spec"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Writer", func() {
+	var (
+		tmpdir    string
+		importErr error
+	)
+	BeforeEach(func() {
+		var err error
+		tmpdir, err = os.MkdirTemp("", "test")
+		Expect(err).NotTo(HaveOccurred())
+		importErr = errors.NewImportError(stderrors.New("test error"), "the execute error").
+			SetNodeName("n1").
+			SetStatement("INSERT VERTEX ...")
+	})
+	AfterEach(func() {
+		err := os.RemoveAll(tmpdir)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	fnReadAll := func(c *source.Config) []spec.Record {
+		s, err := source.New(c)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Open()).NotTo(HaveOccurred())
+		defer s.Close()
+		rr := reader.NewRecordReader(s)
+		if c.CSV != nil && c.CSV.WithHeader {
+			_, err = rr.(reader.HeaderReader).Header()
+			Expect(err).NotTo(HaveOccurred())
+		}
+		var records []spec.Record
+		for {
+			_, record, err := rr.Read()
+			if err == io.EOF {
+				return records
+			}
+			Expect(err).NotTo(HaveOccurred())
+			records = append(records, record)
+		}
+	}
+
+	It("no dead letters", func() {
+		path := filepath.Join(tmpdir, "dl", "file.csv")
+		w := New(path, nil)
+		Expect(w.Close()).NotTo(HaveOccurred())
+		Expect(w.Close()).NotTo(HaveOccurred())
+		_, err := os.Stat(path)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+		Expect(w.Write(importErr, spec.Record{"1"})).To(HaveOccurred())
+	})
+
+	It("csv", func() {
+		path := filepath.Join(tmpdir, "dl", "file.csv")
+		c := &source.Config{
+			Local: &source.LocalConfig{Path: path},
+			CSV:   &source.CSVConfig{Delimiter: "|"},
+		}
+		w := New(path, c)
+		Expect(w.Write(importErr, spec.Record{"1", "a,b"}, spec.Record{"2", "c"})).NotTo(HaveOccurred())
+		Expect(w.Write(stderrors.New("build failed"), spec.Record{"3", "d"})).NotTo(HaveOccurred())
+		Expect(w.Close()).NotTo(HaveOccurred())
+
+		Expect(fnReadAll(c)).To(Equal([]spec.Record{
+			{"1", "a,b", "the execute error: test error", "n1", "", "INSERT VERTEX ..."},
+			{"2", "c", "the execute error: test error", "n1", "", "INSERT VERTEX ..."},
+			{"3", "d", "build failed", "", "", ""},
+		}))
+	})
+
+	It("csv with header", func() {
+		path := filepath.Join(tmpdir, "file.csv")
+		c := &source.Config{
+			Local: &source.LocalConfig{Path: path},
+			CSV:   &source.CSVConfig{WithHeader: true},
+		}
+
+		w := New(path, c)
+		Expect(w.Write(importErr, spec.Record{"1", "a"})).To(HaveOccurred())
+
+		w = New(path, c, WithHeaderFunc(func() ([]string, error) {
+			return nil, stderrors.New("header failed")
+		}))
+		Expect(w.Write(importErr, spec.Record{"1", "a"})).To(HaveOccurred())
+
+		w = New(path, c, WithHeaderFunc(func() ([]string, error) {
+			return []string{"id", "name"}, nil
+		}))
+		Expect(w.Write(importErr, spec.Record{"1", "a"})).NotTo(HaveOccurred())
+		Expect(w.Close()).NotTo(HaveOccurred())
+
+		s, err := source.New(c)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Open()).NotTo(HaveOccurred())
+		defer s.Close()
+		header, err := reader.ReadHeader(s)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(header).To(Equal([]string{"id", "name", "_error", "_tag", "_edge", "_statement"}))
+		Expect(fnReadAll(c)).To(Equal([]spec.Record{
+			{"1", "a", "the execute error: test error", "n1", "", "INSERT VERTEX ..."},
+		}))
+	})
+
+	It("json", func() {
+		path := filepath.Join(tmpdir, "file.jsonl")
+		fields := []string{"id", "user.name", "tags[0]", "tags[1]"}
+		c := &source.Config{
+			Local: &source.LocalConfig{Path: path},
+			JSON:  &source.JSONConfig{Fields: fields},
+		}
+		w := New(path, c, WithHeaderFunc(func() ([]string, error) {
+			return fields, nil
+		}))
+		Expect(w.Write(importErr, spec.Record{"1", "a", "t1", ""})).NotTo(HaveOccurred())
+		Expect(w.Close()).NotTo(HaveOccurred())
+
+		c.JSON.Fields = append(fields, SidecarFields...)
+		Expect(fnReadAll(c)).To(Equal([]spec.Record{
+			{"1", "a", "t1", "", "the execute error: test error", "n1", "", "INSERT VERTEX ..."},
+		}))
+	})
+
+	It("parquet", func() {
+		path := filepath.Join(tmpdir, "file.parquet")
+		header := []string{"z", "user.name", "a"}
+		c := &source.Config{
+			Local:   &source.LocalConfig{Path: path},
+			Parquet: &source.ParquetConfig{},
+		}
+		w := New(path, c, WithHeaderFunc(func() ([]string, error) {
+			return header, nil
+		}))
+		Expect(w.Write(importErr, spec.Record{"1", "a", "x"}, spec.Record{"2", "b", "y"})).NotTo(HaveOccurred())
+		Expect(w.Close()).NotTo(HaveOccurred())
+
+		s, err := source.New(c)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Open()).NotTo(HaveOccurred())
+		defer s.Close()
+		readHeader, err := reader.ReadHeader(s)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(readHeader).To(Equal([]string{"z", "user.name", "a", "_error", "_tag", "_edge", "_statement"}))
+
+		Expect(fnReadAll(c)).To(Equal([]spec.Record{
+			{"1", "a", "x", "the execute error: test error", "n1", "", "INSERT VERTEX ..."},
+			{"2", "b", "y", "the execute error: test error", "n1", "", "INSERT VERTEX ..."},
+		}))
+	})
+
+	It("parquet conflict columns", func() {
+		path := filepath.Join(tmpdir, "file.parquet")
+		c := &source.Config{
+			Local:   &source.LocalConfig{Path: path},
+			Parquet: &source.ParquetConfig{},
+		}
+		for _, header := range [][]string{{"a", "a"}, {"a", "a.b"}, {"a.b", "a"}} {
+			header := header
+			w := New(path, c, WithHeaderFunc(func() ([]string, error) {
+				return header, nil
+			}))
+			err := w.Write(importErr, spec.Record{"1", "2"})
+			Expect(stderrors.Is(err, errors.ErrUnsupportedColumn)).To(BeTrue())
+		}
+	})
+
+	It("replay", func() {
+		path := filepath.Join(tmpdir, "file.csv")
+		c := &source.Config{
+			Local: &source.LocalConfig{Path: path},
+			CSV:   &source.CSVConfig{WithHeader: true},
+		}
+		fnHeader := WithHeaderFunc(func() ([]string, error) {
+			return []string{"id", "_error", "_tag", "_edge", "_statement"}, nil
+		})
+		Expect(os.WriteFile(path, []byte("id,_error,_tag,_edge,_statement\n1,e,n1,,s\n2,e,n1,,s\n"), 0o600)).NotTo(HaveOccurred())
+
+		w := New(path, c, fnHeader, WithReplay())
+		Expect(w.Write(importErr, spec.Record{"2", "e", "n1", "", "s"})).NotTo(HaveOccurred())
+		content, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("1,e,n1,,s"))
+		w.(Committer).Commit()
+		Expect(w.Close()).NotTo(HaveOccurred())
+
+		content, err = os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("id,_error,_tag,_edge,_statement\n2,the execute error: test error,n1,,INSERT VERTEX ...\n"))
+
+		// all replayed successfully
+		w = New(path, c, fnHeader, WithReplay())
+		w.(Committer).Commit()
+		Expect(w.Close()).NotTo(HaveOccurred())
+		_, err = os.Stat(path)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("replay not committed", func() {
+		path := filepath.Join(tmpdir, "file.csv")
+		c := &source.Config{
+			Local: &source.LocalConfig{Path: path},
+			CSV:   &source.CSVConfig{WithHeader: true},
+		}
+		fnHeader := WithHeaderFunc(func() ([]string, error) {
+			return []string{"id", "_error", "_tag", "_edge", "_statement"}, nil
+		})
+		original := "id,_error,_tag,_edge,_statement\n1,e,n1,,s\n2,e,n1,,s\n"
+		Expect(os.WriteFile(path, []byte(original), 0o600)).NotTo(HaveOccurred())
+
+		// interrupted after some dead letters are written again
+		w := New(path, c, fnHeader, WithReplay())
+		Expect(w.Write(importErr, spec.Record{"1", "e", "n1", "", "s"})).NotTo(HaveOccurred())
+		Expect(w.Close()).NotTo(HaveOccurred())
+		content, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal(original))
+		_, err = os.Stat(path + replaySuffix)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+
+		// interrupted without any dead letter
+		w = New(path, c, fnHeader, WithReplay())
+		Expect(w.Close()).NotTo(HaveOccurred())
+		content, err = os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal(original))
+	})
+
+	It("create failed", func() {
+		Expect(os.WriteFile(filepath.Join(tmpdir, "file"), nil, 0o600)).NotTo(HaveOccurred())
+		w := New(filepath.Join(tmpdir, "file", "file.csv"), nil)
+		Expect(w.Write(importErr, spec.Record{"1"})).To(HaveOccurred())
+
+		w = New(tmpdir, nil)
+		Expect(w.Write(importErr, spec.Record{"1"})).To(HaveOccurred())
+	})
+})
+
+var _ = DescribeTable("IsSidecarRecord",
+	func(record spec.Record, field, name string, expect bool) {
+		Expect(IsSidecarRecord(record, field, name)).To(Equal(expect))
+	},
+	Entry(nil, spec.Record{"1", "e", "n1", "", "s"}, FieldTag, "n1", true),
+	Entry(nil, spec.Record{"1", "e", "n1", "", "s"}, FieldTag, "n2", false),
+	Entry(nil, spec.Record{"1", "e", "", "e1", "s"}, FieldEdge, "e1", true),
+	Entry(nil, spec.Record{"1", "e", "", "e1", "s"}, FieldTag, "e1", false),
+	Entry(nil, spec.Record{"e", "", "e1"}, FieldEdge, "e1", false),
+	Entry(nil, spec.Record{"1", "e", "n1", "", "s"}, "unknown", "n1", false),
+)
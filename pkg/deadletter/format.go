@@ -0,0 +1,210 @@
+package deadletter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/reader"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/source"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/spec"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+var (
+	_ formatWriter = (*csvWriter)(nil)
+	_ formatWriter = (*jsonWriter)(nil)
+	_ formatWriter = (*parquetWriter)(nil)
+)
+
+type (
+	csvWriter struct {
+		cw *csv.Writer
+	}
+
+	jsonWriter struct {
+		bw    *bufio.Writer
+		paths [][]string
+	}
+
+	parquetWriter struct {
+		pw *parquet.Writer
+		// indices is the record index of each leaf column in the schema order, -1 for the sidecar columns.
+		indices []int
+		// sidecar is the index in SidecarFields of each leaf column, -1 for the record columns.
+		sidecar []int
+	}
+)
+
+func newCSVWriter(w io.Writer, c *source.CSVConfig, header []string) (formatWriter, error) {
+	cw := csv.NewWriter(w)
+	if c != nil {
+		if chars := []rune(c.Delimiter); len(chars) > 0 {
+			cw.Comma = chars[0]
+		}
+	}
+	if header != nil {
+		if err := cw.Write(append(append([]string(nil), header...), SidecarFields...)); err != nil {
+			return nil, err
+		}
+	}
+	return &csvWriter{cw: cw}, nil
+}
+
+func (w *csvWriter) WriteRecord(record spec.Record, sidecar []string) error {
+	return w.cw.Write(append(append([]string(nil), record...), sidecar...))
+}
+
+func (w *csvWriter) Flush() error {
+	w.cw.Flush()
+	return w.cw.Error()
+}
+
+func (w *csvWriter) Close() error {
+	return w.Flush()
+}
+
+// newJSONWriter writes the json lines, the values are set by the json paths of the header.
+func newJSONWriter(w io.Writer, header []string) formatWriter {
+	paths := make([][]string, 0, len(header))
+	for _, field := range header {
+		paths = append(paths, reader.SplitJSONPath(field))
+	}
+	return &jsonWriter{
+		bw:    bufio.NewWriter(w),
+		paths: paths,
+	}
+}
+
+func (w *jsonWriter) WriteRecord(record spec.Record, sidecar []string) error {
+	obj := make(map[string]any, len(w.paths)+len(sidecar))
+	for i, p := range w.paths {
+		if i < len(record) {
+			setJSONValue(obj, p, record[i])
+		}
+	}
+	for i, field := range SidecarFields {
+		obj[field] = sidecar[i]
+	}
+
+	bs, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	_, _ = w.bw.Write(bs)
+	return w.bw.WriteByte('\n')
+}
+
+func (w *jsonWriter) Flush() error {
+	return w.bw.Flush()
+}
+
+func (w *jsonWriter) Close() error {
+	return w.Flush()
+}
+
+// setJSONValue sets the value by the path, the array indices are written as object keys, which are looked up in the same way.
+func setJSONValue(obj map[string]any, path []string, value string) {
+	if len(path) == 0 {
+		return
+	}
+	for _, segment := range path[:len(path)-1] {
+		next, ok := obj[segment].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			obj[segment] = next
+		}
+		obj = next
+	}
+	obj[path[len(path)-1]] = value
+}
+
+// newParquetWriter writes all the columns as strings, the order of the header is kept in the file metadata,
+// since the columns of the parquet schema are sorted by names.
+func newParquetWriter(w io.Writer, header []string) (formatWriter, error) {
+	columns := append(append([]string(nil), header...), SidecarFields...)
+
+	root := parquet.Group{}
+	for _, column := range columns {
+		if err := addParquetColumn(root, strings.Split(column, ".")); err != nil {
+			return nil, err
+		}
+	}
+	schema := parquet.NewSchema("dead_letter", root)
+
+	columnIndices := make(map[string]int, len(columns))
+	for i, column := range columns {
+		columnIndices[column] = i
+	}
+	pw := &parquetWriter{}
+	for _, path := range schema.Columns() {
+		index := columnIndices[strings.Join(path, ".")]
+		if index >= len(header) {
+			pw.indices = append(pw.indices, -1)
+			pw.sidecar = append(pw.sidecar, index-len(header))
+		} else {
+			pw.indices = append(pw.indices, index)
+			pw.sidecar = append(pw.sidecar, -1)
+		}
+	}
+
+	metadata, err := json.Marshal(columns)
+	if err != nil {
+		return nil, err
+	}
+	pw.pw = parquet.NewWriter(w, schema, parquet.KeyValueMetadata(reader.ParquetColumnsMetadataKey, string(metadata)))
+	return pw, nil
+}
+
+func addParquetColumn(g parquet.Group, path []string) error {
+	for i, segment := range path {
+		node, ok := g[segment]
+		if i == len(path)-1 {
+			if ok {
+				return errors.NewImportError(errors.ErrUnsupportedColumn, "duplicate parquet column %s", strings.Join(path, "."))
+			}
+			g[segment] = parquet.String()
+			return nil
+		}
+		if !ok {
+			node = parquet.Group{}
+			g[segment] = node
+		}
+		next, ok := node.(parquet.Group)
+		if !ok {
+			return errors.NewImportError(errors.ErrUnsupportedColumn, "conflict parquet column %s", strings.Join(path, "."))
+		}
+		g = next
+	}
+	return nil
+}
+
+func (w *parquetWriter) WriteRecord(record spec.Record, sidecar []string) error {
+	row := make(parquet.Row, len(w.indices))
+	for i, index := range w.indices {
+		var val string
+		if index >= 0 {
+			if index < len(record) {
+				val = record[index]
+			}
+		} else {
+			val = sidecar[w.sidecar[i]]
+		}
+		row[i] = parquet.ByteArrayValue([]byte(val)).Level(0, 0, i)
+	}
+	_, err := w.pw.WriteRows([]parquet.Row{row})
+	return err
+}
+
+// Flush does nothing, the parquet file is not readable until the footer is written on close.
+func (*parquetWriter) Flush() error {
+	return nil
+}
+
+func (w *parquetWriter) Close() error {
+	return w.pw.Close()
+}
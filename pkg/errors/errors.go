@@ -27,4 +27,26 @@ var (
 	ErrUnsupportedFunction       = stderrors.New("unsupported function")
 	ErrFilterSyntax              = stderrors.New("filter syntax")
 	ErrUnsupportedMode           = stderrors.New("unsupported mode")
+	ErrNoFields                  = stderrors.New("no fields")
+	ErrNoColumn                  = stderrors.New("no column")
+	ErrUnsupportedColumn         = stderrors.New("unsupported column")
+	ErrUnsupportedCompression    = stderrors.New("unsupported compression")
+	ErrNoHeader                  = stderrors.New("no header")
+	ErrInvalidCheckpoint         = stderrors.New("invalid checkpoint")
+	ErrNoCheckpoint              = stderrors.New("no checkpoint")
+	ErrNoDeadLetter              = stderrors.New("no dead letter")
+	ErrSchemaMismatch            = stderrors.New("schema mismatch")
+	ErrSchemaNotReady            = stderrors.New("schema not ready")
+	ErrExecuteFailed             = stderrors.New("execute failed")
+	ErrFailureThreshold          = stderrors.New("failure threshold exceeded")
+	ErrInterrupted               = stderrors.New("interrupted")
+	ErrUnknownDependency         = stderrors.New("unknown dependency")
+	ErrDependencyCycle           = stderrors.New("dependency cycle")
+	ErrUnsupportedDependency     = stderrors.New("unsupported dependency policy")
+	ErrDependencyFailed          = stderrors.New("dependency failed")
+	ErrInvalidTemplate           = stderrors.New("invalid template")
+	ErrUnsupportedEnvelope       = stderrors.New("unsupported envelope")
+	ErrUnsupportedReload         = stderrors.New("unsupported reload")
+	ErrImportCanceled            = stderrors.New("import canceled")
+	ErrStopTimeout               = stderrors.New("stop timeout")
 )
@@ -9,6 +9,7 @@ import (
 const (
 	fieldMessages   = "messages"
 	fieldGraphName  = "graph"
+	fieldFileName   = "file"
 	fieldEdgeName   = "edge"
 	fieldNodeName   = "node"
 	fieldNodeIDName = "nodeID"
@@ -73,6 +74,14 @@ func (e *ImportError) GraphName() string {
 	return e.getFieldString(fieldGraphName)
 }
 
+func (e *ImportError) SetFileName(fileName string) *ImportError {
+	return e.withField(fieldFileName, fileName)
+}
+
+func (e *ImportError) FileName() string {
+	return e.getFieldString(fieldFileName)
+}
+
 func (e *ImportError) SetNodeName(nodeName string) *ImportError {
 	return e.withField(fieldNodeName, nodeName)
 }
@@ -176,6 +185,9 @@ func (e *ImportError) Error() string {
 	if graphName := e.GraphName(); graphName != "" {
 		fields = append(fields, fmt.Sprintf("%s(%s)", fieldGraphName, graphName))
 	}
+	if fileName := e.FileName(); fileName != "" {
+		fields = append(fields, fmt.Sprintf("%s(%s)", fieldFileName, fileName))
+	}
 	if nodeName := e.NodeName(); nodeName != "" {
 		fields = append(fields, fmt.Sprintf("%s(%s)", fieldNodeName, nodeName))
 	}
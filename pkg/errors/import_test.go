@@ -122,6 +122,9 @@ var _ = Describe("ImportError", func() {
 		importError.SetGraphName("")
 		Expect(importError.GraphName()).To(BeEmpty())
 
+		importError.SetFileName("")
+		Expect(importError.FileName()).To(BeEmpty())
+
 		importError.SetNodeName("")
 		Expect(importError.NodeName()).To(BeEmpty())
 
@@ -149,6 +152,9 @@ var _ = Describe("ImportError", func() {
 		importError.SetGraphName("graphName")
 		Expect(importError.GraphName()).To(Equal("graphName"))
 
+		importError.SetFileName("fileName")
+		Expect(importError.FileName()).To(Equal("fileName"))
+
 		importError.SetNodeName("nodeName")
 		Expect(importError.NodeName()).To(Equal("nodeName"))
 
@@ -170,6 +176,7 @@ var _ = Describe("ImportError", func() {
 		Expect(importError.Fields()).To(Equal(map[string]any{
 			"messages":  []string{"test message", "test message 1"},
 			"graph":     "graphName",
+			"file":      "fileName",
 			"node":      "nodeName",
 			"edge":      "edgeName",
 			"nodeID":    "nodeIDName",
@@ -177,7 +184,7 @@ var _ = Describe("ImportError", func() {
 			"record":    []string{"record1", "record2"},
 			"statement": "test statement",
 		}))
-		Expect(importError.Error()).To(Equal("graph(graphName): node(nodeName): edge(edgeName): nodeID(nodeIDName): prop(propName): record([record1 record2]): statement(test statement): messages: test message, test message 1: test error"))
+		Expect(importError.Error()).To(Equal("graph(graphName): file(fileName): node(nodeName): edge(edgeName): nodeID(nodeIDName): prop(propName): record([record1 record2]): statement(test statement): messages: test message, test message 1: test error"))
 	})
 
 	It("withField", func() {
@@ -2,11 +2,30 @@
 package importer
 
 import (
+	stderrors "errors"
+	"fmt"
+	"io"
+	"sync"
 	"time"
 
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/client"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/deadletter"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/spec"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/stats"
+)
+
+const (
+	KindTag  = "tag"
+	KindEdge = "edge"
+)
+
+var (
+	_ io.Closer = (*defaultImporter)(nil)
+	_ Targeter  = (*defaultImporter)(nil)
+	_ Canceler  = (*defaultImporter)(nil)
+	_ Committer = (*defaultImporter)(nil)
+	_ error     = (*BatchError)(nil)
 )
 
 type (
@@ -19,10 +38,33 @@ type (
 		Wait()
 	}
 
+	// Targeter is implemented by the importers which know the tag or edge imported.
+	Targeter interface {
+		Target() stats.Target
+	}
+
+	// Canceler is implemented by the importers which can be canceled, such as when the manager is stopped
+	// but the batches in flight are not finished in time.
+	Canceler interface {
+		// Cancel stops sending the requests and writing the dead letters of the batches in flight,
+		// the subsequent imports fail with errors.ErrImportCanceled.
+		Cancel()
+	}
+
+	// Committer is implemented by the importers which replay the dead letters.
+	Committer interface {
+		// Commit marks all the records of the source are imported or written to the dead letter again,
+		// so that the replayed dead letter file is replaced when closed, see deadletter.Committer.
+		Commit()
+	}
+
 	ImportResp struct {
 		RecordNum int
 		Latency   time.Duration
 		RespTime  time.Duration
+		// Requests are the succeeded requests if the records are imported by more than one request,
+		// such as the bisected batches, the fields above are the sums of them.
+		Requests []ImportResp
 	}
 
 	ImportResult struct {
@@ -30,11 +72,31 @@ type (
 		Err  error
 	}
 
+	// BatchError is returned when the batch is bisected, only the failed records are in it.
+	BatchError struct {
+		// Errs are the errors of the failed parts, the record is attached if the part has only one.
+		Errs []error
+		// Indices are the indices of the failed records in the imported ones.
+		Indices []int
+	}
+
 	Option func(*defaultImporter)
 
 	defaultImporter struct {
-		builder spec.StatementBuilder
-		pool    client.Pool
+		builder    spec.StatementBuilder
+		pool       client.Pool
+		nodeName   string
+		edgeName   string
+		mode       string
+		deadLetter deadletter.Writer
+		filter     func(spec.Record) bool
+		bisect     bool
+		// recordPerRequest sends a request for each record, see WithRecordPerRequest.
+		recordPerRequest bool
+
+		// cancelMu guards the dead letters written, so that none is written once canceled.
+		cancelMu sync.RWMutex
+		canceled bool
 
 		fnAdd  func(delta int)
 		fnDone func()
@@ -78,6 +140,58 @@ func WithClientPool(p client.Pool) Option {
 	}
 }
 
+// WithNodeName sets the node name of the errors.
+func WithNodeName(name string) Option {
+	return func(i *defaultImporter) {
+		i.nodeName = name
+	}
+}
+
+// WithEdgeName sets the edge name of the errors.
+func WithEdgeName(name string) Option {
+	return func(i *defaultImporter) {
+		i.edgeName = name
+	}
+}
+
+// WithMode sets the mode of the statements, such as INSERT, in the stats.
+func WithMode(mode string) Option {
+	return func(i *defaultImporter) {
+		i.mode = mode
+	}
+}
+
+// WithDeadLetter writes the failed records to the dead letter writer, which is closed when the importer closed.
+func WithDeadLetter(w deadletter.Writer) Option {
+	return func(i *defaultImporter) {
+		i.deadLetter = w
+	}
+}
+
+// WithFilter imports only the records which the filter returns true.
+func WithFilter(fn func(spec.Record) bool) Option {
+	return func(i *defaultImporter) {
+		i.filter = fn
+	}
+}
+
+// WithRecordPerRequest sends a request for each record instead of each batch, and only the failed records
+// are failed. It's for the statements which are not idempotent, such as the set expressions of UPDATE,
+// so that a failed request is retried without applying the statements of the other records again.
+func WithRecordPerRequest() Option {
+	return func(i *defaultImporter) {
+		i.recordPerRequest = true
+	}
+}
+
+// WithBisect splits the failed batch and retries the halves recursively if the error is permanent,
+// such as a bad record, until the failed records are isolated.
+func WithBisect() Option {
+	return func(i *defaultImporter) {
+		i.bisect = true
+	}
+}
+
 func WithAddFunc(fn func(delta int)) Option {
 	return func(i *defaultImporter) {
 		i.fnAdd = fn
@@ -97,30 +211,232 @@ func WithWaitFunc(fn func()) Option {
 }
 
 func (i *defaultImporter) Import(records ...spec.Record) (*ImportResp, error) {
+	var indices []int
+	if i.filter != nil {
+		filtered := make(spec.Records, 0, len(records))
+		for idx, record := range records {
+			if i.filter(record) {
+				filtered = append(filtered, record)
+				indices = append(indices, idx)
+			}
+		}
+		if len(filtered) == 0 {
+			return &ImportResp{}, nil
+		}
+		records = filtered
+	}
+
+	if i.recordPerRequest && len(records) > 1 {
+		return i.importRecordPerRequest(records, indices)
+	}
+
+	resp, isPermanent, err := i.execute(records)
+	if err == nil {
+		return resp, nil
+	}
+	if !i.bisect || !isPermanent || len(records) == 1 {
+		return nil, i.importError(err, records)
+	}
+
+	indices = i.recordIndices(records, indices)
+	resp = &ImportResp{}
+	batchErr := &BatchError{}
+	mid := len(records) / 2
+	i.importBisect(records[:mid], indices[:mid], resp, batchErr)
+	i.importBisect(records[mid:], indices[mid:], resp, batchErr)
+	if len(batchErr.Errs) == 0 {
+		// The batch is succeeded when retried.
+		return resp, nil
+	}
+	return resp, batchErr
+}
+
+func (i *defaultImporter) importRecordPerRequest(records spec.Records, indices []int) (*ImportResp, error) {
+	indices = i.recordIndices(records, indices)
+	resp := &ImportResp{}
+	batchErr := &BatchError{}
+	for idx := range records {
+		r, _, err := i.execute(records[idx : idx+1])
+		if err != nil {
+			err = errors.AsOrNewImportError(err).SetRecord(records[idx])
+			batchErr.Errs = append(batchErr.Errs, i.importError(err, records[idx:idx+1]))
+			batchErr.Indices = append(batchErr.Indices, indices[idx])
+			continue
+		}
+		resp.add(r)
+	}
+	if len(batchErr.Errs) == 0 {
+		return resp, nil
+	}
+	return resp, batchErr
+}
+
+// recordIndices returns the indices of the records in the imported ones, which are the indices filtered if any.
+func (*defaultImporter) recordIndices(records spec.Records, indices []int) []int {
+	if indices != nil {
+		return indices
+	}
+	indices = make([]int, len(records))
+	for idx := range indices {
+		indices[idx] = idx
+	}
+	return indices
+}
+
+func (i *defaultImporter) importBisect(records spec.Records, indices []int, resp *ImportResp, batchErr *BatchError) {
+	r, isPermanent, err := i.execute(records)
+	if err == nil {
+		resp.add(r)
+		return
+	}
+	if isPermanent && len(records) > 1 {
+		mid := len(records) / 2
+		i.importBisect(records[:mid], indices[:mid], resp, batchErr)
+		i.importBisect(records[mid:], indices[mid:], resp, batchErr)
+		return
+	}
+	if len(records) == 1 {
+		err = errors.AsOrNewImportError(err).SetRecord(records[0])
+	}
+	batchErr.Errs = append(batchErr.Errs, i.importError(err, records))
+	batchErr.Indices = append(batchErr.Indices, indices...)
+}
+
+// execute builds and executes the statement, isPermanent reports whether the error is caused by the records,
+// which fails again when retried, it is only checked in bisect mode.
+func (i *defaultImporter) execute(records spec.Records) (resp *ImportResp, isPermanent bool, err error) {
+	if i.isCanceled() {
+		return nil, false, errors.NewImportError(errors.ErrImportCanceled)
+	}
+
 	statement, nRecord, err := i.builder.Build(records...)
 	if err != nil {
-		return nil, err
+		return nil, true, err
 	}
 
 	if nRecord == 0 {
-		return &ImportResp{}, nil
+		return &ImportResp{}, false, nil
 	}
 
-	resp, err := i.pool.Execute(statement)
+	execResp, err := i.pool.Execute(statement)
 	if err != nil {
-		return nil, errors.NewImportError(err).
+		return nil, false, errors.NewImportError(err).
 			SetStatement(statement)
 	}
-	if !resp.IsSucceed() {
-		return nil, errors.NewImportError(err, "the execute error is %s ", resp.GetError()).
+	if !execResp.IsSucceed() {
+		isPermanent = i.bisect && execResp.IsPermanentError()
+		return nil, isPermanent, errors.NewImportError(errors.ErrExecuteFailed, "the execute error is %s ", execResp.GetError()).
 			SetStatement(statement)
 	}
 
 	return &ImportResp{
 		RecordNum: nRecord,
-		RespTime:  resp.GetRespTime(),
-		Latency:   resp.GetLatency(),
-	}, nil
+		RespTime:  execResp.GetRespTime(),
+		Latency:   execResp.GetLatency(),
+	}, false, nil
+}
+
+// add sums the succeeded request req, it's skipped if no request is sent since no record is built.
+func (r *ImportResp) add(req *ImportResp) {
+	if req.RecordNum == 0 {
+		return
+	}
+	r.RecordNum += req.RecordNum
+	r.Latency += req.Latency
+	r.RespTime += req.RespTime
+	r.Requests = append(r.Requests, *req)
+}
+
+// PerRequest returns the resp of each succeeded request, which is r itself if it's a single request.
+func (r *ImportResp) PerRequest() []ImportResp {
+	if len(r.Requests) == 0 {
+		return []ImportResp{*r}
+	}
+	return r.Requests
+}
+
+func AsBatchError(err error) (*BatchError, bool) {
+	if e := new(BatchError); stderrors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
+
+func (e *BatchError) Error() string {
+	if len(e.Errs) == 0 {
+		return fmt.Sprintf("%d records failed", len(e.Indices))
+	}
+	return fmt.Sprintf("%d records failed, the first error is %s", len(e.Indices), e.Errs[0])
+}
+
+func (e *BatchError) Unwrap() []error {
+	return e.Errs
+}
+
+// Close closes the dead letter writer.
+func (i *defaultImporter) Close() error {
+	if i.deadLetter != nil {
+		return i.deadLetter.Close()
+	}
+	return nil
+}
+
+// importError sets the names, and writes the records to the dead letter writer.
+func (i *defaultImporter) importError(err error, records spec.Records) error {
+	if i.nodeName == "" && i.edgeName == "" && i.deadLetter == nil {
+		return err
+	}
+	e := errors.AsOrNewImportError(err).SetNodeName(i.nodeName).SetEdgeName(i.edgeName)
+	if i.deadLetter != nil {
+		i.cancelMu.RLock()
+		defer i.cancelMu.RUnlock()
+		// The canceled records are not failed, they are imported again when resumed.
+		if !i.canceled {
+			if werr := i.deadLetter.Write(e, records...); werr != nil {
+				e.AppendMessage("write dead letter failed: %s", werr)
+			}
+		}
+	}
+	return e
+}
+
+// Cancel waits for the dead letters being written, and then stops the subsequent requests and dead letters.
+func (i *defaultImporter) Cancel() {
+	i.cancelMu.Lock()
+	defer i.cancelMu.Unlock()
+	i.canceled = true
+}
+
+// Commit commits the dead letter writer if it's replaying.
+func (i *defaultImporter) Commit() {
+	if c, ok := i.deadLetter.(deadletter.Committer); ok {
+		c.Commit()
+	}
+}
+
+func (i *defaultImporter) isCanceled() bool {
+	i.cancelMu.RLock()
+	defer i.cancelMu.RUnlock()
+	return i.canceled
+}
+
+// Target returns the tag or edge set by WithNodeName or WithEdgeName, and the mode set by WithMode.
+func (i *defaultImporter) Target() stats.Target {
+	switch {
+	case i.nodeName != "":
+		return stats.Target{Kind: KindTag, Name: i.nodeName, Mode: i.mode}
+	case i.edgeName != "":
+		return stats.Target{Kind: KindEdge, Name: i.edgeName, Mode: i.mode}
+	}
+	return stats.Target{}
+}
+
+// TargetOf returns the tag or edge imported by the importer, it's empty if unknown.
+func TargetOf(i Importer) stats.Target {
+	if t, ok := i.(Targeter); ok {
+		return t.Target()
+	}
+	return stats.Target{}
 }
 
 func (i *defaultImporter) Add(delta int) {
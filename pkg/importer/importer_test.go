@@ -2,13 +2,17 @@ package importer
 
 import (
 	stderrors "errors"
+	"io"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/client"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/deadletter"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/spec"
 	specbase "github.com/vesoft-inc/nebula-importer/v4/pkg/spec/base"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/stats"
 
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
@@ -70,6 +74,7 @@ var _ = Describe("Importer", func() {
 			Expect(ok).To(BeTrue())
 			Expect(importError.Messages).To(ContainElement(ContainSubstring("status failed")))
 			Expect(importError.Statement()).NotTo(BeEmpty())
+			Expect(stderrors.Is(err, errors.ErrExecuteFailed)).To(BeTrue())
 			Expect(resp).To(BeNil())
 		})
 
@@ -88,6 +93,7 @@ var _ = Describe("Importer", func() {
 			Expect(resp).NotTo(BeNil())
 			Expect(resp.Latency).To(Equal(time.Microsecond * time.Duration(10)))
 			Expect(resp.RespTime).To(Equal(time.Microsecond * time.Duration(12)))
+			Expect(resp.PerRequest()).To(Equal([]ImportResp{*resp}))
 		})
 
 		It("execute successfully with Add, Wait and Done", func() {
@@ -140,4 +146,277 @@ var _ = Describe("Importer", func() {
 			Expect(resp.RespTime).To(Equal(time.Microsecond * time.Duration(12)))
 		})
 	})
+
+	Describe("dead letter", func() {
+		var mockDeadLetter *deadletter.MockWriter
+		BeforeEach(func() {
+			mockDeadLetter = deadletter.NewMockWriter(ctrl)
+		})
+
+		It("build failed", func() {
+			mockBuilder.EXPECT().Build(gomock.Any()).Return("", 0, errors.ErrNoRecord)
+			mockDeadLetter.EXPECT().Write(gomock.Any(), spec.Record{"id"}).DoAndReturn(func(err error, _ ...spec.Record) error {
+				importError, ok := errors.AsImportError(err)
+				Expect(ok).To(BeTrue())
+				Expect(importError.NodeName()).To(Equal("n1"))
+				return nil
+			})
+
+			i := New(mockBuilder, mockClientPool, WithNodeName("n1"), WithDeadLetter(mockDeadLetter))
+			resp, err := i.Import(spec.Record{"id"})
+			Expect(err).To(HaveOccurred())
+			Expect(stderrors.Is(err, errors.ErrNoRecord)).To(BeTrue())
+			Expect(resp).To(BeNil())
+		})
+
+		It("execute failed", func() {
+			mockBuilder.EXPECT().Build(gomock.Any()).Return("statement", 2, nil)
+			mockClientPool.EXPECT().Execute(gomock.Any()).Return(nil, stderrors.New("test error"))
+			mockDeadLetter.EXPECT().Write(gomock.Any(), spec.Record{"id1"}, spec.Record{"id2"}).DoAndReturn(func(err error, _ ...spec.Record) error {
+				importError, ok := errors.AsImportError(err)
+				Expect(ok).To(BeTrue())
+				Expect(importError.EdgeName()).To(Equal("e1"))
+				Expect(importError.Statement()).To(Equal("statement"))
+				return nil
+			})
+
+			i := New(mockBuilder, mockClientPool, WithEdgeName("e1"), WithDeadLetter(mockDeadLetter))
+			resp, err := i.Import(spec.Record{"id1"}, spec.Record{"id2"})
+			Expect(err).To(HaveOccurred())
+			Expect(resp).To(BeNil())
+		})
+
+		It("write failed", func() {
+			mockBuilder.EXPECT().Build(gomock.Any()).Return("statement", 1, nil)
+			mockClientPool.EXPECT().Execute(gomock.Any()).Times(1).Return(mockResponse, nil)
+			mockResponse.EXPECT().IsSucceed().Times(1).Return(false)
+			mockResponse.EXPECT().GetError().Times(1).Return(stderrors.New("status failed"))
+			mockDeadLetter.EXPECT().Write(gomock.Any(), gomock.Any()).Return(stderrors.New("disk full"))
+
+			i := New(mockBuilder, mockClientPool, WithDeadLetter(mockDeadLetter))
+			_, err := i.Import(spec.Record{"id"})
+			Expect(err).To(HaveOccurred())
+			importError, ok := errors.AsImportError(err)
+			Expect(ok).To(BeTrue())
+			Expect(importError.Messages).To(ContainElement(ContainSubstring("disk full")))
+		})
+
+		It("close", func() {
+			mockDeadLetter.EXPECT().Close().Return(nil)
+
+			i := New(mockBuilder, mockClientPool, WithDeadLetter(mockDeadLetter))
+			Expect(i.(io.Closer).Close()).NotTo(HaveOccurred())
+
+			i = New(mockBuilder, mockClientPool)
+			Expect(i.(io.Closer).Close()).NotTo(HaveOccurred())
+		})
+
+		It("canceled", func() {
+			i := New(mockBuilder, mockClientPool, WithNodeName("n1"), WithDeadLetter(mockDeadLetter))
+
+			// The batch in flight is canceled while executing, no dead letter is written.
+			mockBuilder.EXPECT().Build(gomock.Any()).Return("statement", 1, nil)
+			mockClientPool.EXPECT().Execute(gomock.Any()).DoAndReturn(func(string) (client.Response, error) {
+				i.(Canceler).Cancel()
+				return nil, stderrors.New("test error")
+			})
+			resp, err := i.Import(spec.Record{"id1"})
+			Expect(err).To(HaveOccurred())
+			Expect(resp).To(BeNil())
+
+			// The subsequent imports send no requests.
+			resp, err = i.Import(spec.Record{"id2"})
+			Expect(stderrors.Is(err, errors.ErrImportCanceled)).To(BeTrue())
+			Expect(resp).To(BeNil())
+		})
+	})
+
+	Describe("filter", func() {
+		It("filtered", func() {
+			mockBuilder.EXPECT().Build(spec.Record{"id2"}).Times(1).Return("statement", 1, nil)
+			mockClientPool.EXPECT().Execute(gomock.Any()).Times(1).Return(mockResponse, nil)
+			mockResponse.EXPECT().IsSucceed().Times(1).Return(true)
+			mockResponse.EXPECT().GetLatency().Times(1).Return(time.Microsecond * 10)
+			mockResponse.EXPECT().GetRespTime().Times(1).Return(time.Microsecond * 12)
+
+			i := New(mockBuilder, mockClientPool, WithFilter(func(record spec.Record) bool {
+				return record[0] == "id2"
+			}))
+			resp, err := i.Import(spec.Record{"id1"}, spec.Record{"id2"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.RecordNum).To(Equal(1))
+
+			resp, err = i.Import(spec.Record{"id1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.RecordNum).To(Equal(0))
+		})
+	})
+
+	Describe("bisect", func() {
+		var (
+			mockSucceededResponse *client.MockResponse
+			mockDeadLetter        *deadletter.MockWriter
+		)
+		BeforeEach(func() {
+			mockSucceededResponse = client.NewMockResponse(ctrl)
+			mockDeadLetter = deadletter.NewMockWriter(ctrl)
+
+			mockBuilder.EXPECT().Build(gomock.Any()).AnyTimes().DoAndReturn(func(records ...spec.Record) (string, int, error) {
+				ids := make([]string, 0, len(records))
+				for _, record := range records {
+					if record[0] == "unbuildable" {
+						return "", 0, errors.ErrNoRecord
+					}
+					ids = append(ids, record[0])
+				}
+				return strings.Join(ids, ","), len(records), nil
+			})
+			mockClientPool.EXPECT().Execute(gomock.Any()).AnyTimes().DoAndReturn(func(statement string) (client.Response, error) {
+				if strings.Contains(statement, "bad") {
+					return mockResponse, nil
+				}
+				if strings.Contains(statement, "down") {
+					return nil, stderrors.New("connection refused")
+				}
+				return mockSucceededResponse, nil
+			})
+			mockResponse.EXPECT().IsSucceed().AnyTimes().Return(false)
+			mockResponse.EXPECT().GetError().AnyTimes().Return(stderrors.New("status failed"))
+			mockSucceededResponse.EXPECT().IsSucceed().AnyTimes().Return(true)
+			mockSucceededResponse.EXPECT().GetLatency().AnyTimes().Return(time.Microsecond * 10)
+			mockSucceededResponse.EXPECT().GetRespTime().AnyTimes().Return(time.Microsecond * 12)
+		})
+
+		It("isolate the bad records", func() {
+			mockResponse.EXPECT().IsPermanentError().AnyTimes().Return(true)
+			mockDeadLetter.EXPECT().Write(gomock.Any(), spec.Record{"bad1"}).Return(nil)
+			mockDeadLetter.EXPECT().Write(gomock.Any(), spec.Record{"unbuildable"}).Return(nil)
+
+			i := New(mockBuilder, mockClientPool, WithNodeName("n1"), WithDeadLetter(mockDeadLetter), WithBisect())
+			resp, err := i.Import(
+				spec.Record{"id0"},
+				spec.Record{"bad1"},
+				spec.Record{"id2"},
+				spec.Record{"id3"},
+				spec.Record{"id4"},
+				spec.Record{"unbuildable"},
+			)
+			Expect(err).To(HaveOccurred())
+			Expect(resp).NotTo(BeNil())
+			Expect(resp.RecordNum).To(Equal(4))
+			Expect(resp.Latency).To(Equal(time.Microsecond * 10 * 4))
+			Expect(resp.PerRequest()).To(Equal([]ImportResp{
+				{RecordNum: 1, Latency: time.Microsecond * 10, RespTime: time.Microsecond * 12},
+				{RecordNum: 1, Latency: time.Microsecond * 10, RespTime: time.Microsecond * 12},
+				{RecordNum: 1, Latency: time.Microsecond * 10, RespTime: time.Microsecond * 12},
+				{RecordNum: 1, Latency: time.Microsecond * 10, RespTime: time.Microsecond * 12},
+			}))
+
+			var batchErr *BatchError
+			Expect(stderrors.As(err, &batchErr)).To(BeTrue())
+			Expect(batchErr.Indices).To(Equal([]int{1, 5}))
+			Expect(batchErr.Errs).To(HaveLen(2))
+			Expect(stderrors.Is(err, errors.ErrNoRecord)).To(BeTrue())
+
+			importError, ok := errors.AsImportError(batchErr.Errs[0])
+			Expect(ok).To(BeTrue())
+			Expect(importError.Record()).To(Equal([]string{"bad1"}))
+			Expect(importError.NodeName()).To(Equal("n1"))
+			Expect(importError.Statement()).To(Equal("bad1"))
+			Expect(err.Error()).To(ContainSubstring("2 records failed"))
+		})
+
+		It("record per request", func() {
+			mockDeadLetter.EXPECT().Write(gomock.Any(), spec.Record{"down2"}).Return(nil)
+			mockDeadLetter.EXPECT().Write(gomock.Any(), spec.Record{"bad3"}).Return(nil)
+
+			i := New(mockBuilder, mockClientPool, WithEdgeName("e1"), WithDeadLetter(mockDeadLetter), WithRecordPerRequest(),
+				WithFilter(func(record spec.Record) bool {
+					return record[0] != "skipped"
+				}),
+			)
+			resp, err := i.Import(
+				spec.Record{"id0"},
+				spec.Record{"skipped"},
+				spec.Record{"down2"},
+				spec.Record{"bad3"},
+				spec.Record{"id4"},
+			)
+			Expect(err).To(HaveOccurred())
+			Expect(resp.RecordNum).To(Equal(2))
+			var batchErr *BatchError
+			Expect(stderrors.As(err, &batchErr)).To(BeTrue())
+			Expect(batchErr.Indices).To(Equal([]int{2, 3}))
+			importError, ok := errors.AsImportError(batchErr.Errs[1])
+			Expect(ok).To(BeTrue())
+			Expect(importError.Record()).To(Equal([]string{"bad3"}))
+			Expect(importError.EdgeName()).To(Equal("e1"))
+			Expect(importError.Statement()).To(Equal("bad3"))
+
+			// All the records are succeeded.
+			resp, err = i.Import(spec.Record{"id0"}, spec.Record{"id1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.RecordNum).To(Equal(2))
+			Expect(resp.RespTime).To(Equal(time.Microsecond * 12 * 2))
+			Expect(resp.PerRequest()).To(Equal([]ImportResp{
+				{RecordNum: 1, Latency: time.Microsecond * 10, RespTime: time.Microsecond * 12},
+				{RecordNum: 1, Latency: time.Microsecond * 10, RespTime: time.Microsecond * 12},
+			}))
+		})
+
+		It("not permanent error", func() {
+			mockResponse.EXPECT().IsPermanentError().Times(1).Return(false)
+
+			i := New(mockBuilder, mockClientPool, WithBisect())
+			resp, err := i.Import(spec.Record{"id0"}, spec.Record{"bad1"})
+			Expect(err).To(HaveOccurred())
+			Expect(resp).To(BeNil())
+			var batchErr *BatchError
+			Expect(stderrors.As(err, &batchErr)).To(BeFalse())
+		})
+
+		It("not permanent error when bisecting", func() {
+			mockResponse.EXPECT().IsPermanentError().AnyTimes().Return(true)
+
+			i := New(mockBuilder, mockClientPool, WithBisect(), WithFilter(func(record spec.Record) bool {
+				return record[0] != "skipped"
+			}))
+			resp, err := i.Import(
+				spec.Record{"skipped"},
+				spec.Record{"down0"},
+				spec.Record{"down1"},
+				spec.Record{"bad2"},
+				spec.Record{"id3"},
+			)
+			Expect(err).To(HaveOccurred())
+			Expect(resp.RecordNum).To(Equal(1))
+			var batchErr *BatchError
+			Expect(stderrors.As(err, &batchErr)).To(BeTrue())
+			Expect(batchErr.Indices).To(Equal([]int{1, 2, 3}))
+			Expect(batchErr.Errs).To(HaveLen(2))
+			importError, ok := errors.AsImportError(batchErr.Errs[0])
+			Expect(ok).To(BeTrue())
+			Expect(importError.Record()).To(BeNil())
+		})
+
+		It("single record", func() {
+			i := New(mockBuilder, mockClientPool, WithBisect())
+			mockResponse.EXPECT().IsPermanentError().Times(1).Return(true)
+			resp, err := i.Import(spec.Record{"bad0"})
+			Expect(err).To(HaveOccurred())
+			Expect(resp).To(BeNil())
+			var batchErr *BatchError
+			Expect(stderrors.As(err, &batchErr)).To(BeFalse())
+		})
+	})
+
+	DescribeTable("TargetOf",
+		func(i Importer, expected stats.Target) {
+			Expect(TargetOf(i)).To(Equal(expected))
+		},
+		Entry("tag", New(nil, nil, WithNodeName("n1")), stats.Target{Kind: KindTag, Name: "n1"}),
+		Entry("edge", New(nil, nil, WithEdgeName("e1"), WithMode("DELETE")), stats.Target{Kind: KindEdge, Name: "e1", Mode: "DELETE"}),
+		Entry("unknown", New(nil, nil, WithMode("INSERT")), stats.Target{}),
+		Entry("not targeter", &MockImporter{}, stats.Target{}),
+	)
 })
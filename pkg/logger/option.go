@@ -1,10 +1,15 @@
 package logger
 
-import "time"
+import (
+	"io"
+	"os"
+	"time"
+)
 
 var defaultOptions = options{
 	level:      InfoLevel,
 	console:    true,
+	consoleOut: os.Stdout,
 	timeLayout: time.RFC3339,
 }
 
@@ -13,6 +18,7 @@ type (
 		level      Level
 		fields     Fields
 		console    bool
+		consoleOut io.Writer
 		timeLayout string
 		files      []string
 	}
@@ -43,6 +49,13 @@ func WithConsole(console bool) Option {
 	}
 }
 
+// WithConsoleWriter sets the writer of the console logs, which is the stdout by default.
+func WithConsoleWriter(w io.Writer) Option {
+	return func(o *options) {
+		o.consoleOut = w
+	}
+}
+
 func WithTimeLayout(layout string) Option {
 	return func(o *options) {
 		o.timeLayout = layout
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"os"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -47,6 +48,12 @@ var _ = Describe("Option", func() {
 		Expect(o.timeLayout).To(Equal(time.RFC3339))
 	})
 
+	It("WithConsoleWriter", func() {
+		o := options{}
+		WithConsoleWriter(os.Stderr)(&o)
+		Expect(o.consoleOut).To(Equal(os.Stderr))
+	})
+
 	It("nopLogger", func() {
 		files := []string{"f1", "f2"}
 		o := options{}
@@ -23,10 +23,14 @@ func newZapLogger(o *options) (*zapLogger, error) {
 		encoderCfg.EncodeTime = zapcore.TimeEncoderOfLayout(o.timeLayout)
 	}
 	if o.console {
+		consoleOut := o.consoleOut
+		if consoleOut == nil {
+			consoleOut = os.Stdout
+		}
 		cores = append(cores,
 			zapcore.NewCore(
 				zapcore.NewJSONEncoder(encoderCfg),
-				zapcore.Lock(os.Stdout),
+				zapcore.Lock(zapcore.AddSync(consoleOut)),
 				atomicLevel,
 			),
 		)
@@ -0,0 +1,221 @@
+package manager
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/logger"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/source"
+)
+
+const (
+	// DependencyCompleted starts the source once its dependencies are completed, even if some records failed.
+	DependencyCompleted DependencyPolicy = "completed"
+	// DependencySucceeded starts the source once its dependencies are completed without failures,
+	// otherwise the source is skipped.
+	DependencySucceeded DependencyPolicy = "succeeded"
+)
+
+type (
+	DependencyPolicy string
+
+	// Dependency is the node of the source in the DAG of the sources. The sources with the same name are
+	// the same node, which is completed once all of them are completed, such as the files matched by a wildcard.
+	Dependency struct {
+		Name      string
+		DependsOn []string
+		Policy    DependencyPolicy
+	}
+
+	dependentSource struct {
+		source.Source
+		dependency Dependency
+	}
+
+	// sourceGroup is the sources with the same name, done is closed once all of them are completed.
+	sourceGroup struct {
+		pending int
+		failed  bool
+		done    chan struct{}
+	}
+
+	// sourceProgress is the batches in flight of a source, and whether any record of it failed.
+	sourceProgress struct {
+		batches sync.WaitGroup
+		failed  atomic.Bool
+		// completed is set once all the records are read, dropped is set if any batch is not submitted.
+		completed atomic.Bool
+		dropped   atomic.Bool
+	}
+)
+
+// NewDependentSource returns the source which is started by the manager after its dependencies.
+func NewDependentSource(s source.Source, d Dependency) source.Source {
+	return &dependentSource{
+		Source:     s,
+		dependency: d,
+	}
+}
+
+// dependencyOf returns the dependency of the source, it's empty if the source is not a dependent source.
+func dependencyOf(s source.Source) Dependency {
+	if ds, ok := s.(*dependentSource); ok {
+		return ds.dependency
+	}
+	return Dependency{}
+}
+
+// ValidateDependencies checks the policies, and that the dependencies exist and have no cycles.
+func ValidateDependencies(deps ...Dependency) error {
+	graph := make(map[string][]string, len(deps))
+	for _, d := range deps {
+		if d.Name != "" {
+			graph[d.Name] = append(graph[d.Name], d.DependsOn...)
+		}
+	}
+	for _, d := range deps {
+		switch d.Policy {
+		case "", DependencyCompleted, DependencySucceeded:
+		default:
+			return errors.NewImportError(errors.ErrUnsupportedDependency, "source %s: %s", d.Name, d.Policy)
+		}
+		for _, name := range d.DependsOn {
+			if _, ok := graph[name]; !ok {
+				return errors.NewImportError(errors.ErrUnknownDependency, "source %s depends on %s", d.Name, name)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	states := make(map[string]int, len(graph))
+	var (
+		path  []string
+		visit func(name string) error
+	)
+	visit = func(name string) error {
+		switch states[name] {
+		case visiting:
+			for i := range path {
+				if path[i] == name {
+					return errors.NewImportError(errors.ErrDependencyCycle, "%s", strings.Join(append(path[i:], name), " -> "))
+				}
+			}
+		case visited:
+			return nil
+		}
+		states[name] = visiting
+		path = append(path, name)
+		for _, dep := range graph[name] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		states[name] = visited
+		return nil
+	}
+	for _, d := range deps {
+		if d.Name == "" {
+			continue
+		}
+		if err := visit(d.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *defaultManager) addDependency(d Dependency) {
+	if d.Name == "" && len(d.DependsOn) == 0 {
+		return
+	}
+	m.groupsMu.Lock()
+	defer m.groupsMu.Unlock()
+	m.dependencies = append(m.dependencies, d)
+	if d.Name == "" {
+		return
+	}
+	g, ok := m.groups[d.Name]
+	if !ok {
+		g = &sourceGroup{done: make(chan struct{})}
+		m.groups[d.Name] = g
+	}
+	g.pending++
+}
+
+// waitDependencies waits until the dependencies of the source are completed, it returns false if the source
+// should be skipped, since the manager is stopped, or a dependency failed with the succeeded policy.
+func (m *defaultManager) waitDependencies(name string, d Dependency) bool {
+	if len(d.DependsOn) == 0 {
+		return true
+	}
+	select {
+	case <-m.chStart:
+	case <-m.done:
+		return false
+	}
+
+	logSourceField := logger.Field{Key: "source", Value: name}
+	for _, dep := range d.DependsOn {
+		// The dependencies exist, which are validated when start.
+		m.groupsMu.Lock()
+		g := m.groups[dep]
+		m.groupsMu.Unlock()
+
+		select {
+		case <-g.done:
+		case <-m.done:
+			return false
+		case <-m.aborted:
+			return false
+		}
+
+		m.groupsMu.Lock()
+		failed := g.failed
+		m.groupsMu.Unlock()
+		if failed && d.Policy == DependencySucceeded {
+			err := errors.NewImportError(errors.ErrDependencyFailed, "manager: skip the source, since its dependency %s failed", dep).
+				SetGraphName(m.graphName)
+			m.logError(err, "", logSourceField)
+			m.groupsMu.Lock()
+			m.skippedSources = append(m.skippedSources, name)
+			m.groupsMu.Unlock()
+			return false
+		}
+	}
+	m.logger.Info("manager: dependencies of the source completed", logSourceField)
+	return true
+}
+
+// completeSource completes the source in its group, the group is done once all the sources of it are completed.
+func (m *defaultManager) completeSource(d Dependency, failed bool) {
+	if d.Name == "" {
+		return
+	}
+	m.groupsMu.Lock()
+	defer m.groupsMu.Unlock()
+	g := m.groups[d.Name]
+	g.failed = g.failed || failed
+	g.pending--
+	if g.pending == 0 {
+		close(g.done)
+	}
+}
+
+// dependencyError returns the error if any source is skipped since its dependencies failed.
+func (m *defaultManager) dependencyError() error {
+	m.groupsMu.Lock()
+	defer m.groupsMu.Unlock()
+	if len(m.skippedSources) == 0 {
+		return nil
+	}
+	return errors.NewImportError(errors.ErrDependencyFailed,
+		"manager: %d sources are skipped since their dependencies failed: %s", len(m.skippedSources), strings.Join(m.skippedSources, ", ")).
+		SetGraphName(m.graphName)
+}
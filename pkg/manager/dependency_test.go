@@ -0,0 +1,152 @@
+package manager
+
+import (
+	stderrors "errors"
+	"io"
+	"sync/atomic"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/client"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/importer"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/logger"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/reader"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/source"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/spec"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Dependency", func() {
+	DescribeTable("ValidateDependencies",
+		func(deps []Dependency, expectErr error, expectMsg string) {
+			err := ValidateDependencies(deps...)
+			if expectErr == nil {
+				Expect(err).NotTo(HaveOccurred())
+				return
+			}
+			Expect(stderrors.Is(err, expectErr)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring(expectMsg))
+		},
+		Entry("nil", nil, nil, ""),
+		Entry("no dependencies", []Dependency{{Name: "a"}, {Name: "b"}}, nil, ""),
+		Entry("dag", []Dependency{
+			{Name: "a"},
+			{Name: "b", DependsOn: []string{"a"}},
+			{Name: "c", DependsOn: []string{"a", "b"}, Policy: DependencySucceeded},
+			{DependsOn: []string{"c"}, Policy: DependencyCompleted},
+		}, nil, ""),
+		Entry("same name", []Dependency{{Name: "a"}, {Name: "a"}, {Name: "b", DependsOn: []string{"a"}}}, nil, ""),
+		Entry("unsupported policy", []Dependency{{Name: "a", Policy: "unknown"}}, errors.ErrUnsupportedDependency, "unknown"),
+		Entry("unknown dependency", []Dependency{{Name: "a", DependsOn: []string{"b"}}}, errors.ErrUnknownDependency, "source a depends on b"),
+		Entry("self cycle", []Dependency{{Name: "a", DependsOn: []string{"a"}}}, errors.ErrDependencyCycle, "a -> a"),
+		Entry("cycle", []Dependency{
+			{Name: "a", DependsOn: []string{"c"}},
+			{Name: "b", DependsOn: []string{"a"}},
+			{Name: "c", DependsOn: []string{"b"}},
+		}, errors.ErrDependencyCycle, "a -> c -> b -> a"),
+	)
+
+	Describe("Run", func() {
+		var (
+			ctrl            *gomock.Controller
+			mockClientPool  *client.MockPool
+			mockSources     [2]*source.MockSource
+			mockReaders     [2]*reader.MockBatchRecordReader
+			mockImporters   [2]*importer.MockImporter
+			dependentSource source.Source
+			m               Manager
+		)
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+			mockClientPool = client.NewMockPool(ctrl)
+			for i := range mockSources {
+				mockSources[i] = source.NewMockSource(ctrl)
+				mockReaders[i] = reader.NewMockBatchRecordReader(ctrl)
+				mockImporters[i] = importer.NewMockImporter(ctrl)
+			}
+
+			l, err := logger.New(logger.WithLevel(logger.ErrorLevel))
+			Expect(err).NotTo(HaveOccurred())
+			m = New(mockClientPool, WithBatch(1), WithLogger(l))
+
+			mockClientPool.EXPECT().Open().Return(nil)
+			mockSources[0].EXPECT().Name().Times(2).Return("source1")
+			for i := range mockSources {
+				mockSources[i].EXPECT().Open().Return(nil)
+				mockSources[i].EXPECT().Size().Return(int64(1024), nil)
+				mockSources[i].EXPECT().Close().Return(nil)
+			}
+			mockReaders[0].EXPECT().ReadBatch().Return(1, spec.Records{{"id1"}}, nil)
+			mockReaders[0].EXPECT().ReadBatch().Return(0, spec.Records(nil), io.EOF)
+		})
+
+		AfterEach(func() {
+			ctrl.Finish()
+		})
+
+		run := func() error {
+			// The dependent source is imported first, but it's started after its dependency.
+			Expect(m.Import(dependentSource, mockReaders[1], mockImporters[1])).NotTo(HaveOccurred())
+			Expect(m.Import(
+				NewDependentSource(mockSources[0], Dependency{Name: "source1"}),
+				mockReaders[0],
+				mockImporters[0],
+			)).NotTo(HaveOccurred())
+			Expect(m.Start()).NotTo(HaveOccurred())
+			return m.Wait()
+		}
+
+		It("completed", func() {
+			dependentSource = NewDependentSource(mockSources[1], Dependency{Name: "source2", DependsOn: []string{"source1"}})
+
+			var imported atomic.Bool
+			mockImporters[0].EXPECT().Add(1).Times(2)
+			mockImporters[0].EXPECT().Done().Times(2)
+			mockImporters[0].EXPECT().Wait()
+			mockImporters[0].EXPECT().Import(gomock.Any()).DoAndReturn(func(...spec.Record) (*importer.ImportResp, error) {
+				imported.Store(true)
+				return nil, stderrors.New("test error")
+			})
+
+			mockSources[1].EXPECT().Name().Times(2).Return("source2")
+			mockReaders[1].EXPECT().ReadBatch().DoAndReturn(func() (int, spec.Records, error) {
+				Expect(imported.Load()).To(BeTrue())
+				return 1, spec.Records{{"id2"}}, nil
+			})
+			mockReaders[1].EXPECT().ReadBatch().Return(0, spec.Records(nil), io.EOF)
+			mockImporters[1].EXPECT().Add(1).Times(2)
+			mockImporters[1].EXPECT().Done().Times(2)
+			mockImporters[1].EXPECT().Wait()
+			mockImporters[1].EXPECT().Import(gomock.Any()).Return(&importer.ImportResp{}, nil)
+
+			Expect(run()).NotTo(HaveOccurred())
+			Expect(m.Stats().FailedRecords).To(Equal(int64(1)))
+			Expect(m.Stats().TotalRecords).To(Equal(int64(2)))
+		})
+
+		It("succeeded", func() {
+			dependentSource = NewDependentSource(mockSources[1], Dependency{
+				Name:      "source2",
+				DependsOn: []string{"source1"},
+				Policy:    DependencySucceeded,
+			})
+
+			mockImporters[0].EXPECT().Add(1).Times(2)
+			mockImporters[0].EXPECT().Done().Times(2)
+			mockImporters[0].EXPECT().Wait()
+			mockImporters[0].EXPECT().Import(gomock.Any()).Return(nil, stderrors.New("test error"))
+
+			// The dependent source is skipped, since its dependency failed.
+			mockSources[1].EXPECT().Name().Times(1).Return("source2")
+			mockImporters[1].EXPECT().Add(1)
+			mockImporters[1].EXPECT().Done()
+
+			err := run()
+			Expect(stderrors.Is(err, errors.ErrDependencyFailed)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("source2"))
+			Expect(m.Stats().FailedRecords).To(Equal(int64(1)))
+		})
+	})
+})
@@ -3,14 +3,17 @@ package manager
 import "time"
 
 const (
-	BeforeHook = HookName("before")
-	AfterHook  = HookName("after")
+	BeforeHook    = HookName("before")
+	AfterHook     = HookName("after")
+	OnFailureHook = HookName("onFailure")
 )
 
 type (
 	Hooks struct {
 		Before []*Hook `yaml:"before,omitempty"`
 		After  []*Hook `yaml:"after,omitempty"`
+		// OnFailure are executed instead of After if the import is aborted by the failure thresholds.
+		OnFailure []*Hook `yaml:"onFailure,omitempty"`
 	}
 
 	HookName string
@@ -2,17 +2,24 @@
 package manager
 
 import (
+	"context"
+	stderrors "errors"
 	"fmt"
 	"io"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/adaptive"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/checkpoint"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/client"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/importer"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/logger"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/metrics"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/ratelimit"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/reader"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/report"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/source"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/spec"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/stats"
@@ -24,6 +31,10 @@ const (
 	DefaultReaderConcurrency   = 50
 	DefaultImporterConcurrency = 512
 	DefaultStatsInterval       = time.Second * 10
+	DefaultStopTimeout         = time.Minute
+	// DefaultMinRecordsForRatio is the processed records before the maxFailedRatio is checked,
+	// so that a few failures at the beginning do not abort the import.
+	DefaultMinRecordsForRatio = 1000
 )
 
 type (
@@ -40,6 +51,8 @@ type (
 		pool                client.Pool
 		getClientOptions    []client.Option
 		stats               *stats.ConcurrencyStats
+		metrics             metrics.Metrics
+		recorder            *report.Recorder
 		batch               int
 		readerConcurrency   int
 		readerWaitGroup     sync.WaitGroup
@@ -48,13 +61,60 @@ type (
 		importerWaitGroup   sync.WaitGroup
 		importerPool        *ants.Pool
 		statsInterval       time.Duration
+		stopTimeout         time.Duration
 		hooks               *Hooks
+		preflights          []PreflightFunc
+		earlyPreflights     []PreflightFunc
+		checkpointStore     checkpoint.Store
+		checkpointKeys      map[string]int
+		resume              bool
+		closers             []io.Closer
+		cancelers           []importer.Canceler
+		committers          []importer.Committer // the importers of the completed sources
+		committersMu        sync.Mutex
+		truncated           atomic.Bool
 		chStart             chan struct{}
 		done                chan struct{}
-		isStopped           atomic.Bool
+		ctx                 context.Context //nolint:containedctx
+		cancel              context.CancelFunc
+		stopOnce            sync.Once
+		stopErr             error
+		startMu             sync.Mutex // held while starting, Stop waits for it
+		started             bool
 		logger              logger.Logger
+
+		// The failure thresholds to abort the import, zero means no limit.
+		maxFailedRecords             int64
+		maxFailedRatio               float64
+		minRecordsForRatio           int64
+		maxConsecutiveFailedRequests int64
+		failedRecords                atomic.Int64
+		totalRecords                 atomic.Int64
+		consecutiveFailedRequests    atomic.Int64
+		abortOnce                    sync.Once
+		abortErr                     error
+		aborted                      chan struct{}
+
+		// The rate limiters of the records and bytes read, nil means no limit.
+		// The requests are limited by the client pool, where each statement is executed.
+		recordsRateLimiter *ratelimit.Limiter
+		bytesRateLimiter   *ratelimit.Limiter
+
+		// The sources are run as a DAG by their dependencies, see NewDependentSource.
+		dependencies   []Dependency
+		groups         map[string]*sourceGroup
+		skippedSources []string
+		groupsMu       sync.Mutex
+
+		// adaptive adjusts the batch size of the readers and the importer concurrency, nil means static.
+		adaptive     *adaptive.Controller
+		batchSizers  []reader.BatchSizer
+		batchSizerMu sync.Mutex
 	}
 
+	// PreflightFunc checks the cluster before importing, such as the schema.
+	PreflightFunc func(cli client.Client) error
+
 	Option func(*defaultManager)
 )
 
@@ -71,18 +131,33 @@ func NewWithOpts(opts ...Option) Manager {
 		readerConcurrency:   DefaultReaderConcurrency,
 		importerConcurrency: DefaultImporterConcurrency,
 		statsInterval:       DefaultStatsInterval,
+		stopTimeout:         DefaultStopTimeout,
+		minRecordsForRatio:  DefaultMinRecordsForRatio,
 		hooks:               &Hooks{},
+		checkpointKeys:      map[string]int{},
+		groups:              map[string]*sourceGroup{},
 		chStart:             make(chan struct{}),
 		done:                make(chan struct{}),
+		aborted:             make(chan struct{}),
 	}
+	// It's canceled once stopped or aborted, to cancel the waiting for the rate limiters.
+	m.ctx, m.cancel = context.WithCancel(context.Background())
 
 	for _, opt := range opts {
 		opt(m)
 	}
 
+	if m.adaptive != nil {
+		_, m.importerConcurrency = m.adaptive.Values()
+	}
+
 	m.readerPool, _ = ants.NewPool(m.readerConcurrency)
 	m.importerPool, _ = ants.NewPool(m.importerConcurrency)
 
+	if m.adaptive != nil {
+		m.adaptive.OnChange(m.onAdaptiveChange)
+	}
+
 	if m.logger == nil {
 		m.logger = logger.NopLogger
 	}
@@ -108,6 +183,22 @@ func WithGetClientOptions(opts ...client.Option) Option {
 	}
 }
 
+// WithPreflight sets the checks, which are called after the before hooks, and the import is not started if any failed.
+// The after hooks are executed if any failed, since they restore the settings changed by the before hooks.
+func WithPreflight(fns ...PreflightFunc) Option {
+	return func(m *defaultManager) {
+		m.preflights = append(m.preflights, fns...)
+	}
+}
+
+// WithEarlyPreflight sets the checks which change nothing, such as validating the schema, they are called
+// before the before hooks, so that the hooks are not executed if any failed.
+func WithEarlyPreflight(fns ...PreflightFunc) Option {
+	return func(m *defaultManager) {
+		m.earlyPreflights = append(m.earlyPreflights, fns...)
+	}
+}
+
 func WithBatch(batch int) Option {
 	return func(m *defaultManager) {
 		if batch > 0 {
@@ -140,6 +231,15 @@ func WithStatsInterval(statsInterval time.Duration) Option {
 	}
 }
 
+// WithStopTimeout sets the max time to wait for the batches in flight when stopped before the import finished.
+func WithStopTimeout(timeout time.Duration) Option {
+	return func(m *defaultManager) {
+		if timeout > 0 {
+			m.stopTimeout = timeout
+		}
+	}
+}
+
 func WithBeforeHooks(hooks ...*Hook) Option {
 	return func(m *defaultManager) {
 		m.hooks.Before = hooks
@@ -152,6 +252,99 @@ func WithAfterHooks(hooks ...*Hook) Option {
 	}
 }
 
+// WithOnFailureHooks sets the hooks executed before the after hooks if the import is aborted by the failure thresholds.
+func WithOnFailureHooks(hooks ...*Hook) Option {
+	return func(m *defaultManager) {
+		m.hooks.OnFailure = hooks
+	}
+}
+
+// WithMaxFailedRecords aborts the import once the failed records exceed n.
+func WithMaxFailedRecords(n int64) Option {
+	return func(m *defaultManager) {
+		if n > 0 {
+			m.maxFailedRecords = n
+		}
+	}
+}
+
+// WithMaxFailedRatio aborts the import once the ratio of the failed records to the processed ones exceeds ratio.
+func WithMaxFailedRatio(ratio float64) Option {
+	return func(m *defaultManager) {
+		if ratio > 0 {
+			m.maxFailedRatio = ratio
+		}
+	}
+}
+
+// WithMinRecordsForRatio sets the processed records before the maxFailedRatio is checked.
+func WithMinRecordsForRatio(n int64) Option {
+	return func(m *defaultManager) {
+		if n > 0 {
+			m.minRecordsForRatio = n
+		}
+	}
+}
+
+// WithMaxConsecutiveFailedRequests aborts the import once more than n requests failed in a row.
+func WithMaxConsecutiveFailedRequests(n int64) Option {
+	return func(m *defaultManager) {
+		if n > 0 {
+			m.maxConsecutiveFailedRequests = n
+		}
+	}
+}
+
+// WithRecordsRateLimiter limits the records read from the sources per second.
+func WithRecordsRateLimiter(l *ratelimit.Limiter) Option {
+	return func(m *defaultManager) {
+		m.recordsRateLimiter = l
+	}
+}
+
+// WithBytesRateLimiter limits the bytes read from the sources per second.
+func WithBytesRateLimiter(l *ratelimit.Limiter) Option {
+	return func(m *defaultManager) {
+		m.bytesRateLimiter = l
+	}
+}
+
+// WithAdaptiveController adjusts the batch size and the importer concurrency by the controller,
+// the initial values of the controller take the place of the batch and the importer concurrency.
+func WithAdaptiveController(c *adaptive.Controller) Option {
+	return func(m *defaultManager) {
+		m.adaptive = c
+	}
+}
+
+// WithCheckpointStore saves the committed checkpoints of sources to the store.
+func WithCheckpointStore(store checkpoint.Store) Option {
+	return func(m *defaultManager) {
+		m.checkpointStore = store
+	}
+}
+
+// WithResume skips the records committed in the checkpoint store.
+func WithResume(resume bool) Option {
+	return func(m *defaultManager) {
+		m.resume = resume
+	}
+}
+
+// WithMetrics reports the stats to the metrics too, which is closed when the manager stopped.
+func WithMetrics(mt metrics.Metrics) Option {
+	return func(m *defaultManager) {
+		m.metrics = mt
+	}
+}
+
+// WithRecorder records the import failures and the hook outcomes for the report.
+func WithRecorder(r *report.Recorder) Option {
+	return func(m *defaultManager) {
+		m.recorder = r
+	}
+}
+
 func WithLogger(l logger.Logger) Option {
 	return func(m *defaultManager) {
 		m.logger = l
@@ -163,7 +356,31 @@ func (m *defaultManager) Import(s source.Source, brr reader.BatchRecordReader, i
 		return nil
 	}
 
-	logSourceField := logger.Field{Key: "source", Value: s.Name()}
+	for _, i := range importers {
+		if c, ok := i.(io.Closer); ok {
+			m.closers = append(m.closers, c)
+		}
+		if c, ok := i.(importer.Canceler); ok {
+			m.cancelers = append(m.cancelers, c)
+		}
+	}
+
+	name := s.Name()
+	logSourceField := logger.Field{Key: "source", Value: name}
+
+	if sizer, ok := brr.(reader.BatchSizer); ok && m.adaptive != nil {
+		batch, _ := m.adaptive.Values()
+		sizer.SetBatch(batch)
+		m.batchSizerMu.Lock()
+		m.batchSizers = append(m.batchSizers, sizer)
+		m.batchSizerMu.Unlock()
+	}
+
+	if m.resume && m.checkpointStore == nil {
+		err := errors.NewImportError(errors.ErrNoCheckpoint, "manager: resume without checkpoint store").SetGraphName(m.graphName)
+		m.logError(err, "", logSourceField)
+		return err
+	}
 
 	if err := s.Open(); err != nil {
 		err = errors.NewImportError(err, "manager: open import source failed").SetGraphName(m.graphName)
@@ -178,13 +395,37 @@ func (m *defaultManager) Import(s source.Source, brr reader.BatchRecordReader, i
 		m.logError(err, "", logSourceField)
 		return err
 	}
-	m.stats.AddTotalBytes(nBytes)
+	m.stats.AddSourceTotalBytes(name, nBytes)
+	if m.metrics != nil {
+		m.metrics.AddTotalBytes(name, nBytes)
+	}
+
+	var tracker *checkpoint.Tracker
+	if m.checkpointStore != nil {
+		key := m.checkpointKey(name)
+		var committed checkpoint.Checkpoint
+		if m.resume {
+			committed, _ = m.checkpointStore.Load(key)
+		}
+		tracker = checkpoint.NewTracker(m.checkpointStore, key, committed)
+	}
+
+	dependency := dependencyOf(s)
+	m.addDependency(dependency)
 
 	m.readerWaitGroup.Add(1)
 	for _, i := range importers {
 		i.Add(1) // Add 1 for start, will call Done after i.Import finish
 	}
 
+	var committers []importer.Committer
+	for _, i := range importers {
+		if c, ok := i.(importer.Committer); ok {
+			committers = append(committers, c)
+		}
+	}
+
+	progress := &sourceProgress{}
 	cleanup := func() {
 		for _, i := range importers {
 			i.Done() // Done 1 for finish, corresponds to start
@@ -194,17 +435,41 @@ func (m *defaultManager) Import(s source.Source, brr reader.BatchRecordReader, i
 	}
 
 	go func() {
+		if !m.waitDependencies(name, dependency) {
+			m.completeSource(dependency, true)
+			cleanup()
+			return
+		}
 		err = m.readerPool.Submit(func() {
-			<-m.chStart
+			select {
+			case <-m.chStart:
+			case <-m.done:
+				// Stopped before started.
+				cleanup()
+				return
+			}
 			defer cleanup()
 
 			for _, i := range importers {
 				i.Wait()
 			}
-			_ = m.loopImport(s, brr, importers...)
+			if err := m.loopImport(s, brr, tracker, progress, importers...); err != nil {
+				progress.failed.Store(true)
+			}
+			if progress.completed.Load() && !progress.dropped.Load() {
+				m.committersMu.Lock()
+				m.committers = append(m.committers, committers...)
+				m.committersMu.Unlock()
+			}
+			if dependency.Name != "" {
+				// The dependent sources start once the batches in flight are completed.
+				progress.batches.Wait()
+			}
+			m.completeSource(dependency, progress.failed.Load())
 		})
 		if err != nil {
 			cleanup()
+			m.completeSource(dependency, true)
 			m.logError(err, "manager: submit reader failed", logSourceField)
 		}
 	}()
@@ -214,13 +479,41 @@ func (m *defaultManager) Import(s source.Source, brr reader.BatchRecordReader, i
 }
 
 func (m *defaultManager) Start() error {
+	m.startMu.Lock()
+	defer m.startMu.Unlock()
 	m.logger.Info("manager: starting")
 
+	if err := ValidateDependencies(m.dependencies...); err != nil {
+		err = errors.NewImportError(err, "manager: invalid dependencies of the sources").SetGraphName(m.graphName)
+		m.logError(err, "")
+		return err
+	}
+
+	if err := m.preflight(m.earlyPreflights); err != nil {
+		return err
+	}
+	if err := m.stoppedWhileStarting(); err != nil {
+		return err
+	}
+
 	if err := m.Before(); err != nil {
 		return err
 	}
 
+	// The errors of the after hooks are logged, the errors of starting are returned.
+	if err := m.preflight(m.preflights); err != nil {
+		_ = m.After()
+		return err
+	}
+	if err := m.stoppedWhileStarting(); err != nil {
+		_ = m.After()
+		return err
+	}
+
 	m.stats.Init()
+	if m.metrics != nil {
+		m.metrics.Init()
+	}
 
 	if err := m.pool.Open(); err != nil {
 		m.logger.WithError(err).Error("manager: start client pool failed")
@@ -228,6 +521,7 @@ func (m *defaultManager) Start() error {
 	}
 
 	close(m.chStart)
+	m.started = true
 
 	go m.loopPrintStats()
 	m.logger.Info("manager: start successfully")
@@ -237,23 +531,61 @@ func (m *defaultManager) Start() error {
 func (m *defaultManager) Wait() error {
 	m.logger.Info("manager: wait")
 
-	m.readerWaitGroup.Wait()
-	m.importerWaitGroup.Wait()
-
-	m.logger.Info("manager: wait successfully")
-	return m.Stop()
+	select {
+	case <-m.finished():
+		m.logger.Info("manager: wait successfully")
+	case <-m.done:
+		// Stopped before finished, such as interrupted by a signal, Stop waits for the batches in flight.
+	}
+	if err := m.Stop(); err != nil {
+		return err
+	}
+	if err := m.abortError(); err != nil {
+		return err
+	}
+	return m.dependencyError()
 }
 
 func (m *defaultManager) Stats() *stats.Stats {
-	return m.stats.Stats()
+	s := m.stats.Stats()
+	if m.adaptive != nil {
+		s.Batch, s.Concurrency = m.adaptive.Values()
+	}
+	return s
 }
 
-func (m *defaultManager) Stop() (err error) {
-	if m.isStopped.Load() {
+// Stop stops reading the sources, and waits for the batches in flight up to the stop timeout.
+// It's safe to be called more than once, and the subsequent calls wait until the first one returned.
+// Stop stops the manager gracefully, if it's starting, the starting is canceled once the current step finished,
+// such as the before hooks, and the after hooks are executed by Start if the before hooks are executed.
+func (m *defaultManager) Stop() error {
+	m.cancel()
+	m.startMu.Lock()
+	started := m.started
+	m.startMu.Unlock()
+
+	m.stopOnce.Do(func() {
+		if !started {
+			m.logger.Info("manager: stop before started")
+			close(m.done)
+			return
+		}
+		m.stopErr = m.stop()
+	})
+	return m.stopErr
+}
+
+// stoppedWhileStarting returns an error if Stop is called while starting.
+func (m *defaultManager) stoppedWhileStarting() error {
+	if m.ctx.Err() == nil {
 		return nil
 	}
-	m.isStopped.Store(true)
+	err := errors.NewImportError(errors.ErrInterrupted, "manager: stopped while starting").SetGraphName(m.graphName)
+	m.logError(err, "")
+	return err
+}
 
+func (m *defaultManager) stop() (err error) {
 	m.logger.Info("manager: stop")
 	defer func() {
 		if err != nil {
@@ -264,12 +596,72 @@ func (m *defaultManager) Stop() (err error) {
 		}
 	}()
 	close(m.done)
+	m.cancel()
 
-	m.readerWaitGroup.Wait()
-	m.importerWaitGroup.Wait()
+	var truncatedErr error
+	select {
+	case <-m.finished():
+	case <-time.After(m.stopTimeout):
+		// Cancel the batches in flight before closing the dead letters and the checkpoint store,
+		// the canceled batches are neither counted nor committed, and imported again when resumed.
+		m.truncated.Store(true)
+		for _, c := range m.cancelers {
+			c.Cancel()
+		}
+		truncatedErr = errors.NewImportError(errors.ErrStopTimeout,
+			"manager: the batches in flight are not finished in %s, the import is truncated", m.stopTimeout,
+		).SetGraphName(m.graphName)
+	}
+
+	// The replayed dead letters are replaced only if their sources are completed and the import is not interrupted,
+	// otherwise they are kept to replay again.
+	if !m.truncated.Load() && m.abortError() == nil {
+		m.committersMu.Lock()
+		for _, c := range m.committers {
+			c.Commit()
+		}
+		m.committersMu.Unlock()
+	}
 
 	m.logStats()
-	return m.After()
+	if m.metrics != nil {
+		if err := m.metrics.Close(); err != nil {
+			m.logError(err, "manager: close metrics failed")
+		}
+	}
+	for _, c := range m.closers {
+		if err := c.Close(); err != nil {
+			m.logError(err, "manager: close importer failed")
+		}
+	}
+	if m.checkpointStore != nil {
+		if err := m.checkpointStore.Close(); err != nil {
+			m.logError(err, "manager: close checkpoint store failed")
+		}
+	}
+	// The after hooks always run, since they restore the settings changed by the before hooks.
+	var onFailureErr error
+	if m.abortError() != nil {
+		onFailureErr = m.OnFailure()
+	}
+	if err := m.After(); err != nil {
+		return err
+	}
+	if onFailureErr != nil {
+		return onFailureErr
+	}
+	return truncatedErr
+}
+
+// finished returns a channel which is closed once all the readers and importers finished.
+func (m *defaultManager) finished() <-chan struct{} {
+	ch := make(chan struct{})
+	go func() {
+		m.readerWaitGroup.Wait()
+		m.importerWaitGroup.Wait()
+		close(ch)
+	}()
+	return ch
 }
 
 func (m *defaultManager) Before() error {
@@ -277,11 +669,40 @@ func (m *defaultManager) Before() error {
 	return m.execHooks(BeforeHook)
 }
 
+func (m *defaultManager) preflight(preflights []PreflightFunc) error {
+	if len(preflights) == 0 {
+		return nil
+	}
+	m.logger.Info("manager: exec preflight")
+
+	cli, err := m.pool.GetClient(m.getClientOptions...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = cli.Close()
+	}()
+
+	for _, fn := range preflights {
+		if err = fn(cli); err != nil {
+			err = errors.AsOrNewImportError(err, "manager: preflight failed")
+			m.logError(err, "")
+			return err
+		}
+	}
+	return nil
+}
+
 func (m *defaultManager) After() error {
 	m.logger.Info("manager: exec after hook")
 	return m.execHooks(AfterHook)
 }
 
+func (m *defaultManager) OnFailure() error {
+	m.logger.Info("manager: exec on failure hook")
+	return m.execHooks(OnFailureHook)
+}
+
 func (m *defaultManager) execHooks(name HookName) error {
 	var hooks []*Hook
 	switch name {
@@ -289,6 +710,8 @@ func (m *defaultManager) execHooks(name HookName) error {
 		hooks = m.hooks.Before
 	case AfterHook:
 		hooks = m.hooks.After
+	case OnFailureHook:
+		hooks = m.hooks.OnFailure
 	}
 	if len(hooks) == 0 {
 		return nil
@@ -312,6 +735,7 @@ func (m *defaultManager) execHooks(name HookName) error {
 				}
 			}
 			resp, err := cli.Execute(statement)
+			m.recordHook(name, statement, resp, err)
 			if err != nil {
 				err = errors.NewImportError(err,
 					"manager: exec failed in %s hook", name,
@@ -335,12 +759,51 @@ func (m *defaultManager) execHooks(name HookName) error {
 	return nil
 }
 
-func (m *defaultManager) loopImport(s source.Source, r reader.BatchRecordReader, importers ...importer.Importer) error {
-	logSourceField := logger.Field{Key: "source", Value: s.Name()}
+// checkpointKey returns the key of the source in the checkpoint store,
+// the same source may be imported more than once, so suffix the occurrence.
+func (m *defaultManager) checkpointKey(name string) string {
+	m.checkpointKeys[name]++
+	if n := m.checkpointKeys[name]; n > 1 {
+		return fmt.Sprintf("%s#%d", name, n)
+	}
+	return name
+}
+
+func (m *defaultManager) loopImport(s source.Source, r reader.BatchRecordReader, tracker *checkpoint.Tracker, progress *sourceProgress, importers ...importer.Importer) error {
+	name := s.Name()
+	logSourceField := logger.Field{Key: "source", Value: name}
+	if tracker != nil {
+		if committed := tracker.Committed(); committed.Records > 0 {
+			nBytes, err := r.Skip(committed.Records)
+			if err != nil && err != io.EOF {
+				err = errors.NewImportError(err, "manager: skip committed records failed").SetGraphName(m.graphName)
+				m.logError(err, "", logSourceField)
+				return err
+			}
+			m.onSkipped(name, nBytes, committed.Records)
+			if nBytes != committed.Offset {
+				m.logger.Warn("manager: skipped bytes mismatch the checkpoint, the source may be changed",
+					logSourceField,
+					logger.Field{Key: "skipped", Value: nBytes},
+					logger.Field{Key: "offset", Value: committed.Offset},
+				)
+			}
+			m.logger.Info("manager: skip committed records",
+				logSourceField,
+				logger.Field{Key: "records", Value: committed.Records},
+			)
+			if err == io.EOF {
+				progress.completed.Store(true)
+				return nil
+			}
+		}
+	}
 	for {
 		select {
 		case <-m.done:
 			return nil
+		case <-m.aborted:
+			return nil
 		default:
 			nBytes, records, err := r.ReadBatch()
 			if err != nil {
@@ -349,14 +812,24 @@ func (m *defaultManager) loopImport(s source.Source, r reader.BatchRecordReader,
 					m.logError(err, "", logSourceField)
 					return err
 				}
+				progress.completed.Store(true)
 				return nil
 			}
-			m.submitImporterTask(nBytes, records, importers...)
+			// The batch is dropped if stopped while waiting, it's not committed in the checkpoint.
+			if m.recordsRateLimiter.WaitN(m.ctx, len(records)) != nil || m.bytesRateLimiter.WaitN(m.ctx, nBytes) != nil {
+				return nil
+			}
+			m.submitImporterTask(name, nBytes, records, tracker, progress, importers...)
 		}
 	}
 }
 
-func (m *defaultManager) submitImporterTask(nBytes int, records spec.Records, importers ...importer.Importer) {
+func (m *defaultManager) submitImporterTask(name string, nBytes int, records spec.Records, tracker *checkpoint.Tracker, progress *sourceProgress, importers ...importer.Importer) {
+	var seq int64
+	if tracker != nil {
+		seq = tracker.Add(int64(nBytes), int64(len(records)))
+	}
+
 	importersDone := func() {
 		for _, i := range importers {
 			i.Done() // Done 1 for batch
@@ -367,31 +840,76 @@ func (m *defaultManager) submitImporterTask(nBytes int, records spec.Records, im
 		i.Add(1) // Add 1 for batch
 	}
 	m.importerWaitGroup.Add(1)
+	progress.batches.Add(1)
 	if err := m.importerPool.Submit(func() {
 		defer m.importerWaitGroup.Done()
+		defer progress.batches.Done()
 		defer importersDone()
 
-		var isFailed bool
+		var (
+			isFailed bool
+			// failedIndices are the failed records isolated by the bisected batches.
+			failedIndices map[int]struct{}
+		)
 		if len(records) > 0 {
 			for _, i := range importers {
 				result, err := i.Import(records...)
-				if err != nil {
+				if m.truncated.Load() && stderrors.Is(err, errors.ErrImportCanceled) {
+					// Truncated by the stop timeout, the batch is not committed in the checkpoint.
+					progress.failed.Store(true)
+					if tracker != nil {
+						_ = tracker.Done(seq, false)
+					}
+					return
+				}
+				if batchErr, ok := importer.AsBatchError(err); ok {
+					for _, e := range batchErr.Errs {
+						m.logError(e, "manager: import failed")
+						m.recordError(name, e)
+					}
+					m.onRequestFailed(name, i, len(batchErr.Indices))
+					if failedIndices == nil {
+						failedIndices = make(map[int]struct{}, len(batchErr.Indices))
+					}
+					for _, idx := range batchErr.Indices {
+						failedIndices[idx] = struct{}{}
+					}
+				} else if err != nil {
 					m.logError(err, "manager: import failed")
-					m.onRequestFailed(records)
+					m.recordError(name, err)
+					m.onRequestFailed(name, i, len(records))
 					isFailed = true
 					// do not return, continue the subsequent importer.
-				} else if result.RecordNum > 0 {
-					m.onRequestSucceeded(result)
+				}
+				if result != nil && result.RecordNum > 0 {
+					m.onRequestSucceeded(name, i, result)
 				}
 			}
 		}
-		if isFailed {
-			m.onFailed(nBytes, records)
-		} else {
-			m.onSucceeded(nBytes, records)
+		if isFailed || len(failedIndices) > 0 {
+			progress.failed.Store(true)
+		}
+		switch {
+		case isFailed:
+			m.onFailed(name, nBytes, records)
+		case len(failedIndices) > 0:
+			m.onPartiallyFailed(name, nBytes, records, len(failedIndices))
+		default:
+			m.onSucceeded(name, nBytes, records)
+		}
+		if tracker != nil {
+			if err := tracker.Done(seq, !isFailed && len(failedIndices) == 0); err != nil {
+				m.logError(err, "manager: save checkpoint failed")
+			}
 		}
 	}); err != nil {
+		if tracker != nil {
+			_ = tracker.Done(seq, false)
+		}
 		importersDone()
+		progress.failed.Store(true)
+		progress.dropped.Store(true)
+		progress.batches.Done()
 		m.importerWaitGroup.Done()
 		m.logError(err, "manager: submit importer failed")
 	}
@@ -414,23 +932,135 @@ func (m *defaultManager) loopPrintStats() {
 }
 
 func (m *defaultManager) logStats() {
-	m.logger.Info(m.Stats().String())
+	s := m.Stats()
+	m.logger.Info(s.String())
+	for _, line := range s.Breakdown() {
+		m.logger.Info(line)
+	}
+}
+
+func (m *defaultManager) onFailed(name string, nBytes int, records spec.Records) {
+	m.stats.SourceFailed(name, int64(nBytes), int64(len(records)))
+	if m.metrics != nil {
+		m.metrics.Failed(name, int64(nBytes), int64(len(records)))
+	}
+	m.checkFailedRecords(int64(len(records)), int64(len(records)))
+}
+
+func (m *defaultManager) onSucceeded(name string, nBytes int, records spec.Records) {
+	m.stats.SourceSucceeded(name, int64(nBytes), int64(len(records)))
+	if m.metrics != nil {
+		m.metrics.Succeeded(name, int64(nBytes), int64(len(records)))
+	}
+	m.checkFailedRecords(0, int64(len(records)))
 }
 
-func (m *defaultManager) onFailed(nBytes int, records spec.Records) {
-	m.stats.Failed(int64(nBytes), int64(len(records)))
+func (m *defaultManager) onPartiallyFailed(name string, nBytes int, records spec.Records, nFailed int) {
+	m.stats.SourceFailed(name, int64(nBytes), int64(nFailed))
+	m.stats.SourceSucceeded(name, 0, int64(len(records)-nFailed))
+	if m.metrics != nil {
+		m.metrics.Failed(name, int64(nBytes), int64(nFailed))
+		m.metrics.Succeeded(name, 0, int64(len(records)-nFailed))
+	}
+	m.checkFailedRecords(int64(nFailed), int64(len(records)))
 }
 
-func (m *defaultManager) onSucceeded(nBytes int, records spec.Records) {
-	m.stats.Succeeded(int64(nBytes), int64(len(records)))
+func (m *defaultManager) onSkipped(name string, nBytes, nRecords int64) {
+	m.stats.SourceSkipped(name, nBytes, nRecords)
+	if m.metrics != nil {
+		m.metrics.Skipped(name, nBytes, nRecords)
+	}
 }
 
-func (m *defaultManager) onRequestFailed(records spec.Records) {
-	m.stats.RequestFailed(int64(len(records)))
+func (m *defaultManager) onRequestFailed(name string, i importer.Importer, nRecords int) {
+	target := importer.TargetOf(i)
+	m.stats.TargetRequestFailed(name, target, int64(nRecords))
+	if m.metrics != nil {
+		m.metrics.RequestFailed(name, target.Kind, target.Name, int64(nRecords))
+	}
+	if n := m.consecutiveFailedRequests.Add(1); m.maxConsecutiveFailedRequests > 0 && n > m.maxConsecutiveFailedRequests {
+		m.abort(fmt.Sprintf("%d requests failed in a row, exceed maxConsecutiveFailedRequests %d", n, m.maxConsecutiveFailedRequests))
+	}
 }
 
-func (m *defaultManager) onRequestSucceeded(result *importer.ImportResp) {
-	m.stats.RequestSucceeded(int64(result.RecordNum), result.Latency, result.RespTime)
+// onRequestSucceeded records each request of the result, such as the sub-requests of the bisected batches.
+func (m *defaultManager) onRequestSucceeded(name string, i importer.Importer, result *importer.ImportResp) {
+	target := importer.TargetOf(i)
+	for _, req := range result.PerRequest() {
+		m.stats.TargetRequestSucceeded(name, target, int64(req.RecordNum), req.Latency, req.RespTime)
+		if m.metrics != nil {
+			m.metrics.RequestSucceeded(name, target.Kind, target.Name, int64(req.RecordNum), req.Latency, req.RespTime)
+		}
+		if m.adaptive != nil {
+			m.adaptive.Succeeded(req.RespTime)
+		}
+	}
+	m.consecutiveFailedRequests.Store(0)
+}
+
+func (m *defaultManager) onAdaptiveChange(batch, concurrency int) {
+	m.logger.Debug(fmt.Sprintf("manager: adjust the batch to %d and the concurrency to %d", batch, concurrency))
+	m.importerPool.Tune(concurrency)
+	m.batchSizerMu.Lock()
+	defer m.batchSizerMu.Unlock()
+	for _, sizer := range m.batchSizers {
+		sizer.SetBatch(batch)
+	}
+}
+
+// checkFailedRecords counts the processed records, and aborts the import if the failed records exceed the thresholds.
+func (m *defaultManager) checkFailedRecords(nFailed, nRecords int64) {
+	failed := m.failedRecords.Add(nFailed)
+	total := m.totalRecords.Add(nRecords)
+	if nFailed == 0 {
+		return
+	}
+	if m.maxFailedRecords > 0 && failed > m.maxFailedRecords {
+		m.abort(fmt.Sprintf("%d records failed, exceed maxFailedRecords %d", failed, m.maxFailedRecords))
+		return
+	}
+	if m.maxFailedRatio <= 0 || total < m.minRecordsForRatio {
+		return
+	}
+	if ratio := float64(failed) / float64(total); ratio > m.maxFailedRatio {
+		m.abort(fmt.Sprintf("%d of %d records failed, the ratio %.4f exceeds maxFailedRatio %v", failed, total, ratio, m.maxFailedRatio))
+	}
+}
+
+// abort stops reading the sources, the batches in flight are still imported.
+func (m *defaultManager) abort(reason string) {
+	m.abortOnce.Do(func() {
+		m.abortErr = errors.NewImportError(errors.ErrFailureThreshold, "manager: abort import, %s", reason).SetGraphName(m.graphName)
+		m.logError(m.abortErr, "")
+		close(m.aborted)
+		m.cancel()
+	})
+}
+
+// abortError returns the reason if the import is aborted by the failure thresholds.
+func (m *defaultManager) abortError() error {
+	select {
+	case <-m.aborted:
+		return m.abortErr
+	default:
+		return nil
+	}
+}
+
+func (m *defaultManager) recordError(name string, err error) {
+	if m.recorder != nil {
+		m.recorder.RecordError(name, err)
+	}
+}
+
+func (m *defaultManager) recordHook(name HookName, statement string, resp client.Response, err error) {
+	if m.recorder == nil {
+		return
+	}
+	if err == nil && !resp.IsSucceed() {
+		err = resp.GetError()
+	}
+	m.recorder.RecordHook(string(name), statement, err)
 }
 
 func (m *defaultManager) logError(err error, msg string, fields ...logger.Field) {
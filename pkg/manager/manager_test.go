@@ -3,20 +3,33 @@ package manager
 import (
 	stderrors "errors"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/adaptive"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/checkpoint"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/client"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/deadletter"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/importer"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/logger"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/metrics"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/ratelimit"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/reader"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/report"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/source"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/spec"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/stats"
 
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/panjf2000/ants/v2"
 )
 
 var _ = Describe("Manager", func() {
@@ -33,6 +46,7 @@ var _ = Describe("Manager", func() {
 		Expect(m1.importerConcurrency).To(Equal(DefaultImporterConcurrency))
 		Expect(m1.importerPool).NotTo(BeNil())
 		Expect(m1.statsInterval).To(Equal(DefaultStatsInterval))
+		Expect(m1.minRecordsForRatio).To(Equal(int64(DefaultMinRecordsForRatio)))
 		Expect(m1.hooks.Before).To(BeEmpty())
 		Expect(m1.hooks.After).To(BeEmpty())
 		Expect(m1.logger).NotTo(BeNil())
@@ -55,6 +69,7 @@ var _ = Describe("Manager", func() {
 				Statements: []string{"after statements"},
 				Wait:       time.Second,
 			}),
+			WithResume(true),
 			WithLogger(logger.NopLogger),
 		)
 		m1, ok := m.(*defaultManager)
@@ -70,6 +85,7 @@ var _ = Describe("Manager", func() {
 		Expect(m1.statsInterval).To(Equal(DefaultStatsInterval + 1))
 		Expect(m1.hooks.Before).To(HaveLen(1))
 		Expect(m1.hooks.After).To(HaveLen(1))
+		Expect(m1.resume).To(BeTrue())
 		Expect(m1.logger).NotTo(BeNil())
 	})
 
@@ -274,6 +290,114 @@ var _ = Describe("Manager", func() {
 			Expect(err.Error()).To(ContainSubstring("test error"))
 		})
 
+		It("preflight failed", func() {
+			m.(*defaultManager).hooks.Before = nil
+			WithPreflight(func(cli client.Client) error {
+				Expect(cli).To(Equal(mockClient))
+				return nil
+			}, func(client.Client) error {
+				return stderrors.New("test error")
+			})(m.(*defaultManager))
+
+			// The after hooks are executed even if the preflight failed.
+			gomock.InOrder(
+				mockClientPool.EXPECT().GetClient(gomock.Any()).Return(mockClient, nil),
+				mockClient.EXPECT().Close().Return(nil),
+				mockClientPool.EXPECT().GetClient(gomock.Any()).Return(mockClient, nil),
+				mockClient.EXPECT().Execute("after statement").Times(1).Return(mockResponse, nil),
+				mockResponse.EXPECT().IsSucceed().Return(true),
+			)
+
+			err := m.Start()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("test error"))
+		})
+
+		It("stop while starting", func() {
+			m.(*defaultManager).hooks.Before[0].Wait = 0
+			m.(*defaultManager).hooks.After[0].Wait = 0
+
+			mockSource.EXPECT().Name().Return("source name")
+			mockSource.EXPECT().Open().Return(nil)
+			mockSource.EXPECT().Size().Return(int64(1024), nil)
+			mockSource.EXPECT().Close().Return(nil)
+			mockImporter.EXPECT().Add(1)
+			mockImporter.EXPECT().Done()
+
+			chStop := make(chan error, 1)
+			gomock.InOrder(
+				mockClientPool.EXPECT().GetClient(gomock.Any()).Return(mockClient, nil),
+				mockClient.EXPECT().Execute("before statement").DoAndReturn(func(string) (client.Response, error) {
+					go func() {
+						chStop <- m.Stop()
+					}()
+					Eventually(m.(*defaultManager).ctx.Done()).Should(BeClosed())
+					return mockResponse, nil
+				}),
+				mockResponse.EXPECT().IsSucceed().Return(true),
+				// The after hooks are executed since the before hooks are executed.
+				mockClientPool.EXPECT().GetClient(gomock.Any()).Return(mockClient, nil),
+				mockClient.EXPECT().Execute("after statement").Return(mockResponse, nil),
+				mockResponse.EXPECT().IsSucceed().Return(true),
+			)
+
+			Expect(m.Import(mockSource, mockBatchRecordReader, mockImporter)).NotTo(HaveOccurred())
+			err := m.Start()
+			Expect(stderrors.Is(err, errors.ErrInterrupted)).To(BeTrue())
+			Eventually(chStop).Should(Receive(BeNil()))
+			// The readers are not started.
+			m.(*defaultManager).readerWaitGroup.Wait()
+		})
+
+		It("early preflight failed", func() {
+			WithEarlyPreflight(func(client.Client) error {
+				return stderrors.New("test error")
+			})(m.(*defaultManager))
+
+			// Neither the before hooks nor the after hooks are executed.
+			gomock.InOrder(
+				mockClientPool.EXPECT().GetClient(gomock.Any()).Return(mockClient, nil),
+				mockClient.EXPECT().Close().Return(nil),
+			)
+
+			err := m.Start()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("test error"))
+		})
+
+		It("preflight get client failed", func() {
+			m.(*defaultManager).hooks.Before = nil
+			m.(*defaultManager).hooks.After = nil
+			WithPreflight(func(client.Client) error {
+				return nil
+			})(m.(*defaultManager))
+
+			mockClientPool.EXPECT().GetClient(gomock.Any()).Return(nil, stderrors.New("test error"))
+
+			err := m.Start()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("test error"))
+		})
+
+		It("preflight successfully", func() {
+			m.(*defaultManager).hooks.Before = nil
+			m.(*defaultManager).hooks.After = nil
+			WithPreflight(func(client.Client) error {
+				return nil
+			})(m.(*defaultManager))
+
+			gomock.InOrder(
+				mockClientPool.EXPECT().GetClient(gomock.Any()).Return(mockClient, nil),
+				mockClient.EXPECT().Close().Return(nil),
+				mockClientPool.EXPECT().Open().Return(nil),
+			)
+
+			err := m.Start()
+			Expect(err).NotTo(HaveOccurred())
+			err = m.Wait()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
 		It("client pool open failed", func() {
 			gomock.InOrder(
 				mockClientPool.EXPECT().GetClient(gomock.Any()).Return(mockClient, nil),
@@ -400,6 +524,194 @@ var _ = Describe("Manager", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
+		It("stop while waiting", func() {
+			m.(*defaultManager).hooks.Before = nil
+			m.(*defaultManager).hooks.After[0].Wait = 0
+			WithStopTimeout(100 * time.Millisecond)(m.(*defaultManager))
+
+			mockClientPool.EXPECT().Open().Return(nil)
+			mockSource.EXPECT().Name().Times(2).Return("source name")
+			mockSource.EXPECT().Open().Return(nil)
+			mockSource.EXPECT().Size().Return(int64(1024), nil)
+			mockSource.EXPECT().Close().Return(nil)
+			mockBatchRecordReader.EXPECT().ReadBatch().AnyTimes().Return(11, spec.Records{{"id"}}, nil)
+
+			// The batch in flight is not finished until released.
+			release := make(chan struct{})
+			mockImporter.EXPECT().Import(gomock.Any()).AnyTimes().DoAndReturn(func(...spec.Record) (*importer.ImportResp, error) {
+				<-release
+				return &importer.ImportResp{RecordNum: 1}, nil
+			})
+			mockImporter.EXPECT().Add(1).AnyTimes()
+			mockImporter.EXPECT().Done().AnyTimes()
+			mockImporter.EXPECT().Wait().AnyTimes()
+
+			// The after hooks are executed once even if stopped twice.
+			gomock.InOrder(
+				mockClientPool.EXPECT().GetClient(gomock.Any()).Return(mockClient, nil),
+				mockClient.EXPECT().Execute("after statement").Times(1).Return(mockResponse, nil),
+				mockResponse.EXPECT().IsSucceed().Return(true),
+			)
+
+			Expect(m.Import(mockSource, mockBatchRecordReader, mockImporter)).NotTo(HaveOccurred())
+			Expect(m.Start()).NotTo(HaveOccurred())
+
+			chWait := make(chan error, 1)
+			go func() {
+				chWait <- m.Wait()
+			}()
+			time.Sleep(10 * time.Millisecond)
+			Consistently(chWait).ShouldNot(Receive())
+
+			start := time.Now()
+			err := m.Stop()
+			Expect(stderrors.Is(err, errors.ErrStopTimeout)).To(BeTrue())
+			Expect(time.Since(start)).To(BeNumerically(">=", 100*time.Millisecond))
+			var waitErr error
+			Eventually(chWait).Should(Receive(&waitErr))
+			Expect(stderrors.Is(waitErr, errors.ErrStopTimeout)).To(BeTrue())
+
+			close(release)
+			m.(*defaultManager).readerWaitGroup.Wait()
+			m.(*defaultManager).importerWaitGroup.Wait()
+		})
+
+		It("cancel the batches in flight when the stop timeout expires", func() {
+			m.(*defaultManager).hooks.Before = nil
+			m.(*defaultManager).hooks.After[0].Wait = 0
+			WithStopTimeout(100 * time.Millisecond)(m.(*defaultManager))
+
+			mockClientPool.EXPECT().Open().Return(nil)
+			mockSource.EXPECT().Name().Times(2).Return("source name")
+			mockSource.EXPECT().Open().Return(nil)
+			mockSource.EXPECT().Size().Return(int64(1024), nil)
+			mockSource.EXPECT().Close().Return(nil)
+			mockBatchRecordReader.EXPECT().ReadBatch().AnyTimes().Return(11, spec.Records{{"id"}}, nil)
+			mockImporter.EXPECT().Add(1).AnyTimes()
+			mockImporter.EXPECT().Done().AnyTimes()
+			mockImporter.EXPECT().Wait().AnyTimes()
+
+			var (
+				eventsMu sync.Mutex
+				events   []string
+			)
+			addEvent := func(event string) {
+				eventsMu.Lock()
+				defer eventsMu.Unlock()
+				events = append(events, event)
+			}
+			i := &cancelImporter{
+				MockImporter: mockImporter,
+				canceled:     make(chan struct{}),
+				addEvent:     addEvent,
+			}
+			// The batches in flight are not finished until canceled.
+			mockImporter.EXPECT().Import(gomock.Any()).AnyTimes().DoAndReturn(func(...spec.Record) (*importer.ImportResp, error) {
+				<-i.canceled
+				return nil, errors.NewImportError(errors.ErrImportCanceled)
+			})
+
+			gomock.InOrder(
+				mockClientPool.EXPECT().GetClient(gomock.Any()).Return(mockClient, nil),
+				mockClient.EXPECT().Execute("after statement").Times(1).DoAndReturn(func(string) (client.Response, error) {
+					addEvent("after")
+					return mockResponse, nil
+				}),
+				mockResponse.EXPECT().IsSucceed().Return(true),
+			)
+
+			Expect(m.Import(mockSource, mockBatchRecordReader, i)).NotTo(HaveOccurred())
+			Expect(m.Start()).NotTo(HaveOccurred())
+			time.Sleep(10 * time.Millisecond)
+
+			err := m.Stop()
+			Expect(stderrors.Is(err, errors.ErrStopTimeout)).To(BeTrue())
+			Expect(events).To(Equal([]string{"cancel", "close", "after"}))
+
+			m.(*defaultManager).readerWaitGroup.Wait()
+			m.(*defaultManager).importerWaitGroup.Wait()
+			// The canceled batches are neither succeeded nor failed.
+			s := m.Stats()
+			Expect(s.TotalRecords).To(BeZero())
+			Expect(s.FailedRecords).To(BeZero())
+		})
+
+		It("keep the replayed dead letters if stopped halfway", func() {
+			m.(*defaultManager).hooks.Before = nil
+			m.(*defaultManager).hooks.After = nil
+
+			path := filepath.Join(GinkgoT().TempDir(), "dead-letter.csv")
+			Expect(os.WriteFile(path, []byte("1\n2\n"), 0o600)).NotTo(HaveOccurred())
+			i := &replayImporter{
+				MockImporter: mockImporter,
+				w:            deadletter.New(path, &source.Config{CSV: &source.CSVConfig{}}, deadletter.WithReplay()),
+			}
+
+			mockClientPool.EXPECT().Open().Return(nil)
+			mockSource.EXPECT().Name().Times(2).Return("source name")
+			mockSource.EXPECT().Open().Return(nil)
+			mockSource.EXPECT().Size().Return(int64(1024), nil)
+			mockSource.EXPECT().Close().Return(nil)
+			mockBatchRecordReader.EXPECT().ReadBatch().AnyTimes().Return(2, spec.Records{{"1"}}, nil)
+			mockImporter.EXPECT().Import(gomock.Any()).AnyTimes().DoAndReturn(func(records ...spec.Record) (*importer.ImportResp, error) {
+				err := stderrors.New("test error")
+				Expect(i.w.Write(err, records...)).NotTo(HaveOccurred())
+				return nil, err
+			})
+			mockImporter.EXPECT().Add(1).AnyTimes()
+			mockImporter.EXPECT().Done().AnyTimes()
+			mockImporter.EXPECT().Wait().AnyTimes()
+
+			Expect(m.Import(mockSource, mockBatchRecordReader, i)).NotTo(HaveOccurred())
+			Expect(m.Start()).NotTo(HaveOccurred())
+			time.Sleep(10 * time.Millisecond)
+			Expect(m.Stop()).NotTo(HaveOccurred())
+
+			content, err := os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("1\n2\n"))
+			_, err = os.Stat(path + ".replay")
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+
+		It("replace the replayed dead letters once completed", func() {
+			m.(*defaultManager).hooks.Before = nil
+			m.(*defaultManager).hooks.After = nil
+
+			path := filepath.Join(GinkgoT().TempDir(), "dead-letter.csv")
+			Expect(os.WriteFile(path, []byte("1\n2\n"), 0o600)).NotTo(HaveOccurred())
+			i := &replayImporter{
+				MockImporter: mockImporter,
+				w:            deadletter.New(path, &source.Config{CSV: &source.CSVConfig{}}, deadletter.WithReplay()),
+			}
+
+			mockClientPool.EXPECT().Open().Return(nil)
+			mockSource.EXPECT().Name().Times(2).Return("source name")
+			mockSource.EXPECT().Open().Return(nil)
+			mockSource.EXPECT().Size().Return(int64(1024), nil)
+			mockSource.EXPECT().Close().Return(nil)
+			gomock.InOrder(
+				mockBatchRecordReader.EXPECT().ReadBatch().Return(4, spec.Records{{"1"}, {"2"}}, nil),
+				mockBatchRecordReader.EXPECT().ReadBatch().Return(0, spec.Records(nil), io.EOF),
+			)
+			mockImporter.EXPECT().Import(gomock.Any()).Times(1).DoAndReturn(func(records ...spec.Record) (*importer.ImportResp, error) {
+				err := &importer.BatchError{Errs: []error{stderrors.New("test error")}, Indices: []int{1}}
+				Expect(i.w.Write(err.Errs[0], records[1])).NotTo(HaveOccurred())
+				return &importer.ImportResp{RecordNum: 1}, err
+			})
+			mockImporter.EXPECT().Add(1).Times(2)
+			mockImporter.EXPECT().Done().Times(2)
+			mockImporter.EXPECT().Wait().Times(1)
+
+			Expect(m.Import(mockSource, mockBatchRecordReader, i)).NotTo(HaveOccurred())
+			Expect(m.Start()).NotTo(HaveOccurred())
+			Expect(m.Wait()).NotTo(HaveOccurred())
+
+			content, err := os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("2,test error,,,\n"))
+		})
+
 		It("no hooks", func() {
 			m.(*defaultManager).hooks.Before = nil
 			m.(*defaultManager).hooks.After = nil
@@ -603,5 +915,698 @@ var _ = Describe("Manager", func() {
 			err = m.Wait()
 			Expect(err).NotTo(HaveOccurred())
 		})
+
+		It("resume without checkpoint store", func() {
+			m.(*defaultManager).resume = true
+
+			mockSource.EXPECT().Name().Return("source name")
+
+			err := m.Import(
+				mockSource,
+				mockBatchRecordReader,
+				mockImporter,
+			)
+			Expect(err).To(HaveOccurred())
+			Expect(stderrors.Is(err, errors.ErrNoCheckpoint)).To(BeTrue())
+		})
+
+		It("checkpoint and resume", func() {
+			checkpointPath := filepath.Join(tmpdir, "checkpoint.json")
+			fnRun := func(resume bool, skip int64, batches ...spec.Records) {
+				store, err := checkpoint.NewFileStore(checkpointPath, "hash")
+				Expect(err).NotTo(HaveOccurred())
+				m = New(
+					mockClientPool,
+					WithBatch(batch),
+					WithCheckpointStore(store),
+					WithResume(resume),
+				)
+
+				mockSource.EXPECT().Name().Times(2).Return("source name")
+				mockSource.EXPECT().Open().Return(nil)
+				mockSource.EXPECT().Size().Return(int64(1024), nil)
+				mockSource.EXPECT().Close().Return(nil)
+				mockClientPool.EXPECT().Open().Return(nil)
+
+				if skip > 0 {
+					mockBatchRecordReader.EXPECT().Skip(skip).Return(skip*4, nil)
+				}
+				var calls []*gomock.Call
+				for _, records := range batches {
+					calls = append(calls, mockBatchRecordReader.EXPECT().ReadBatch().Return(len(records)*4, records, nil))
+				}
+				calls = append(calls, mockBatchRecordReader.EXPECT().ReadBatch().Return(0, spec.Records(nil), io.EOF))
+				gomock.InOrder(calls...)
+
+				mockImporter.EXPECT().Import(gomock.Any()).Times(len(batches)).DoAndReturn(func(records ...spec.Record) (*importer.ImportResp, error) {
+					if records[0][0] == "fail" {
+						return nil, stderrors.New("import failed")
+					}
+					return &importer.ImportResp{RecordNum: len(records)}, nil
+				})
+				mockImporter.EXPECT().Add(1).Times(1 + len(batches))
+				mockImporter.EXPECT().Done().Times(1 + len(batches))
+				mockImporter.EXPECT().Wait().Times(1)
+
+				err = m.Import(
+					mockSource,
+					mockBatchRecordReader,
+					mockImporter,
+				)
+				Expect(err).NotTo(HaveOccurred())
+				err = m.Start()
+				Expect(err).NotTo(HaveOccurred())
+				err = m.Wait()
+				Expect(err).NotTo(HaveOccurred())
+			}
+			fnLoad := func() checkpoint.Checkpoint {
+				store, err := checkpoint.NewFileStore(checkpointPath, "hash")
+				Expect(err).NotTo(HaveOccurred())
+				cp, ok := store.Load("source name")
+				Expect(ok).To(BeTrue())
+				return cp
+			}
+
+			fnRun(false, 0,
+				spec.Records{{"id1"}, {"id2"}},
+				spec.Records{{"id3"}},
+				spec.Records{{"fail"}},
+				spec.Records{{"id5"}},
+			)
+			Expect(fnLoad()).To(Equal(checkpoint.Checkpoint{Offset: 12, Records: 3}))
+
+			fnRun(true, 3,
+				spec.Records{{"id4"}},
+				spec.Records{{"id5"}},
+			)
+			Expect(fnLoad()).To(Equal(checkpoint.Checkpoint{Offset: 20, Records: 5}))
+			s := m.Stats()
+			Expect(s.SkippedRecords).To(Equal(int64(3)))
+			Expect(s.TotalRecords).To(Equal(int64(2)))
+			Expect(s.ProcessedBytes).To(Equal(int64(20)))
+		})
+
+		It("close importers", func() {
+			m.(*defaultManager).hooks.Before = nil
+			m.(*defaultManager).hooks.After = nil
+
+			mockClientPool.EXPECT().Open().Return(nil)
+			mockSource.EXPECT().Name().Times(2).Return("source name")
+			mockSource.EXPECT().Open().Return(nil)
+			mockSource.EXPECT().Size().Return(int64(1024), nil)
+			mockSource.EXPECT().Close().Return(nil)
+
+			mockBatchRecordReader.EXPECT().ReadBatch().Return(0, spec.Records(nil), io.EOF)
+
+			mockImporter.EXPECT().Add(1)
+			mockImporter.EXPECT().Done()
+			mockImporter.EXPECT().Wait()
+
+			c := &closerImporter{MockImporter: mockImporter, err: stderrors.New("test error")}
+			err := m.Import(
+				mockSource,
+				mockBatchRecordReader,
+				c,
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = m.Start()
+			Expect(err).NotTo(HaveOccurred())
+			err = m.Wait()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.closed).To(Equal(1))
+		})
+
+		It("bisected batch", func() {
+			m.(*defaultManager).hooks.Before = nil
+			m.(*defaultManager).hooks.After = nil
+
+			mockClientPool.EXPECT().Open().Return(nil)
+			mockSource.EXPECT().Name().Times(2).Return("source name")
+			mockSource.EXPECT().Open().Return(nil)
+			mockSource.EXPECT().Size().Return(int64(1024), nil)
+			mockSource.EXPECT().Close().Return(nil)
+
+			gomock.InOrder(
+				mockBatchRecordReader.EXPECT().ReadBatch().Return(12, spec.Records{{"id1"}, {"bad2"}, {"id3"}}, nil),
+				mockBatchRecordReader.EXPECT().ReadBatch().Return(0, spec.Records(nil), io.EOF),
+			)
+
+			mockImporter.EXPECT().Import(gomock.Any()).Times(2).Return(
+				&importer.ImportResp{RecordNum: 2},
+				&importer.BatchError{Errs: []error{stderrors.New("test error")}, Indices: []int{1}},
+			)
+			mockImporter.EXPECT().Add(1).Times(2 * 2)
+			mockImporter.EXPECT().Done().Times(2 * 2)
+			mockImporter.EXPECT().Wait().Times(2)
+
+			err := m.Import(
+				mockSource,
+				mockBatchRecordReader,
+				mockImporter,
+				mockImporter,
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = m.Start()
+			Expect(err).NotTo(HaveOccurred())
+			err = m.Wait()
+			Expect(err).NotTo(HaveOccurred())
+
+			s := m.Stats()
+			Expect(s.TotalRecords).To(Equal(int64(3)))
+			Expect(s.FailedRecords).To(Equal(int64(1)))
+			Expect(s.ProcessedBytes).To(Equal(int64(12)))
+			Expect(s.TotalRequest).To(Equal(int64(4)))
+			Expect(s.FailedRequest).To(Equal(int64(2)))
+			Expect(s.TotalProcessed).To(Equal(int64(6)))
+			Expect(s.FailedProcessed).To(Equal(int64(2)))
+		})
+
+		It("bisected batch of more than one request", func() {
+			c := adaptive.New(
+				adaptive.WithTargetRespTime(5*time.Millisecond),
+				adaptive.WithBatchRange(1, 10),
+				adaptive.WithConcurrencyRange(1, 10),
+				adaptive.WithInitial(2, 1),
+			)
+			m = NewWithOpts(
+				WithClientPool(mockClientPool),
+				WithAdaptiveController(c),
+			)
+
+			mockClientPool.EXPECT().Open().Return(nil)
+			mockSource.EXPECT().Name().Times(2).Return("source name")
+			mockSource.EXPECT().Open().Return(nil)
+			mockSource.EXPECT().Size().Return(int64(1024), nil)
+			mockSource.EXPECT().Close().Return(nil)
+
+			gomock.InOrder(
+				mockBatchRecordReader.EXPECT().ReadBatch().Return(12, spec.Records{{"id1"}, {"bad2"}, {"id3"}}, nil),
+				mockBatchRecordReader.EXPECT().ReadBatch().Return(0, spec.Records(nil), io.EOF),
+			)
+
+			// The sum of the response times exceeds the target, but each of them does not.
+			mockImporter.EXPECT().Import(gomock.Any()).Times(1).Return(
+				&importer.ImportResp{RecordNum: 2, Latency: 5 * time.Millisecond, RespTime: 8 * time.Millisecond, Requests: []importer.ImportResp{
+					{RecordNum: 1, Latency: 2 * time.Millisecond, RespTime: 4 * time.Millisecond},
+					{RecordNum: 1, Latency: 3 * time.Millisecond, RespTime: 4 * time.Millisecond},
+				}},
+				&importer.BatchError{Errs: []error{stderrors.New("test error")}, Indices: []int{1}},
+			)
+			mockImporter.EXPECT().Add(1).Times(2)
+			mockImporter.EXPECT().Done().Times(2)
+			mockImporter.EXPECT().Wait().Times(1)
+
+			Expect(m.Import(mockSource, mockBatchRecordReader, mockImporter)).NotTo(HaveOccurred())
+			Expect(m.Start()).NotTo(HaveOccurred())
+			Expect(m.Wait()).NotTo(HaveOccurred())
+
+			s := m.Stats()
+			Expect(s.TotalRequest).To(Equal(int64(3)))
+			Expect(s.FailedRequest).To(Equal(int64(1)))
+			Expect(s.TotalProcessed).To(Equal(int64(3)))
+			Expect(s.TotalLatency).To(Equal(5 * time.Millisecond))
+			Expect(s.TotalRespTime).To(Equal(8 * time.Millisecond))
+			Expect(s.LatencyHistogram.Count()).To(Equal(int64(2)))
+			// The first request is under the target and grows the batch, their sum would shrink it.
+			batch, _ := c.Values()
+			Expect(batch).To(Equal(3))
+		})
+
+		It("metrics", func() {
+			m.(*defaultManager).hooks.Before = nil
+			m.(*defaultManager).hooks.After = nil
+			mt := metrics.New()
+			m.(*defaultManager).metrics = mt
+
+			mockClientPool.EXPECT().Open().Return(nil)
+			mockSource.EXPECT().Name().Times(2).Return("source name")
+			mockSource.EXPECT().Open().Return(nil)
+			mockSource.EXPECT().Size().Return(int64(1024), nil)
+			mockSource.EXPECT().Close().Return(nil)
+
+			gomock.InOrder(
+				mockBatchRecordReader.EXPECT().ReadBatch().Return(12, spec.Records{{"id1"}, {"bad2"}, {"id3"}}, nil),
+				mockBatchRecordReader.EXPECT().ReadBatch().Return(0, spec.Records(nil), io.EOF),
+			)
+
+			mockImporter.EXPECT().Import(gomock.Any()).Times(1).Return(
+				&importer.ImportResp{RecordNum: 2, Latency: time.Millisecond, RespTime: 2 * time.Millisecond},
+				&importer.BatchError{Errs: []error{stderrors.New("test error")}, Indices: []int{1}},
+			)
+			mockImporter.EXPECT().Add(1).Times(2)
+			mockImporter.EXPECT().Done().Times(2)
+			mockImporter.EXPECT().Wait().Times(1)
+
+			err := m.Import(
+				mockSource,
+				mockBatchRecordReader,
+				&targetImporter{MockImporter: mockImporter, target: stats.Target{Kind: importer.KindTag, Name: "n1", Mode: "INSERT"}},
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = m.Start()
+			Expect(err).NotTo(HaveOccurred())
+			err = m.Wait()
+			Expect(err).NotTo(HaveOccurred())
+
+			w := httptest.NewRecorder()
+			mt.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, metrics.Path, http.NoBody))
+			body := w.Body.String()
+			Expect(body).To(ContainSubstring(`nebula_importer_source_bytes{source="source name"} 1024`))
+			Expect(body).To(ContainSubstring(`nebula_importer_processed_bytes_total{source="source name"} 12`))
+			Expect(body).To(ContainSubstring(`nebula_importer_records_total{source="source name",status="failed"} 1`))
+			Expect(body).To(ContainSubstring(`nebula_importer_records_total{source="source name",status="succeeded"} 2`))
+			Expect(body).To(ContainSubstring(`nebula_importer_requests_total{kind="tag",name="n1",source="source name",status="failed"} 1`))
+			Expect(body).To(ContainSubstring(`nebula_importer_requests_total{kind="tag",name="n1",source="source name",status="succeeded"} 1`))
+			Expect(body).To(ContainSubstring(`nebula_importer_processed_total{kind="tag",name="n1",source="source name",status="succeeded"} 2`))
+			Expect(body).To(ContainSubstring(`nebula_importer_request_latency_seconds_count{kind="tag",name="n1",source="source name"} 1`))
+		})
+
+		It("rate limiters", func() {
+			m.(*defaultManager).hooks.Before = nil
+			m.(*defaultManager).hooks.After = nil
+			m.(*defaultManager).recordsRateLimiter = ratelimit.New(20)
+			m.(*defaultManager).bytesRateLimiter = ratelimit.New(1024)
+
+			mockClientPool.EXPECT().Open().Return(nil)
+			mockSource.EXPECT().Name().Times(2).Return("source name")
+			mockSource.EXPECT().Open().Return(nil)
+			mockSource.EXPECT().Size().Return(int64(1024), nil)
+			mockSource.EXPECT().Close().Return(nil)
+
+			records := make(spec.Records, 10)
+			gomock.InOrder(
+				mockBatchRecordReader.EXPECT().ReadBatch().Times(3).Return(10, records, nil),
+				mockBatchRecordReader.EXPECT().ReadBatch().Return(0, spec.Records(nil), io.EOF),
+			)
+
+			mockImporter.EXPECT().Import(gomock.Any()).Times(3).Return(&importer.ImportResp{RecordNum: 10}, nil)
+			mockImporter.EXPECT().Add(1).Times(4)
+			mockImporter.EXPECT().Done().Times(4)
+			mockImporter.EXPECT().Wait().Times(1)
+
+			Expect(m.Import(mockSource, mockBatchRecordReader, mockImporter)).NotTo(HaveOccurred())
+
+			start := time.Now()
+			Expect(m.Start()).NotTo(HaveOccurred())
+			Expect(m.Wait()).NotTo(HaveOccurred())
+			// The first 20 records are the burst, and the others are allowed in 500ms.
+			Expect(time.Since(start)).To(BeNumerically(">=", 400*time.Millisecond))
+			Expect(m.Stats().TotalRecords).To(Equal(int64(30)))
+		})
+
+		It("stop while rate limited", func() {
+			m.(*defaultManager).hooks.Before = nil
+			m.(*defaultManager).hooks.After = nil
+			m.(*defaultManager).recordsRateLimiter = ratelimit.New(1)
+
+			mockClientPool.EXPECT().Open().Return(nil)
+			mockSource.EXPECT().Name().Times(2).Return("source name")
+			mockSource.EXPECT().Open().Return(nil)
+			mockSource.EXPECT().Size().Return(int64(1024), nil)
+			mockSource.EXPECT().Close().Return(nil)
+
+			read := make(chan struct{})
+			mockBatchRecordReader.EXPECT().ReadBatch().DoAndReturn(func() (int, spec.Records, error) {
+				close(read)
+				return 10, make(spec.Records, 10), nil
+			})
+
+			mockImporter.EXPECT().Add(1).Times(1)
+			mockImporter.EXPECT().Done().Times(1)
+			mockImporter.EXPECT().Wait().Times(1)
+
+			Expect(m.Import(mockSource, mockBatchRecordReader, mockImporter)).NotTo(HaveOccurred())
+
+			start := time.Now()
+			Expect(m.Start()).NotTo(HaveOccurred())
+			<-read
+			Expect(m.Stop()).NotTo(HaveOccurred())
+			Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+			Expect(m.Stats().TotalRecords).To(Equal(int64(0)))
+		})
+
+		It("adaptive", func() {
+			m.(*defaultManager).hooks.Before = nil
+			m.(*defaultManager).hooks.After = nil
+			c := adaptive.New(
+				adaptive.WithBatchRange(1, 10),
+				adaptive.WithConcurrencyRange(1, 10),
+				adaptive.WithInitial(2, 1),
+			)
+			m = NewWithOpts(
+				WithClientPool(mockClientPool),
+				WithAdaptiveController(c),
+			)
+			Expect(m.(*defaultManager).importerConcurrency).To(Equal(1))
+
+			mockClientPool.EXPECT().Open().Return(nil)
+			mockSource.EXPECT().Name().Times(2).Return("source name")
+			mockSource.EXPECT().Open().Return(nil)
+			mockSource.EXPECT().Size().Return(int64(1024), nil)
+			mockSource.EXPECT().Close().Return(nil)
+
+			var batches []int
+			brr := &batchSizerReader{
+				MockBatchRecordReader: mockBatchRecordReader,
+				setBatch: func(batch int) {
+					batches = append(batches, batch)
+				},
+			}
+			gomock.InOrder(
+				mockBatchRecordReader.EXPECT().ReadBatch().Times(2).Return(10, make(spec.Records, 2), nil),
+				mockBatchRecordReader.EXPECT().ReadBatch().Return(0, spec.Records(nil), io.EOF),
+			)
+
+			mockImporter.EXPECT().Import(gomock.Any()).Times(2).Return(&importer.ImportResp{RecordNum: 2, RespTime: time.Millisecond}, nil)
+			mockImporter.EXPECT().Add(1).Times(3)
+			mockImporter.EXPECT().Done().Times(3)
+			mockImporter.EXPECT().Wait().Times(1)
+
+			Expect(m.Import(mockSource, brr, mockImporter)).NotTo(HaveOccurred())
+			Expect(m.Start()).NotTo(HaveOccurred())
+			Expect(m.Wait()).NotTo(HaveOccurred())
+
+			// Increased after the first request, then the window is 2 requests.
+			Expect(batches).To(Equal([]int{2, 3}))
+			s := m.Stats()
+			Expect(s.Batch).To(Equal(3))
+			Expect(s.Concurrency).To(Equal(2))
+			Expect(m.(*defaultManager).importerPool.Cap()).To(Equal(2))
+		})
+
+		It("stats breakdown", func() {
+			m.(*defaultManager).hooks.Before = nil
+			m.(*defaultManager).hooks.After = nil
+
+			mockClientPool.EXPECT().Open().Return(nil)
+			mockSource.EXPECT().Name().Times(2).Return("source name")
+			mockSource.EXPECT().Open().Return(nil)
+			mockSource.EXPECT().Size().Return(int64(1024), nil)
+			mockSource.EXPECT().Close().Return(nil)
+
+			gomock.InOrder(
+				mockBatchRecordReader.EXPECT().ReadBatch().Return(12, spec.Records{{"id1"}, {"id2"}, {"id3"}}, nil),
+				mockBatchRecordReader.EXPECT().ReadBatch().Return(0, spec.Records(nil), io.EOF),
+			)
+
+			gomock.InOrder(
+				mockImporter.EXPECT().Import(gomock.Any()).Return(
+					&importer.ImportResp{RecordNum: 3, Latency: time.Millisecond, RespTime: 2 * time.Millisecond}, nil),
+				mockImporter.EXPECT().Import(gomock.Any()).Return(nil, stderrors.New("test error")),
+			)
+			mockImporter.EXPECT().Add(1).Times(2 * 2)
+			mockImporter.EXPECT().Done().Times(2 * 2)
+			mockImporter.EXPECT().Wait().Times(2)
+
+			tag := stats.Target{Kind: importer.KindTag, Name: "n1", Mode: "INSERT"}
+			edge := stats.Target{Kind: importer.KindEdge, Name: "e1", Mode: "DELETE"}
+			err := m.Import(
+				mockSource,
+				mockBatchRecordReader,
+				&targetImporter{MockImporter: mockImporter, target: tag},
+				&targetImporter{MockImporter: mockImporter, target: edge},
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = m.Start()
+			Expect(err).NotTo(HaveOccurred())
+			err = m.Wait()
+			Expect(err).NotTo(HaveOccurred())
+
+			s := m.Stats()
+			Expect(s.Sources).To(HaveLen(1))
+			Expect(s.Sources).To(HaveKey("source name"))
+			src := s.Sources["source name"]
+			Expect(src.TotalBytes).To(Equal(int64(1024)))
+			Expect(src.ProcessedBytes).To(Equal(int64(12)))
+			Expect(src.TotalRecords).To(Equal(int64(3)))
+			Expect(src.FailedRecords).To(Equal(int64(3)))
+			Expect(src.TotalRequest).To(Equal(int64(2)))
+			Expect(src.FailedRequest).To(Equal(int64(1)))
+
+			Expect(s.Targets).To(HaveLen(2))
+			Expect(s.Targets[tag].TotalRequest).To(Equal(int64(1)))
+			Expect(s.Targets[tag].FailedRequest).To(BeZero())
+			Expect(s.Targets[tag].TotalProcessed).To(Equal(int64(3)))
+			Expect(s.Targets[tag].TotalLatency).To(Equal(time.Millisecond))
+			Expect(s.Targets[edge].TotalRequest).To(Equal(int64(1)))
+			Expect(s.Targets[edge].FailedRequest).To(Equal(int64(1)))
+			Expect(s.Targets[edge].FailedProcessed).To(Equal(int64(3)))
+			Expect(s.Targets[edge].TotalRecords).To(BeZero())
+
+			Expect(s.Breakdown()).To(HaveLen(3))
+		})
+
+		Describe("failure thresholds", func() {
+			var (
+				readBatches int
+				onFailure   *Hook
+			)
+			BeforeEach(func() {
+				readBatches = 0
+				m.(*defaultManager).hooks.Before = nil
+				onFailure = &Hook{Statements: []string{"on failure statement"}}
+				WithOnFailureHooks(onFailure)(m.(*defaultManager))
+
+				mockClientPool.EXPECT().Open().Return(nil)
+				mockSource.EXPECT().Name().Times(2).Return("source name")
+				mockSource.EXPECT().Open().Return(nil)
+				mockSource.EXPECT().Size().Return(int64(1024), nil)
+				mockSource.EXPECT().Close().Return(nil)
+
+				// The source is endless, the reading must be stopped by the thresholds.
+				mockBatchRecordReader.EXPECT().ReadBatch().AnyTimes().DoAndReturn(func() (int, spec.Records, error) {
+					readBatches++
+					return 2, spec.Records{{"id1"}, {"id2"}}, nil
+				})
+				mockImporter.EXPECT().Add(1).AnyTimes()
+				mockImporter.EXPECT().Done().AnyTimes()
+				mockImporter.EXPECT().Wait().AnyTimes()
+
+				// The on failure hooks run before the after hooks.
+				m.(*defaultManager).hooks.After[0].Wait = 0
+				gomock.InOrder(
+					mockClientPool.EXPECT().GetClient(gomock.Any()).Return(mockClient, nil),
+					mockClient.EXPECT().Execute("on failure statement").Return(mockResponse, nil),
+					mockResponse.EXPECT().IsSucceed().Return(true),
+					mockClientPool.EXPECT().GetClient(gomock.Any()).Return(mockClient, nil),
+					mockClient.EXPECT().Execute("after statement").Return(mockResponse, nil),
+					mockResponse.EXPECT().IsSucceed().Return(true),
+				)
+			})
+
+			run := func() error {
+				Expect(m.Import(mockSource, mockBatchRecordReader, mockImporter)).NotTo(HaveOccurred())
+				Expect(m.Start()).NotTo(HaveOccurred())
+				return m.Wait()
+			}
+
+			It("max failed records", func() {
+				WithMaxFailedRecords(5)(m.(*defaultManager))
+				mockImporter.EXPECT().Import(gomock.Any()).AnyTimes().Return(nil, stderrors.New("test error"))
+
+				err := run()
+				Expect(stderrors.Is(err, errors.ErrFailureThreshold)).To(BeTrue())
+				Expect(err.Error()).To(ContainSubstring("exceed maxFailedRecords 5"))
+				Expect(m.Stats().FailedRecords).To(BeNumerically(">", 5))
+				Expect(readBatches).To(BeNumerically("<", 100))
+			})
+
+			It("max failed ratio", func() {
+				WithMaxFailedRatio(0.2)(m.(*defaultManager))
+				var nImport atomic.Int64
+				mockImporter.EXPECT().Import(gomock.Any()).AnyTimes().DoAndReturn(func(...spec.Record) (*importer.ImportResp, error) {
+					if nImport.Add(1) > 10 {
+						return nil, stderrors.New("test error")
+					}
+					return &importer.ImportResp{RecordNum: 2}, nil
+				})
+
+				err := run()
+				Expect(stderrors.Is(err, errors.ErrFailureThreshold)).To(BeTrue())
+				Expect(err.Error()).To(ContainSubstring("exceeds maxFailedRatio 0.2"))
+				s := m.Stats()
+				Expect(float64(s.FailedRecords) / float64(s.TotalRecords)).To(BeNumerically(">", 0.2))
+			})
+
+			It("max failed ratio with the first batch failed", func() {
+				WithMaxFailedRatio(0.2)(m.(*defaultManager))
+				WithMinRecordsForRatio(40)(m.(*defaultManager))
+				// The requests are in order.
+				m.(*defaultManager).importerPool, _ = ants.NewPool(1)
+				var nImport atomic.Int64
+				mockImporter.EXPECT().Import(gomock.Any()).AnyTimes().DoAndReturn(func(...spec.Record) (*importer.ImportResp, error) {
+					// The first one fails, but the import continues until the 30th.
+					if n := nImport.Add(1); n == 1 || n > 30 {
+						return nil, stderrors.New("test error")
+					}
+					return &importer.ImportResp{RecordNum: 2}, nil
+				})
+
+				err := run()
+				Expect(stderrors.Is(err, errors.ErrFailureThreshold)).To(BeTrue())
+				Expect(err.Error()).To(ContainSubstring("exceeds maxFailedRatio 0.2"))
+				Expect(nImport.Load()).To(BeNumerically(">", 30))
+			})
+
+			It("max consecutive failed requests", func() {
+				WithMaxConsecutiveFailedRequests(3)(m.(*defaultManager))
+				// The requests are in order.
+				m.(*defaultManager).importerPool, _ = ants.NewPool(1)
+				var nImport atomic.Int64
+				mockImporter.EXPECT().Import(gomock.Any()).AnyTimes().DoAndReturn(func(...spec.Record) (*importer.ImportResp, error) {
+					// Every other request fails before the 20th.
+					if n := nImport.Add(1); n > 20 || n%2 == 0 {
+						return nil, stderrors.New("test error")
+					}
+					return &importer.ImportResp{RecordNum: 2}, nil
+				})
+
+				err := run()
+				Expect(stderrors.Is(err, errors.ErrFailureThreshold)).To(BeTrue())
+				Expect(err.Error()).To(ContainSubstring("exceed maxConsecutiveFailedRequests 3"))
+				Expect(nImport.Load()).To(BeNumerically(">", 20))
+			})
+		})
+
+		It("recorder", func() {
+			m.(*defaultManager).hooks.Before[0].Wait = 0
+			m.(*defaultManager).hooks.After[0].Wait = 0
+			recorder := report.NewRecorder()
+			WithRecorder(recorder)(m.(*defaultManager))
+
+			gomock.InOrder(
+				mockClientPool.EXPECT().GetClient(gomock.Any()).Return(mockClient, nil),
+				mockClient.EXPECT().Execute("before statement").Return(mockResponse, nil),
+				mockResponse.EXPECT().IsSucceed().Times(2).Return(true),
+				mockClientPool.EXPECT().Open().Return(nil),
+			)
+			mockSource.EXPECT().Name().Times(2).Return("source name")
+			mockSource.EXPECT().Open().Return(nil)
+			mockSource.EXPECT().Size().Return(int64(1024), nil)
+			mockSource.EXPECT().Close().Return(nil)
+
+			gomock.InOrder(
+				mockBatchRecordReader.EXPECT().ReadBatch().Return(12, spec.Records{{"id1"}, {"id2"}}, nil),
+				mockBatchRecordReader.EXPECT().ReadBatch().Return(0, spec.Records(nil), io.EOF),
+			)
+
+			gomock.InOrder(
+				mockImporter.EXPECT().Import(gomock.Any()).Return(&importer.ImportResp{RecordNum: 1}, &importer.BatchError{
+					Errs: []error{
+						errors.NewImportError(errors.ErrExecuteFailed, "the execute error is -1009:SemanticError").
+							SetNodeName("n1").SetStatement("INSERT VERTEX `n1`() VALUES 2:()"),
+					},
+					Indices: []int{1},
+				}),
+				mockImporter.EXPECT().Import(gomock.Any()).Return(nil, errors.NewImportError(stderrors.New("test error")).SetStatement("statement")),
+			)
+			mockImporter.EXPECT().Add(1).Times(2 * 2)
+			mockImporter.EXPECT().Done().Times(2 * 2)
+			mockImporter.EXPECT().Wait().Times(2)
+
+			err := m.Import(mockSource, mockBatchRecordReader, mockImporter, mockImporter)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = m.Start()
+			Expect(err).NotTo(HaveOccurred())
+
+			gomock.InOrder(
+				mockClientPool.EXPECT().GetClient(gomock.Any()).Return(mockClient, nil),
+				mockClient.EXPECT().Execute("after statement").Return(mockResponse, nil),
+			)
+			mockResponse.EXPECT().IsSucceed().Times(2).Return(false)
+			mockResponse.EXPECT().GetError().Times(2).Return(stderrors.New("after error"))
+			err = m.Wait()
+			Expect(err).To(HaveOccurred())
+
+			rpt := report.New(recorder, m.Stats())
+			Expect(rpt.Failures).To(Equal(map[string]int64{
+				report.CategoryExecution:  1,
+				report.CategoryConnection: 1,
+			}))
+			Expect(rpt.FailedStatements).To(Equal([]*report.FailedStatement{
+				{
+					Source:    "source name",
+					Category:  report.CategoryExecution,
+					Tag:       "n1",
+					Statement: "INSERT VERTEX `n1`() VALUES 2:()",
+					Error:     "the execute error is -1009:SemanticError: execute failed",
+				},
+				{
+					Source:    "source name",
+					Category:  report.CategoryConnection,
+					Statement: "statement",
+					Error:     "test error",
+				},
+			}))
+			Expect(rpt.Hooks).To(Equal([]*report.HookOutcome{
+				{Hook: "before", Statement: "before statement", Succeeded: true},
+				{Hook: "after", Statement: "after statement", Error: "after error"},
+			}))
+		})
 	})
 })
+
+type closerImporter struct {
+	*importer.MockImporter
+	err    error
+	closed int
+}
+
+func (c *closerImporter) Close() error {
+	c.closed++
+	return c.err
+}
+
+type targetImporter struct {
+	*importer.MockImporter
+	target stats.Target
+}
+
+func (t *targetImporter) Target() stats.Target {
+	return t.target
+}
+
+type batchSizerReader struct {
+	*reader.MockBatchRecordReader
+	setBatch func(batch int)
+}
+
+func (r *batchSizerReader) SetBatch(batch int) {
+	r.setBatch(batch)
+}
+
+// cancelImporter is the importer which can be canceled and closed, the events are recorded in order.
+type cancelImporter struct {
+	*importer.MockImporter
+	canceled chan struct{}
+	addEvent func(string)
+}
+
+func (i *cancelImporter) Cancel() {
+	i.addEvent("cancel")
+	close(i.canceled)
+}
+
+func (i *cancelImporter) Close() error {
+	i.addEvent("close")
+	return nil
+}
+
+// replayImporter is the importer which writes the dead letters of a replay.
+type replayImporter struct {
+	*importer.MockImporter
+	w deadletter.Writer
+}
+
+func (i *replayImporter) Commit() {
+	i.w.(deadletter.Committer).Commit()
+}
+
+func (i *replayImporter) Close() error {
+	return i.w.Close()
+}
@@ -0,0 +1,233 @@
+package metrics
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/client"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	Namespace = "nebula_importer"
+	Path      = "/metrics"
+
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusSkipped   = "skipped"
+)
+
+var _ Metrics = (*defaultMetrics)(nil)
+
+type (
+	// Metrics exposes the stats of the import as Prometheus metrics,
+	// labelled by the source, the tag or edge and the graphd address.
+	Metrics interface {
+		Init()
+		AddTotalBytes(source string, nBytes int64)
+		Succeeded(source string, nBytes, nRecords int64)
+		Failed(source string, nBytes, nRecords int64)
+		Skipped(source string, nBytes, nRecords int64)
+		RequestSucceeded(source, kind, name string, nRecords int64, latency, respTime time.Duration)
+		RequestFailed(source, kind, name string, nRecords int64)
+		// ObserveExecute observes the statements executed by the client pool, see client.WithExecuteObserver.
+		ObserveExecute(address string, resp client.Response, err error)
+		Handler() http.Handler
+		// Serve listens on the address and serves the metrics until closed.
+		Serve(listen string) error
+		Close() error
+	}
+
+	defaultMetrics struct {
+		registry *prometheus.Registry
+
+		startTime      prometheus.Gauge
+		totalBytes     *prometheus.GaugeVec
+		processedBytes *prometheus.CounterVec
+		records        *prometheus.CounterVec
+		requests       *prometheus.CounterVec
+		processed      *prometheus.CounterVec
+		latency        *prometheus.HistogramVec
+		respTime       *prometheus.HistogramVec
+		graphdRequests *prometheus.CounterVec
+		graphdLatency  *prometheus.HistogramVec
+		graphdRespTime *prometheus.HistogramVec
+
+		initOnce sync.Once
+		mu       sync.Mutex
+		listener net.Listener
+		server   *http.Server
+	}
+)
+
+// DefaultBuckets are the buckets of the latency and response time in seconds, from 1ms to about 32s.
+var DefaultBuckets = prometheus.ExponentialBuckets(0.001, 2, 16)
+
+func New() Metrics {
+	m := &defaultMetrics{
+		registry: prometheus.NewRegistry(),
+		startTime: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "start_time_seconds",
+			Help:      "The unix time when the import started.",
+		}),
+		totalBytes: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "source_bytes",
+			Help:      "The total bytes of the source.",
+		}, []string{"source"}),
+		processedBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "processed_bytes_total",
+			Help:      "The processed bytes of the source.",
+		}, []string{"source"}),
+		records: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "records_total",
+			Help:      "The number of records of the source by the status, succeeded, failed or skipped.",
+		}, []string{"source", "status"}),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "requests_total",
+			Help:      "The number of requests of the tag or edge by the status, succeeded or failed.",
+		}, []string{"source", "kind", "name", "status"}),
+		processed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "processed_total",
+			Help:      "The number of nodes or edges processed by the status, succeeded or failed.",
+		}, []string{"source", "kind", "name", "status"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "request_latency_seconds",
+			Help:      "The latency of the succeeded requests of the tag or edge, reported by the graphd.",
+			Buckets:   DefaultBuckets,
+		}, []string{"source", "kind", "name"}),
+		respTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "request_response_time_seconds",
+			Help:      "The response time of the succeeded requests of the tag or edge, measured by the client.",
+			Buckets:   DefaultBuckets,
+		}, []string{"source", "kind", "name"}),
+		graphdRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "graphd_requests_total",
+			Help:      "The number of statements executed by the graphd by the status, succeeded or failed.",
+		}, []string{"address", "status"}),
+		graphdLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "graphd_latency_seconds",
+			Help:      "The latency of the statements executed by the graphd.",
+			Buckets:   DefaultBuckets,
+		}, []string{"address"}),
+		graphdRespTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "graphd_response_time_seconds",
+			Help:      "The response time of the statements executed by the graphd.",
+			Buckets:   DefaultBuckets,
+		}, []string{"address"}),
+	}
+	m.registry.MustRegister(
+		m.startTime,
+		m.totalBytes,
+		m.processedBytes,
+		m.records,
+		m.requests,
+		m.processed,
+		m.latency,
+		m.respTime,
+		m.graphdRequests,
+		m.graphdLatency,
+		m.graphdRespTime,
+	)
+	return m
+}
+
+func (m *defaultMetrics) Init() {
+	m.initOnce.Do(func() {
+		m.startTime.SetToCurrentTime()
+	})
+}
+
+func (m *defaultMetrics) AddTotalBytes(source string, nBytes int64) {
+	m.totalBytes.WithLabelValues(source).Add(float64(nBytes))
+}
+
+func (m *defaultMetrics) Succeeded(source string, nBytes, nRecords int64) {
+	m.processedBytes.WithLabelValues(source).Add(float64(nBytes))
+	m.records.WithLabelValues(source, StatusSucceeded).Add(float64(nRecords))
+}
+
+func (m *defaultMetrics) Failed(source string, nBytes, nRecords int64) {
+	m.processedBytes.WithLabelValues(source).Add(float64(nBytes))
+	m.records.WithLabelValues(source, StatusFailed).Add(float64(nRecords))
+}
+
+func (m *defaultMetrics) Skipped(source string, nBytes, nRecords int64) {
+	m.processedBytes.WithLabelValues(source).Add(float64(nBytes))
+	m.records.WithLabelValues(source, StatusSkipped).Add(float64(nRecords))
+}
+
+func (m *defaultMetrics) RequestSucceeded(source, kind, name string, nRecords int64, latency, respTime time.Duration) {
+	m.requests.WithLabelValues(source, kind, name, StatusSucceeded).Inc()
+	m.processed.WithLabelValues(source, kind, name, StatusSucceeded).Add(float64(nRecords))
+	m.latency.WithLabelValues(source, kind, name).Observe(latency.Seconds())
+	m.respTime.WithLabelValues(source, kind, name).Observe(respTime.Seconds())
+}
+
+func (m *defaultMetrics) RequestFailed(source, kind, name string, nRecords int64) {
+	m.requests.WithLabelValues(source, kind, name, StatusFailed).Inc()
+	m.processed.WithLabelValues(source, kind, name, StatusFailed).Add(float64(nRecords))
+}
+
+func (m *defaultMetrics) ObserveExecute(address string, resp client.Response, err error) {
+	if err != nil || resp == nil || !resp.IsSucceed() {
+		m.graphdRequests.WithLabelValues(address, StatusFailed).Inc()
+		return
+	}
+	m.graphdRequests.WithLabelValues(address, StatusSucceeded).Inc()
+	m.graphdLatency.WithLabelValues(address).Observe(resp.GetLatency().Seconds())
+	m.graphdRespTime.WithLabelValues(address).Observe(resp.GetRespTime().Seconds())
+}
+
+func (m *defaultMetrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *defaultMetrics) Serve(listen string) error {
+	l, err := net.Listen("tcp", listen)
+	if err != nil {
+		return errors.NewImportError(err, "metrics: listen on %s failed", listen)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(Path, m.Handler())
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	m.mu.Lock()
+	m.listener, m.server = l, server
+	m.mu.Unlock()
+
+	go func() {
+		_ = server.Serve(l)
+	}()
+	return nil
+}
+
+func (m *defaultMetrics) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.server == nil {
+		return nil
+	}
+	err := m.server.Close()
+	m.listener, m.server = nil, nil
+	return err
+}
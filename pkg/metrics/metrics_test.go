@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	stderrors "errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/client"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var _ = Describe("Metrics", func() {
+	It("stats", func() {
+		mt := New()
+		m := mt.(*defaultMetrics)
+		mt.Init()
+		Expect(testutil.ToFloat64(m.startTime)).To(BeNumerically(">", 0))
+
+		mt.AddTotalBytes("s1", 100)
+		mt.Succeeded("s1", 10, 2)
+		mt.Failed("s1", 20, 3)
+		mt.Skipped("s1", 30, 4)
+		mt.RequestSucceeded("s1", "tag", "n1", 2, time.Millisecond, 2*time.Millisecond)
+		mt.RequestFailed("s1", "edge", "e1", 3)
+
+		Expect(testutil.ToFloat64(m.totalBytes.WithLabelValues("s1"))).To(Equal(100.0))
+		Expect(testutil.ToFloat64(m.processedBytes.WithLabelValues("s1"))).To(Equal(60.0))
+		Expect(testutil.ToFloat64(m.records.WithLabelValues("s1", StatusSucceeded))).To(Equal(2.0))
+		Expect(testutil.ToFloat64(m.records.WithLabelValues("s1", StatusFailed))).To(Equal(3.0))
+		Expect(testutil.ToFloat64(m.records.WithLabelValues("s1", StatusSkipped))).To(Equal(4.0))
+		Expect(testutil.ToFloat64(m.requests.WithLabelValues("s1", "tag", "n1", StatusSucceeded))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(m.processed.WithLabelValues("s1", "tag", "n1", StatusSucceeded))).To(Equal(2.0))
+		Expect(testutil.ToFloat64(m.requests.WithLabelValues("s1", "edge", "e1", StatusFailed))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(m.processed.WithLabelValues("s1", "edge", "e1", StatusFailed))).To(Equal(3.0))
+		Expect(testutil.CollectAndCount(m.latency)).To(Equal(1))
+		Expect(testutil.CollectAndCount(m.respTime)).To(Equal(1))
+	})
+
+	It("ObserveExecute", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		defer ctrl.Finish()
+		mockResponse := client.NewMockResponse(ctrl)
+
+		mt := New()
+		m := mt.(*defaultMetrics)
+
+		mockResponse.EXPECT().IsSucceed().Times(2).Return(true)
+		mockResponse.EXPECT().GetLatency().Times(2).Return(time.Millisecond)
+		mockResponse.EXPECT().GetRespTime().Times(2).Return(2 * time.Millisecond)
+		mt.ObserveExecute("127.0.0.1:9669", mockResponse, nil)
+		mt.ObserveExecute("127.0.0.2:9669", mockResponse, nil)
+
+		mockResponse.EXPECT().IsSucceed().Times(1).Return(false)
+		mt.ObserveExecute("127.0.0.1:9669", mockResponse, nil)
+		mt.ObserveExecute("127.0.0.1:9669", nil, stderrors.New("test error"))
+
+		Expect(testutil.ToFloat64(m.graphdRequests.WithLabelValues("127.0.0.1:9669", StatusSucceeded))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(m.graphdRequests.WithLabelValues("127.0.0.1:9669", StatusFailed))).To(Equal(2.0))
+		Expect(testutil.ToFloat64(m.graphdRequests.WithLabelValues("127.0.0.2:9669", StatusSucceeded))).To(Equal(1.0))
+		Expect(testutil.CollectAndCount(m.graphdLatency)).To(Equal(2))
+		Expect(testutil.CollectAndCount(m.graphdRespTime)).To(Equal(2))
+	})
+
+	It("Handler", func() {
+		mt := New()
+		mt.Succeeded("s1", 10, 2)
+
+		w := httptest.NewRecorder()
+		mt.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, Path, http.NoBody))
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(w.Body.String()).To(ContainSubstring(`nebula_importer_records_total{source="s1",status="succeeded"} 2`))
+	})
+
+	It("Serve", func() {
+		mt := New()
+		Expect(mt.Close()).NotTo(HaveOccurred())
+		Expect(mt.Serve("invalid address")).To(HaveOccurred())
+
+		Expect(mt.Serve("127.0.0.1:0")).NotTo(HaveOccurred())
+		addr := mt.(*defaultMetrics).listener.Addr().String()
+
+		resp, err := http.Get("http://" + addr + Path)
+		Expect(err).NotTo(HaveOccurred())
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(body)).To(ContainSubstring("nebula_importer_start_time_seconds"))
+
+		Expect(mt.Close()).NotTo(HaveOccurred())
+		_, err = http.Get("http://" + addr + Path)
+		Expect(err).To(HaveOccurred())
+	})
+})
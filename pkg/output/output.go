@@ -0,0 +1,291 @@
+package output
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/client"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/utils"
+)
+
+const (
+	// HooksName is the name of the output of the statements which are not for a tag or edge, such as the hooks.
+	HooksName = "hooks"
+
+	FileExt = ".ngql"
+)
+
+var (
+	_ Pool                 = (*defaultPool)(nil)
+	_ client.Pool          = (*namedPool)(nil)
+	_ client.SpaceExecutor = (*defaultPool)(nil)
+	_ client.SpaceExecutor = (*namedPool)(nil)
+	_ client.Response      = response{}
+)
+
+type (
+	// Pool writes the statements instead of executing them, the statements of each tag or edge go to its named pool.
+	Pool interface {
+		client.Pool
+		// Named returns the pool writing to the output of the tag or edge.
+		Named(name string) client.Pool
+	}
+
+	defaultPool struct {
+		graphName string
+		// dir is the directory of the statement files, the statements are written to w if it is empty.
+		dir string
+		w   io.Writer
+
+		mu       sync.Mutex
+		writers  map[string]*statementWriter
+		isClosed bool
+	}
+
+	namedPool struct {
+		p    *defaultPool
+		name string
+	}
+
+	statementWriter struct {
+		mu sync.Mutex
+		f  *os.File
+		bw *bufio.Writer
+		// space is the graph space of the last USE statement written.
+		space string
+	}
+
+	response struct{}
+)
+
+// NewFilePool writes the statements to the files named by the tags and edges in the directory.
+// The files are created when the first statement is written, each starts with the USE statement.
+func NewFilePool(dir, graphName string) Pool {
+	return &defaultPool{
+		graphName: graphName,
+		dir:       dir,
+		writers:   map[string]*statementWriter{},
+	}
+}
+
+// NewWriterPool writes all the statements to w, which starts with the USE statement.
+func NewWriterPool(w io.Writer, graphName string) Pool {
+	return &defaultPool{
+		graphName: graphName,
+		w:         w,
+		writers:   map[string]*statementWriter{},
+	}
+}
+
+func (p *defaultPool) Named(name string) client.Pool {
+	return &namedPool{p: p, name: name}
+}
+
+func (*defaultPool) Open() error {
+	return nil
+}
+
+func (p *defaultPool) Execute(statement string) (client.Response, error) {
+	return p.write(HooksName, "", statement)
+}
+
+func (p *defaultPool) ExecuteInSpace(space, statement string) (client.Response, error) {
+	return p.write(HooksName, space, statement)
+}
+
+func (p *defaultPool) ExecuteChanInSpace(space, statement string) (<-chan client.ExecuteResult, bool) {
+	return executeChan(func() (client.Response, error) {
+		return p.ExecuteInSpace(space, statement)
+	})
+}
+
+func (p *defaultPool) GetClient(...client.Option) (client.Client, error) {
+	return p, nil
+}
+
+func (p *defaultPool) ExecuteChan(statement string) (<-chan client.ExecuteResult, bool) {
+	return executeChan(func() (client.Response, error) {
+		return p.Execute(statement)
+	})
+}
+
+// Close flushes and closes all the outputs.
+func (p *defaultPool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.isClosed {
+		return nil
+	}
+	p.isClosed = true
+
+	var err error
+	for _, w := range p.writers {
+		if closeErr := w.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// write writes the statement to the output of name, which is preceded by the USE statement if the space changed.
+func (p *defaultPool) write(name, space, statement string) (client.Response, error) {
+	w, err := p.getWriter(name)
+	if err != nil {
+		return nil, err
+	}
+	if err = w.WriteInSpace(space, statement); err != nil {
+		return nil, errors.NewImportError(err, "write statement failed").SetStatement(statement)
+	}
+	return response{}, nil
+}
+
+func (p *defaultPool) getWriter(name string) (*statementWriter, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.isClosed {
+		return nil, client.ErrClosed
+	}
+
+	// All the statements share the same writer if the directory is not set.
+	if p.dir == "" {
+		name = ""
+	}
+	if w, ok := p.writers[name]; ok {
+		return w, nil
+	}
+
+	w := &statementWriter{}
+	if p.dir == "" {
+		w.bw = bufio.NewWriter(p.w)
+	} else {
+		path := filepath.Join(p.dir, name+FileExt)
+		if err := os.MkdirAll(p.dir, 0o755); err != nil {
+			return nil, errors.NewImportError(err, "create output failed").SetFileName(path)
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			return nil, errors.NewImportError(err, "create output failed").SetFileName(path)
+		}
+		w.f, w.bw = f, bufio.NewWriter(f)
+	}
+	if p.graphName != "" {
+		if err := w.WriteInSpace(p.graphName, ""); err != nil {
+			_ = w.Close()
+			return nil, errors.NewImportError(err, "write output failed")
+		}
+	}
+	p.writers[name] = w
+	return w, nil
+}
+
+func (p *namedPool) Open() error {
+	return nil
+}
+
+func (p *namedPool) Execute(statement string) (client.Response, error) {
+	return p.p.write(p.name, "", statement)
+}
+
+func (p *namedPool) ExecuteInSpace(space, statement string) (client.Response, error) {
+	return p.p.write(p.name, space, statement)
+}
+
+func (p *namedPool) ExecuteChanInSpace(space, statement string) (<-chan client.ExecuteResult, bool) {
+	return executeChan(func() (client.Response, error) {
+		return p.ExecuteInSpace(space, statement)
+	})
+}
+
+func (p *namedPool) GetClient(...client.Option) (client.Client, error) {
+	return p, nil
+}
+
+func (p *namedPool) ExecuteChan(statement string) (<-chan client.ExecuteResult, bool) {
+	return executeChan(func() (client.Response, error) {
+		return p.Execute(statement)
+	})
+}
+
+// Close does nothing, the outputs are closed by the pool which it is named from.
+func (*namedPool) Close() error {
+	return nil
+}
+
+func executeChan(execute func() (client.Response, error)) (<-chan client.ExecuteResult, bool) {
+	ch := make(chan client.ExecuteResult, 1)
+	resp, err := execute()
+	ch <- client.ExecuteResult{Response: resp, Err: err}
+	close(ch)
+	return ch, true
+}
+
+// WriteInSpace writes the statement in a line, which is flushed to keep the output readable on failures.
+// The USE statement is written first if the space is not empty and differs from the last one,
+// the statement is omitted if it's empty.
+func (w *statementWriter) WriteInSpace(space, statement string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if space != "" && space != w.space {
+		_, _ = w.bw.WriteString(fmt.Sprintf("USE %s;\n", utils.ConvertIdentifier(space)))
+		w.space = space
+	}
+	if statement != "" {
+		// The statements of UPDATE and UPSERT mode end with the semicolon already.
+		_, _ = w.bw.WriteString(strings.TrimSuffix(statement, ";"))
+		_, _ = w.bw.WriteString(";\n")
+	}
+	return w.bw.Flush()
+}
+
+func (w *statementWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	err := w.bw.Flush()
+	if w.f != nil {
+		if closeErr := w.f.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+func (response) IsSucceed() bool {
+	return true
+}
+
+func (response) GetLatency() time.Duration {
+	return 0
+}
+
+func (response) GetRespTime() time.Duration {
+	return 0
+}
+
+func (response) GetError() error {
+	return nil
+}
+
+func (response) IsPermanentError() bool {
+	return false
+}
+
+func (response) IsRetryMoreError() bool {
+	return false
+}
+
+func (response) GetColNames() []string {
+	return nil
+}
+
+func (response) GetRowValues() ([][]string, error) {
+	return nil, nil
+}
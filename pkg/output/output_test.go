@@ -0,0 +1,122 @@
+package output
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/client"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pool", func() {
+	It("writer", func() {
+		buf := &bytes.Buffer{}
+		p := NewWriterPool(buf, "graphName")
+		Expect(p.Open()).NotTo(HaveOccurred())
+
+		resp, err := p.Execute("statement1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.IsSucceed()).To(BeTrue())
+		Expect(resp.GetError()).NotTo(HaveOccurred())
+		Expect(resp.GetLatency()).To(BeZero())
+		Expect(resp.GetRespTime()).To(BeZero())
+		Expect(resp.IsPermanentError()).To(BeFalse())
+		Expect(resp.IsRetryMoreError()).To(BeFalse())
+
+		cli, err := p.GetClient()
+		Expect(err).NotTo(HaveOccurred())
+		_, err = cli.Execute("statement2;")
+		Expect(err).NotTo(HaveOccurred())
+
+		named := p.Named("n1")
+		Expect(named.Open()).NotTo(HaveOccurred())
+		_, err = named.Execute("INSERT VERTEX n1")
+		Expect(err).NotTo(HaveOccurred())
+		ch, ok := named.ExecuteChan("INSERT VERTEX n1")
+		Expect(ok).To(BeTrue())
+		Expect((<-ch).Err).NotTo(HaveOccurred())
+		Expect(named.Close()).NotTo(HaveOccurred())
+
+		Expect(p.Close()).NotTo(HaveOccurred())
+		Expect(buf.String()).To(Equal("USE `graphName`;\nstatement1;\nstatement2;\nINSERT VERTEX n1;\nINSERT VERTEX n1;\n"))
+
+		_, err = named.Execute("INSERT VERTEX n1")
+		Expect(err).To(Equal(client.ErrClosed))
+		Expect(p.Close()).NotTo(HaveOccurred())
+	})
+
+	It("spaces", func() {
+		buf := &bytes.Buffer{}
+		p := NewWriterPool(buf, "graphName")
+
+		_, err := client.NewSpacePool(p.Named("n1"), "graphName").Execute("INSERT VERTEX n1")
+		Expect(err).NotTo(HaveOccurred())
+		ch, ok := client.NewSpacePool(p.Named("n2"), "s2").ExecuteChan("INSERT VERTEX n2")
+		Expect(ok).To(BeTrue())
+		Expect((<-ch).Err).NotTo(HaveOccurred())
+		_, err = client.NewSpacePool(p.Named("n3"), "s2").Execute("INSERT VERTEX n3")
+		Expect(err).NotTo(HaveOccurred())
+		ch, ok = client.NewSpacePool(p, "graphName").ExecuteChan("statement1")
+		Expect(ok).To(BeTrue())
+		Expect((<-ch).Err).NotTo(HaveOccurred())
+		// The statements without space are written in the current space.
+		_, err = p.Execute("statement2")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(p.Close()).NotTo(HaveOccurred())
+		Expect(buf.String()).To(Equal("USE `graphName`;\nINSERT VERTEX n1;\nUSE `s2`;\nINSERT VERTEX n2;\nINSERT VERTEX n3;\n" +
+			"USE `graphName`;\nstatement1;\nstatement2;\n"))
+	})
+
+	It("files", func() {
+		dir := filepath.Join(GinkgoT().TempDir(), "output")
+		p := NewFilePool(dir, "graphName")
+
+		var wg sync.WaitGroup
+		for _, name := range []string{"n1", "e1"} {
+			named := p.Named(name)
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer GinkgoRecover()
+					_, err := named.Execute("INSERT " + name)
+					Expect(err).NotTo(HaveOccurred())
+				}()
+			}
+		}
+		wg.Wait()
+		ch, ok := p.ExecuteChan("statement1")
+		Expect(ok).To(BeTrue())
+		Expect((<-ch).Err).NotTo(HaveOccurred())
+		Expect(p.Close()).NotTo(HaveOccurred())
+
+		entries, err := os.ReadDir(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(3))
+
+		content, err := os.ReadFile(filepath.Join(dir, "n1.ngql"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(HavePrefix("USE `graphName`;\n"))
+		Expect(bytes.Count(content, []byte("INSERT n1;\n"))).To(Equal(10))
+		Expect(string(content)).NotTo(ContainSubstring("e1"))
+
+		content, err = os.ReadFile(filepath.Join(dir, HooksName+FileExt))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("USE `graphName`;\nstatement1;\n"))
+	})
+
+	It("create failed", func() {
+		file := filepath.Join(GinkgoT().TempDir(), "file")
+		Expect(os.WriteFile(file, nil, 0o600)).NotTo(HaveOccurred())
+
+		p := NewFilePool(filepath.Join(file, "output"), "")
+		_, err := p.Named("n1").Execute("INSERT n1")
+		Expect(err).To(HaveOccurred())
+		Expect(p.Close()).NotTo(HaveOccurred())
+	})
+})
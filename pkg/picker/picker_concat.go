@@ -72,6 +72,17 @@ func (ci ConcatItems) Len() int {
 	return len(ci.pickers)
 }
 
+// Indices returns the index columns of the concat items.
+func (ci ConcatItems) Indices() []int {
+	indices := make([]int, 0, len(ci.pickers))
+	for _, p := range ci.pickers {
+		if index, ok := p.(IndexPicker); ok {
+			indices = append(indices, int(index))
+		}
+	}
+	return indices
+}
+
 func (cp ConcatPicker) Pick(record []string) (*Value, error) {
 	var sb strings.Builder
 	for _, p := range cp.items.pickers {
@@ -54,6 +54,7 @@ var _ = Describe("ConcatItems", func() {
 		err := ci.Add(1, "str1", 2, []byte("str2"), 3)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ci.Len()).To(Equal(5))
+		Expect(ci.Indices()).To(Equal([]int{1, 2, 3}))
 	})
 })
 
@@ -0,0 +1,121 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+type (
+	// Limiter is a token bucket, which allows the events at the limit per second with the burst of one second.
+	// The zero limit means no limit, and the limit can be changed at any time, even while waiting.
+	// A nil Limiter never limits.
+	Limiter struct {
+		l *rate.Limiter
+	}
+
+	// Group is the limiters of the keys which share the same limit, such as of each graphd address.
+	// A nil Group never limits.
+	Group struct {
+		mu       sync.Mutex
+		limit    float64
+		limiters map[string]*Limiter
+	}
+)
+
+func New(limit float64) *Limiter {
+	l := &Limiter{l: rate.NewLimiter(rate.Inf, 0)}
+	l.SetLimit(limit)
+	return l
+}
+
+// SetLimit sets the limit per second, zero or negative means no limit.
+func (l *Limiter) SetLimit(limit float64) {
+	if limit <= 0 {
+		l.l.SetLimit(rate.Inf)
+		return
+	}
+	l.l.SetBurst(int(math.Ceil(limit)))
+	l.l.SetLimit(rate.Limit(limit))
+}
+
+// Limit returns the limit per second, zero means no limit.
+func (l *Limiter) Limit() float64 {
+	if l == nil || l.l.Limit() == rate.Inf {
+		return 0
+	}
+	return float64(l.l.Limit())
+}
+
+// WaitN blocks until n events are allowed, n can be greater than the burst, which is taken in chunks,
+// so that the changed limit takes effect in about one second.
+func (l *Limiter) WaitN(ctx context.Context, n int) error {
+	if l == nil {
+		return nil
+	}
+	for n > 0 {
+		if l.l.Limit() == rate.Inf {
+			return nil
+		}
+		k := n
+		if burst := l.l.Burst(); k > burst {
+			k = burst
+		}
+		if err := l.l.WaitN(ctx, k); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			// The limit is changed in the meantime, take again.
+			continue
+		}
+		n -= k
+	}
+	return nil
+}
+
+func NewGroup(limit float64) *Group {
+	return &Group{
+		limit:    limit,
+		limiters: map[string]*Limiter{},
+	}
+}
+
+// Get returns the limiter of the key, which is created on the first call.
+func (g *Group) Get(key string) *Limiter {
+	if g == nil {
+		return nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	l, ok := g.limiters[key]
+	if !ok {
+		l = New(g.limit)
+		g.limiters[key] = l
+	}
+	return l
+}
+
+// SetLimit sets the limit of all the keys.
+func (g *Group) SetLimit(limit float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.limit = limit
+	for _, l := range g.limiters {
+		l.SetLimit(limit)
+	}
+}
+
+// Limit returns the limit of each key, zero means no limit.
+func (g *Group) Limit() float64 {
+	if g == nil {
+		return 0
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.limit <= 0 {
+		return 0
+	}
+	return g.limit
+}
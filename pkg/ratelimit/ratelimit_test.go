@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Limiter", func() {
+	It("nil", func() {
+		var l *Limiter
+		Expect(l.Limit()).To(Equal(0.0))
+		Expect(l.WaitN(context.Background(), 100)).NotTo(HaveOccurred())
+	})
+
+	It("no limit", func() {
+		l := New(0)
+		Expect(l.Limit()).To(Equal(0.0))
+		start := time.Now()
+		Expect(l.WaitN(context.Background(), 1000000)).NotTo(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically("<", 100*time.Millisecond))
+	})
+
+	It("limit", func() {
+		l := New(1000)
+		Expect(l.Limit()).To(Equal(1000.0))
+		start := time.Now()
+		// The first 1000 are the burst, and the others are allowed in 200ms.
+		Expect(l.WaitN(context.Background(), 1200)).NotTo(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically(">=", 150*time.Millisecond))
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+	})
+
+	It("set limit", func() {
+		l := New(1000)
+		l.SetLimit(100)
+		Expect(l.Limit()).To(Equal(100.0))
+		l.SetLimit(-1)
+		Expect(l.Limit()).To(Equal(0.0))
+		start := time.Now()
+		Expect(l.WaitN(context.Background(), 1000000)).NotTo(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically("<", 100*time.Millisecond))
+	})
+
+	It("set limit while waiting", func() {
+		l := New(1)
+		Expect(l.WaitN(context.Background(), 1)).NotTo(HaveOccurred())
+		go func() {
+			defer GinkgoRecover()
+			time.Sleep(50 * time.Millisecond)
+			l.SetLimit(0)
+		}()
+		start := time.Now()
+		Expect(l.WaitN(context.Background(), 5)).NotTo(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically("<", 2*time.Second))
+	})
+
+	It("canceled", func() {
+		l := New(1)
+		Expect(l.WaitN(context.Background(), 1)).NotTo(HaveOccurred())
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		Expect(l.WaitN(ctx, 1)).To(Equal(context.Canceled))
+	})
+})
+
+var _ = Describe("Group", func() {
+	It("nil", func() {
+		var g *Group
+		Expect(g.Limit()).To(Equal(0.0))
+		Expect(g.Get("a")).To(BeNil())
+		Expect(g.Get("a").WaitN(context.Background(), 1)).NotTo(HaveOccurred())
+	})
+
+	It("get and set limit", func() {
+		g := NewGroup(10)
+		Expect(g.Limit()).To(Equal(10.0))
+		a, b := g.Get("a"), g.Get("b")
+		Expect(a).NotTo(BeIdenticalTo(b))
+		Expect(g.Get("a")).To(BeIdenticalTo(a))
+		Expect(a.Limit()).To(Equal(10.0))
+
+		g.SetLimit(20)
+		Expect(g.Limit()).To(Equal(20.0))
+		Expect(a.Limit()).To(Equal(20.0))
+		Expect(b.Limit()).To(Equal(20.0))
+		Expect(g.Get("c").Limit()).To(Equal(20.0))
+
+		g.SetLimit(0)
+		Expect(g.Limit()).To(Equal(0.0))
+		Expect(a.Limit()).To(Equal(0.0))
+	})
+})
@@ -3,6 +3,7 @@ package reader
 
 import (
 	stderrors "errors"
+	"sync/atomic"
 
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/logger"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/source"
@@ -14,6 +15,13 @@ type (
 		Source() source.Source
 		source.Sizer
 		ReadBatch() (int, spec.Records, error)
+		// Skip reads and discards n records, returns the bytes skipped.
+		Skip(n int64) (int64, error)
+	}
+
+	// BatchSizer is implemented by the batch readers whose batch size can be changed while reading.
+	BatchSizer interface {
+		SetBatch(batch int)
 	}
 
 	continueError struct {
@@ -22,7 +30,8 @@ type (
 
 	defaultBatchReader struct {
 		*options
-		rr RecordReader
+		rr        RecordReader
+		batchSize atomic.Int64
 	}
 )
 
@@ -31,6 +40,7 @@ func NewBatchRecordReader(rr RecordReader, opts ...Option) BatchRecordReader {
 		options: newOptions(opts...),
 		rr:      rr,
 	}
+	brr.batchSize.Store(int64(brr.batch))
 	brr.logger = brr.logger.With(logger.Field{Key: "source", Value: rr.Source().Name()})
 	return brr
 }
@@ -49,13 +59,21 @@ func (r *defaultBatchReader) Size() (int64, error) {
 	return r.rr.Size()
 }
 
+// SetBatch changes the batch size of the subsequent batches, it's ignored if not positive.
+func (r *defaultBatchReader) SetBatch(batch int) {
+	if batch > 0 {
+		r.batchSize.Store(int64(batch))
+	}
+}
+
 func (r *defaultBatchReader) ReadBatch() (int, spec.Records, error) { //nolint:gocritic
 	var (
 		totalBytes int
-		records    = make(spec.Records, 0, r.batch)
+		batchSize  = int(r.batchSize.Load())
+		records    = make(spec.Records, 0, batchSize)
 	)
 
-	for batch := 0; batch < r.batch; {
+	for batch := 0; batch < batchSize; {
 		n, record, err := r.rr.Read()
 		totalBytes += n
 		if err != nil {
@@ -79,6 +97,22 @@ func (r *defaultBatchReader) ReadBatch() (int, spec.Records, error) { //nolint:g
 	return totalBytes, records, nil
 }
 
+func (r *defaultBatchReader) Skip(n int64) (int64, error) {
+	var totalBytes int64
+	for skipped := int64(0); skipped < n; {
+		nBytes, _, err := r.rr.Read()
+		totalBytes += int64(nBytes)
+		if err != nil {
+			if ce := new(continueError); stderrors.As(err, &ce) {
+				continue
+			}
+			return totalBytes, err
+		}
+		skipped++
+	}
+	return totalBytes, nil
+}
+
 func (ce *continueError) Error() string {
 	return ce.Err.Error()
 }
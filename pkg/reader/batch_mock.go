@@ -66,6 +66,21 @@ func (mr *MockBatchRecordReaderMockRecorder) Size() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Size", reflect.TypeOf((*MockBatchRecordReader)(nil).Size))
 }
 
+// Skip mocks base method.
+func (m *MockBatchRecordReader) Skip(n int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Skip", n)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Skip indicates an expected call of Skip.
+func (mr *MockBatchRecordReaderMockRecorder) Skip(n interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Skip", reflect.TypeOf((*MockBatchRecordReader)(nil).Skip), n)
+}
+
 // Source mocks base method.
 func (m *MockBatchRecordReader) Source() source.Source {
 	m.ctrl.T.Helper()
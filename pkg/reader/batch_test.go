@@ -147,6 +147,31 @@ var _ = Describe("BatchRecordReader", func() {
 			Expect(records).To(BeEmpty())
 		})
 
+		It("set batch", func() {
+			brr := NewBatchRecordReader(rr, WithBatch(1))
+			sizer, ok := brr.(BatchSizer)
+			Expect(ok).To(BeTrue())
+
+			var (
+				records []spec.Record
+				err     error
+			)
+			sizer.SetBatch(0)
+			_, records, err = brr.ReadBatch()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(Equal([]spec.Record{
+				{"1", "2", "3"},
+			}))
+
+			sizer.SetBatch(2)
+			_, records, err = brr.ReadBatch()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(Equal([]spec.Record{
+				{"4", " 5", "6"},
+				{" 7", "8", " 9"},
+			}))
+		})
+
 		It("3 batch", func() {
 			var (
 				nBytes  int64
@@ -209,6 +234,29 @@ var _ = Describe("BatchRecordReader", func() {
 			Expect(n).To(Equal(0))
 			Expect(records).To(BeEmpty())
 		})
+
+		It("skip", func() {
+			brr := NewBatchRecordReader(rr, WithBatch(2))
+			nBytes, err := brr.Skip(0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nBytes).To(Equal(int64(0)))
+
+			nBytes, err = brr.Skip(3)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nBytes).To(Equal(int64(21)))
+
+			n, records, err := brr.ReadBatch()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(Equal(12))
+			Expect(records).To(Equal(spec.Records{
+				{"10", " 11 ", " 12"},
+			}))
+
+			nBytes, err = brr.Skip(1)
+			Expect(err).To(HaveOccurred())
+			Expect(stderrors.Is(err, io.EOF)).To(BeTrue())
+			Expect(nBytes).To(Equal(int64(0)))
+		})
 	})
 
 	When("failed", func() {
@@ -268,6 +316,20 @@ var _ = Describe("BatchRecordReader", func() {
 			Expect(n).To(Equal(0))
 			Expect(records).To(BeEmpty())
 		})
+
+		It("skip", func() {
+			brr := NewBatchRecordReader(rr, WithBatch(2))
+			nBytes, err := brr.Skip(2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nBytes).To(Equal(int64(13)))
+
+			n, records, err := brr.ReadBatch()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(Equal(3))
+			Expect(records).To(Equal(spec.Records{
+				{"id4"},
+			}))
+		})
 	})
 })
 
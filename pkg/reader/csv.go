@@ -5,7 +5,9 @@ import (
 	"encoding/csv"
 	stderrors "errors"
 	"io"
+	"strings"
 
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/source"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/spec"
 )
@@ -14,6 +16,7 @@ type (
 	header struct {
 		withHeader bool
 		hasRead    bool
+		record     []string
 	}
 
 	csvReader struct {
@@ -63,15 +66,34 @@ func (r *csvReader) Size() (int64, error) {
 	return r.s.Size()
 }
 
+// Header reads the csv header if not read, the column names are the header.
+func (r *csvReader) Header() ([]string, error) {
+	if !r.h.withHeader {
+		return nil, errors.ErrNoHeader
+	}
+	if !r.h.hasRead {
+		r.h.hasRead = true
+
+		record, err := r.cr.Read()
+		if err != nil {
+			return record, r.wrapErr(err)
+		}
+		if len(record) > 0 {
+			// trim the utf-8 byte order mark
+			record[0] = strings.TrimPrefix(record[0], "\ufeff")
+		}
+		r.h.record = record
+	}
+	return r.h.record, nil
+}
+
 func (r *csvReader) Read() (int, spec.Record, error) { //nolint:gocritic
 	// determine whether the reader has read the csv header
 	if r.h.withHeader && !r.h.hasRead {
-		r.h.hasRead = true
-
 		// if read header, read and move to next line
-		record, err := r.cr.Read()
+		record, err := r.Header()
 		if err != nil {
-			return 0, record, r.wrapErr(err)
+			return 0, record, err
 		}
 	}
 
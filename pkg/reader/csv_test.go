@@ -4,6 +4,7 @@ import (
 	stderrors "errors"
 	"io"
 
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/source"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/spec"
 
@@ -181,6 +182,52 @@ var _ = Describe("csvReader", func() {
 		})
 	})
 
+	Describe("Header", func() {
+		It("should success", func() {
+			s, err := source.New(&source.Config{
+				Local: &source.LocalConfig{
+					Path: "testdata/local_withHeader.csv",
+				},
+				CSV: &source.CSVConfig{
+					WithHeader: true,
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.Open()).NotTo(HaveOccurred())
+			defer s.Close()
+
+			r := NewCSVReader(s)
+			header, err := r.(HeaderReader).Header()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(header).To(Equal([]string{"h1", "h2", "h3"}))
+
+			// the header is read only once
+			header, err = r.(HeaderReader).Header()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(header).To(Equal([]string{"h1", "h2", "h3"}))
+
+			n, record, err := r.Read()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(Equal(14))
+			Expect(record).To(Equal(spec.Record{"1", "2", "3"}))
+		})
+
+		It("without header", func() {
+			s, err := source.New(&source.Config{
+				Local: &source.LocalConfig{
+					Path: "testdata/local.csv",
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.Open()).NotTo(HaveOccurred())
+			defer s.Close()
+
+			header, err := ReadHeader(s)
+			Expect(stderrors.Is(err, errors.ErrNoHeader)).To(BeTrue())
+			Expect(header).To(BeEmpty())
+		})
+	})
+
 	Describe("withHeader read failed", func() {
 		var s source.Source
 		BeforeEach(func() {
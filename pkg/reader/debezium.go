@@ -0,0 +1,74 @@
+package reader
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+const (
+	// debeziumFieldPrefix is the prefix of the fields which are looked up in the envelope instead of the row image,
+	// such as __op and __source.table, it's the same as the ExtractNewRecordState of Debezium.
+	debeziumFieldPrefix = "__"
+	// debeziumFieldTs is the ts_ms of the envelope in seconds, which is suitable for the TIMESTAMP props.
+	debeziumFieldTs = "__ts"
+
+	debeziumOpDelete = "d"
+)
+
+// debeziumEvent is the change event of Debezium, the fields are looked up in the row image,
+// which is the before of the deletes, and the after of the others.
+type debeziumEvent struct {
+	envelope map[string]any
+	image    any
+}
+
+// newDebeziumEvent returns false if the event should be skipped, such as the tombstones and the truncates.
+// The payload is unwrapped if the schemas are enabled in the converter, such as {"schema": ..., "payload": ...}.
+func newDebeziumEvent(obj any) (*debeziumEvent, bool) {
+	envelope, ok := obj.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	if payload, ok := envelope["payload"]; ok && envelope["op"] == nil {
+		if envelope, ok = payload.(map[string]any); !ok {
+			return nil, false
+		}
+	}
+
+	op, _ := envelope["op"].(string)
+	e := &debeziumEvent{envelope: envelope}
+	switch op {
+	case "c", "r", "u":
+		e.image = envelope["after"]
+	case debeziumOpDelete:
+		e.image = envelope["before"]
+	default:
+		return nil, false
+	}
+	return e, true
+}
+
+func (e *debeziumEvent) lookup(p jsonPath) any {
+	if len(p) == 0 || !strings.HasPrefix(p[0], debeziumFieldPrefix) {
+		return p.lookup(e.image)
+	}
+	if len(p) == 1 && p[0] == debeziumFieldTs {
+		return e.ts()
+	}
+	envelopePath := append(jsonPath{strings.TrimPrefix(p[0], debeziumFieldPrefix)}, p[1:]...)
+	return envelopePath.lookup(e.envelope)
+}
+
+// ts returns the ts_ms in seconds, or nil if it's not an integer.
+func (e *debeziumEvent) ts() any {
+	n, ok := e.envelope["ts_ms"].(json.Number)
+	if !ok {
+		return nil
+	}
+	ms, err := n.Int64()
+	if err != nil {
+		return nil
+	}
+	return json.Number(strconv.FormatInt(ms/1000, 10))
+}
@@ -0,0 +1,53 @@
+package reader
+
+import (
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("debeziumEvent", func() {
+	decode := func(s string) any {
+		var obj any
+		d := json.NewDecoder(strings.NewReader(s))
+		d.UseNumber()
+		Expect(d.Decode(&obj)).NotTo(HaveOccurred())
+		return obj
+	}
+
+	DescribeTable("newDebeziumEvent skipped",
+		func(s string) {
+			e, ok := newDebeziumEvent(decode(s))
+			Expect(ok).To(BeFalse())
+			Expect(e).To(BeNil())
+		},
+		Entry("tombstone", `null`),
+		Entry("not object", `[1]`),
+		Entry("payload not object", `{"schema": {}, "payload": null}`),
+		Entry("no op", `{"after": {"id": 1}}`),
+		Entry("truncate", `{"op": "t"}`),
+		Entry("message", `{"op": "m"}`),
+	)
+
+	DescribeTable(".lookup",
+		func(s, field string, expect any) {
+			e, ok := newDebeziumEvent(decode(s))
+			Expect(ok).To(BeTrue())
+			if expect == nil {
+				Expect(e.lookup(parseJSONPath(field))).To(BeNil())
+				return
+			}
+			Expect(e.lookup(parseJSONPath(field))).To(Equal(expect))
+		},
+		Entry("create", `{"op": "c", "after": {"id": 1}}`, "id", json.Number("1")),
+		Entry("read", `{"op": "r", "after": {"id": 2}}`, "id", json.Number("2")),
+		Entry("delete", `{"op": "d", "before": {"id": 3}, "after": null}`, "id", json.Number("3")),
+		Entry("missing", `{"op": "u", "after": {"id": 4}}`, "a.b", nil),
+		Entry("envelope", `{"op": "u", "source": {"db": "x"}}`, "__source.db", "x"),
+		Entry("ts", `{"op": "u", "ts_ms": 1500}`, "__ts", json.Number("1")),
+		Entry("ts not number", `{"op": "u", "ts_ms": "x"}`, "__ts", nil),
+		Entry("ts not integer", `{"op": "u", "ts_ms": 1.5}`, "__ts", nil),
+	)
+})
@@ -0,0 +1,188 @@
+package reader
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/source"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/spec"
+)
+
+type (
+	jsonReader struct {
+		*baseReader
+		br       *bufio.Reader
+		fields   []string
+		paths    []jsonPath
+		envelope string
+	}
+
+	// jsonPath is the parsed json path, such as "a.b[0].c" => ["a", "b", "0", "c"].
+	jsonPath []string
+)
+
+func NewJSONReader(s source.Source) RecordReader {
+	var (
+		fields   []string
+		paths    []jsonPath
+		envelope string
+	)
+	if c := s.Config(); c != nil && c.JSON != nil {
+		fields = c.JSON.Fields
+		envelope = c.JSON.Envelope
+		paths = make([]jsonPath, 0, len(c.JSON.Fields))
+		for _, field := range c.JSON.Fields {
+			paths = append(paths, parseJSONPath(field))
+		}
+	}
+
+	return &jsonReader{
+		baseReader: &baseReader{
+			s: s,
+		},
+		br:       bufio.NewReader(s),
+		fields:   fields,
+		paths:    paths,
+		envelope: envelope,
+	}
+}
+
+// Header returns the fields, the column names are the json paths.
+func (r *jsonReader) Header() ([]string, error) {
+	if len(r.fields) == 0 {
+		return nil, errors.ErrNoFields
+	}
+	return r.fields, nil
+}
+
+func (r *jsonReader) Size() (int64, error) {
+	return r.s.Size()
+}
+
+func (r *jsonReader) Read() (int, spec.Record, error) { //nolint:gocritic
+	if len(r.paths) == 0 {
+		return 0, nil, errors.ErrNoFields
+	}
+
+	var nBytes int
+	for {
+		line, err := r.br.ReadBytes('\n')
+		nBytes += len(line)
+		if err != nil && err != io.EOF {
+			return nBytes, nil, err
+		}
+
+		// skip the blank lines
+		if len(bytes.TrimSpace(line)) == 0 {
+			if err != nil {
+				return nBytes, nil, err
+			}
+			continue
+		}
+
+		// The io.EOF of the last line without line break will be returned on the next call.
+		record, decodeErr := r.decode(line)
+		if decodeErr != nil {
+			return nBytes, nil, NewContinueError(decodeErr)
+		}
+		// skip the events without records, such as the tombstones
+		if record == nil {
+			if err != nil {
+				return nBytes, nil, err
+			}
+			continue
+		}
+		return nBytes, record, nil
+	}
+}
+
+// decode returns nil without error if the line is skipped.
+func (r *jsonReader) decode(line []byte) (spec.Record, error) {
+	d := json.NewDecoder(bytes.NewReader(line))
+	d.UseNumber()
+
+	var obj any
+	if err := d.Decode(&obj); err != nil {
+		return nil, err
+	}
+
+	lookup := func(p jsonPath) any { return p.lookup(obj) }
+	if r.envelope != "" {
+		event, ok := newDebeziumEvent(obj)
+		if !ok {
+			return nil, nil
+		}
+		lookup = event.lookup
+	}
+
+	record := make(spec.Record, len(r.paths))
+	for i, p := range r.paths {
+		val, err := jsonValueString(lookup(p))
+		if err != nil {
+			return nil, err
+		}
+		record[i] = val
+	}
+	return record, nil
+}
+
+// SplitJSONPath splits the json path to the segments, such as "a.b[0].c" => ["a", "b", "0", "c"].
+func SplitJSONPath(field string) []string {
+	return parseJSONPath(field)
+}
+
+func parseJSONPath(s string) jsonPath {
+	s = strings.ReplaceAll(s, "[", ".")
+	s = strings.ReplaceAll(s, "]", "")
+	segments := strings.Split(s, ".")
+	p := make(jsonPath, 0, len(segments))
+	for _, segment := range segments {
+		if segment != "" {
+			p = append(p, segment)
+		}
+	}
+	return p
+}
+
+// lookup returns nil if the path does not exist.
+func (p jsonPath) lookup(obj any) any {
+	for _, segment := range p {
+		switch v := obj.(type) {
+		case map[string]any:
+			obj = v[segment]
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil
+			}
+			obj = v[index]
+		default:
+			return nil
+		}
+	}
+	return obj
+}
+
+// jsonValueString converts the json value to the column string, null and missing values are empty strings.
+func jsonValueString(v any) (string, error) {
+	switch val := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return val, nil
+	case json.Number:
+		return val.String(), nil
+	case bool:
+		return strconv.FormatBool(val), nil
+	}
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(bs), nil
+}
@@ -0,0 +1,210 @@
+package reader
+
+import (
+	stderrors "errors"
+	"io"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/source"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/spec"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("jsonReader", func() {
+	Describe("default", func() {
+		var s source.Source
+		BeforeEach(func() {
+			var err error
+			s, err = source.New(&source.Config{
+				Local: &source.LocalConfig{
+					Path: "testdata/local.jsonl",
+				},
+				JSON: &source.JSONConfig{
+					Fields: []string{"id", "name", "info.age", "info.vip", "tags[1]", "tags"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).NotTo(BeNil())
+			err = s.Open()
+			Expect(err).NotTo(HaveOccurred())
+		})
+		AfterEach(func() {
+			err := s.Close()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should success", func() {
+			var (
+				nBytes int64
+				n      int
+				record spec.Record
+				err    error
+			)
+			r := NewRecordReader(s)
+			Expect(r).To(BeAssignableToTypeOf(&jsonReader{}))
+			nBytes, err = r.Size()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nBytes).To(Equal(int64(183)))
+
+			n, record, err = r.Read()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(Equal(77))
+			Expect(record).To(Equal(spec.Record{"1", "a", "18", "true", "y", `["x","y"]`}))
+
+			n, record, err = r.Read()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(Equal(48))
+			Expect(record).To(Equal(spec.Record{"2", "", "20.5", "", "", ""}))
+
+			n, record, err = r.Read()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(Equal(58))
+			Expect(record).To(Equal(spec.Record{"3", "c", "", "false", "", "[]"}))
+
+			n, record, err = r.Read()
+			Expect(err).To(HaveOccurred())
+			Expect(stderrors.Is(err, io.EOF)).To(BeTrue())
+			Expect(n).To(Equal(0))
+			Expect(record).To(BeEmpty())
+		})
+
+		It("Header", func() {
+			header, err := ReadHeader(s)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(header).To(Equal([]string{"id", "name", "info.age", "info.vip", "tags[1]", "tags"}))
+		})
+	})
+
+	Describe("read failed", func() {
+		var s source.Source
+		BeforeEach(func() {
+			var err error
+			s, err = source.New(&source.Config{
+				Local: &source.LocalConfig{
+					Path: "testdata/local_failed.jsonl",
+				},
+				JSON: &source.JSONConfig{
+					Fields: []string{"id"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).NotTo(BeNil())
+			err = s.Open()
+			Expect(err).NotTo(HaveOccurred())
+		})
+		AfterEach(func() {
+			err := s.Close()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should success", func() {
+			var (
+				n      int
+				record spec.Record
+				err    error
+			)
+			r := NewJSONReader(s)
+
+			n, record, err = r.Read()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(Equal(10))
+			Expect(record).To(Equal(spec.Record{"1"}))
+
+			n, record, err = r.Read()
+			Expect(err).To(HaveOccurred())
+			ce := new(continueError)
+			Expect(stderrors.As(err, &ce)).To(BeTrue())
+			Expect(n).To(Equal(8))
+			Expect(record).To(BeEmpty())
+
+			n, record, err = r.Read()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(Equal(10))
+			Expect(record).To(Equal(spec.Record{"3"}))
+
+			n, record, err = r.Read()
+			Expect(err).To(HaveOccurred())
+			Expect(stderrors.Is(err, io.EOF)).To(BeTrue())
+			Expect(n).To(Equal(0))
+			Expect(record).To(BeEmpty())
+		})
+
+		It("no fields", func() {
+			s.Config().JSON.Fields = nil
+			r := NewJSONReader(s)
+			n, record, err := r.Read()
+			Expect(stderrors.Is(err, errors.ErrNoFields)).To(BeTrue())
+			Expect(n).To(Equal(0))
+			Expect(record).To(BeEmpty())
+
+			header, err := ReadHeader(s)
+			Expect(stderrors.Is(err, errors.ErrNoFields)).To(BeTrue())
+			Expect(header).To(BeEmpty())
+		})
+	})
+
+	Describe("debezium", func() {
+		var s source.Source
+		BeforeEach(func() {
+			var err error
+			s, err = source.New(&source.Config{
+				Local: &source.LocalConfig{
+					Path: "testdata/debezium.jsonl",
+				},
+				JSON: &source.JSONConfig{
+					Fields:   []string{"id", "name", "__op", "__ts_ms", "__ts", "__source.table"},
+					Envelope: "Debezium",
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).NotTo(BeNil())
+			err = s.Open()
+			Expect(err).NotTo(HaveOccurred())
+		})
+		AfterEach(func() {
+			err := s.Close()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should success", func() {
+			var (
+				n      int
+				record spec.Record
+				err    error
+			)
+			r := NewJSONReader(s)
+
+			// the payload with the schema
+			n, record, err = r.Read()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(Equal(141))
+			Expect(record).To(Equal(spec.Record{"1", "a", "c", "1700000000123", "1700000000", "users"}))
+
+			n, record, err = r.Read()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(Equal(117))
+			Expect(record).To(Equal(spec.Record{"1", "b", "u", "1700000001000", "1700000001", "users"}))
+
+			// the tombstone and the truncate are skipped, the before image is read for the delete
+			n, record, err = r.Read()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(Equal(194))
+			Expect(record).To(Equal(spec.Record{"1", "b", "d", "1700000003000", "1700000003", "users"}))
+
+			n, record, err = r.Read()
+			Expect(err).To(HaveOccurred())
+			ce := new(continueError)
+			Expect(stderrors.As(err, &ce)).To(BeTrue())
+			Expect(n).To(Equal(7))
+			Expect(record).To(BeEmpty())
+
+			n, record, err = r.Read()
+			Expect(err).To(HaveOccurred())
+			Expect(stderrors.Is(err, io.EOF)).To(BeTrue())
+			Expect(n).To(Equal(0))
+			Expect(record).To(BeEmpty())
+		})
+	})
+})
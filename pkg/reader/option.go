@@ -12,8 +12,9 @@ type (
 	Option func(*options)
 
 	options struct {
-		batch  int
-		logger logger.Logger
+		batch       int
+		logger      logger.Logger
+		usedIndices []int
 	}
 )
 
@@ -29,6 +30,13 @@ func WithLogger(l logger.Logger) Option {
 	}
 }
 
+// WithUsedIndices sets the record indices used, the record readers can skip the other columns if supported.
+func WithUsedIndices(indices ...int) Option {
+	return func(m *options) {
+		m.usedIndices = indices
+	}
+}
+
 func newOptions(opts ...Option) *options {
 	defaultOptions := &options{
 		batch: DefaultBatchSize,
@@ -0,0 +1,402 @@
+package reader
+
+import (
+	"encoding/json"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/source"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/spec"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/deprecated"
+	"github.com/parquet-go/parquet-go/format"
+)
+
+const (
+	parquetValueBufferSize = 128
+
+	// ParquetColumnsMetadataKey is the key of the file metadata which lists the column paths in json,
+	// it is the default order of the columns, such as the dead letters written.
+	ParquetColumnsMetadataKey = "nebula-importer.columns"
+
+	// julianDayOfUnixEpoch is the julian day of 1970-01-01, which is used by the INT96 timestamps.
+	julianDayOfUnixEpoch = 2440588
+)
+
+type (
+	parquetReader struct {
+		*baseReader
+		columns     []string
+		usedIndices []int
+
+		isOpened     bool
+		openErr      error
+		tmpFile      *os.File
+		size         int64
+		f            *parquet.File
+		header       []string
+		leafColumns  []*parquet.LeafColumn // the leaf column of each record column, nil if not used
+		rowGroupIdx  int
+		rowGroupRead int64
+		bytesRead    int64 // the bytes reported
+		rowGroupEnds []int64
+		cols         []*parquetColumnReader
+	}
+
+	parquetColumnReader struct {
+		typ    parquet.Type
+		pages  parquet.Pages
+		values parquet.ValueReader
+		buffer []parquet.Value
+		pos    int
+	}
+)
+
+func NewParquetReader(s source.Source, opts ...Option) RecordReader {
+	o := newOptions(opts...)
+	r := &parquetReader{
+		baseReader: &baseReader{
+			s: s,
+		},
+		usedIndices: o.usedIndices,
+	}
+	if c := s.Config(); c != nil && c.Parquet != nil {
+		r.columns = c.Parquet.Columns
+	}
+	return r
+}
+
+func (r *parquetReader) Size() (int64, error) {
+	return r.s.Size()
+}
+
+// Header returns the column paths, the nested columns are separated by ".".
+func (r *parquetReader) Header() ([]string, error) {
+	if err := r.lazyOpen(); err != nil {
+		return nil, err
+	}
+	return r.header, nil
+}
+
+func (r *parquetReader) Read() (int, spec.Record, error) { //nolint:gocritic
+	if err := r.lazyOpen(); err != nil {
+		return 0, nil, err
+	}
+
+	for r.rowGroupIdx < len(r.f.RowGroups()) {
+		rowGroup := r.f.RowGroups()[r.rowGroupIdx]
+		if r.rowGroupRead == 0 {
+			r.openRowGroup(rowGroup)
+		}
+		if r.rowGroupRead >= rowGroup.NumRows() {
+			r.closeRowGroup()
+			continue
+		}
+
+		record := make(spec.Record, len(r.cols))
+		for i, col := range r.cols {
+			if col == nil {
+				continue
+			}
+			v, err := col.next()
+			if err != nil {
+				return 0, nil, err
+			}
+			if record[i], err = parquetValueString(v, col.typ); err != nil {
+				return 0, nil, err
+			}
+		}
+		r.rowGroupRead++
+
+		// Report the progress when the row group is finished.
+		var nBytes int
+		if r.rowGroupRead == rowGroup.NumRows() {
+			nBytes = int(r.rowGroupEnds[r.rowGroupIdx] - r.bytesRead)
+			r.bytesRead = r.rowGroupEnds[r.rowGroupIdx]
+		}
+		return nBytes, record, nil
+	}
+
+	r.close()
+	// The remaining bytes, such as the footer and the empty row groups at the end.
+	nBytes := int(r.size - r.bytesRead)
+	r.bytesRead = r.size
+	return nBytes, nil, io.EOF
+}
+
+func (r *parquetReader) lazyOpen() error {
+	if !r.isOpened {
+		r.isOpened = true
+		r.openErr = r.open()
+	}
+	return r.openErr
+}
+
+func (r *parquetReader) open() error {
+	size, err := r.s.Size()
+	if err != nil {
+		return err
+	}
+
+	ra, ok := source.ReaderAt(r.s)
+	if !ok {
+		// The parquet file need to be read randomly, so copy it to a temporary file.
+		if r.tmpFile, err = os.CreateTemp("", "nebula-importer-*.parquet"); err != nil {
+			return err
+		}
+		// Remove it in advance as far as possible, in case that the reader is not read to the end.
+		_ = os.Remove(r.tmpFile.Name())
+		if size, err = io.Copy(r.tmpFile, r.s); err != nil {
+			r.close()
+			return err
+		}
+		ra = r.tmpFile
+	}
+
+	r.size = size
+	if r.f, err = parquet.OpenFile(ra, size); err != nil {
+		r.close()
+		return err
+	}
+
+	if err = r.initColumns(); err != nil {
+		r.close()
+		return err
+	}
+
+	// The bytes of each row group are accumulated, and the last one is up to the size, including the footer.
+	rowGroups := r.f.Metadata().RowGroups
+	r.rowGroupEnds = make([]int64, len(rowGroups))
+	var offset int64
+	for i := range rowGroups {
+		offset += parquetRowGroupSize(&rowGroups[i])
+		if offset > size || i == len(rowGroups)-1 {
+			offset = size
+		}
+		r.rowGroupEnds[i] = offset
+	}
+	return nil
+}
+
+func (r *parquetReader) initColumns() error {
+	schema := r.f.Schema()
+
+	columns := r.columns
+	if len(columns) == 0 {
+		if v, ok := r.f.Lookup(ParquetColumnsMetadataKey); ok {
+			if err := json.Unmarshal([]byte(v), &columns); err != nil {
+				return errors.NewImportError(errors.ErrUnsupportedColumn, "parquet metadata %s: %s", ParquetColumnsMetadataKey, err)
+			}
+		}
+	}
+
+	paths := make([][]string, 0, len(columns))
+	if len(columns) > 0 {
+		for _, column := range columns {
+			paths = append(paths, strings.Split(column, "."))
+		}
+	} else {
+		paths = append(paths, schema.Columns()...)
+	}
+
+	isUsed := func(int) bool { return true }
+	if r.usedIndices != nil {
+		used := make(map[int]struct{}, len(r.usedIndices))
+		for _, index := range r.usedIndices {
+			used[index] = struct{}{}
+		}
+		isUsed = func(index int) bool {
+			_, ok := used[index]
+			return ok
+		}
+	}
+
+	r.header = make([]string, len(paths))
+	r.leafColumns = make([]*parquet.LeafColumn, len(paths))
+	for i, path := range paths {
+		r.header[i] = strings.Join(path, ".")
+		if !isUsed(i) {
+			continue
+		}
+		leafColumn, ok := schema.Lookup(path...)
+		if !ok {
+			return errors.NewImportError(errors.ErrNoColumn, "parquet column %s", strings.Join(path, "."))
+		}
+		if leafColumn.MaxRepetitionLevel > 0 {
+			return errors.NewImportError(errors.ErrUnsupportedColumn, "repeated parquet column %s", strings.Join(path, "."))
+		}
+		r.leafColumns[i] = &leafColumn
+	}
+	r.cols = make([]*parquetColumnReader, len(paths))
+	return nil
+}
+
+func (r *parquetReader) openRowGroup(rowGroup parquet.RowGroup) {
+	chunks := rowGroup.ColumnChunks()
+	for i, leafColumn := range r.leafColumns {
+		if leafColumn == nil {
+			continue
+		}
+		chunk := chunks[leafColumn.ColumnIndex]
+		r.cols[i] = &parquetColumnReader{
+			typ:    leafColumn.Node.Type(),
+			pages:  chunk.Pages(),
+			buffer: make([]parquet.Value, 0, parquetValueBufferSize),
+		}
+	}
+}
+
+func (r *parquetReader) closeRowGroup() {
+	for i, col := range r.cols {
+		if col != nil {
+			_ = col.pages.Close()
+			r.cols[i] = nil
+		}
+	}
+	r.rowGroupIdx++
+	r.rowGroupRead = 0
+}
+
+func (r *parquetReader) close() {
+	if r.tmpFile != nil {
+		_ = r.tmpFile.Close()
+		_ = os.Remove(r.tmpFile.Name())
+		r.tmpFile = nil
+	}
+}
+
+func (c *parquetColumnReader) next() (parquet.Value, error) {
+	for c.pos >= len(c.buffer) {
+		if c.values == nil {
+			page, err := c.pages.ReadPage()
+			if err != nil {
+				if err == io.EOF {
+					err = io.ErrUnexpectedEOF
+				}
+				return parquet.Value{}, err
+			}
+			c.values = page.Values()
+		}
+
+		n, err := c.values.ReadValues(c.buffer[:cap(c.buffer)])
+		c.buffer, c.pos = c.buffer[:n], 0
+		if err != nil {
+			if err != io.EOF {
+				return parquet.Value{}, err
+			}
+			c.values = nil
+		}
+	}
+	v := c.buffer[c.pos]
+	c.pos++
+	return v, nil
+}
+
+func parquetRowGroupSize(rowGroup *format.RowGroup) int64 {
+	if rowGroup.TotalCompressedSize > 0 {
+		return rowGroup.TotalCompressedSize
+	}
+	var size int64
+	for i := range rowGroup.Columns {
+		size += rowGroup.Columns[i].MetaData.TotalCompressedSize
+	}
+	return size
+}
+
+// parquetValueString converts the typed value to the column string, null values are empty strings.
+//
+//revive:disable-next-line:cyclomatic
+func parquetValueString(v parquet.Value, t parquet.Type) (string, error) {
+	if v.IsNull() {
+		return "", nil
+	}
+
+	if lt := t.LogicalType(); lt != nil {
+		switch {
+		case lt.Timestamp != nil:
+			return parquetTime(v.Int64(), &lt.Timestamp.Unit).Format("2006-01-02T15:04:05.999999"), nil
+		case lt.Date != nil:
+			return time.Unix(int64(v.Int32())*24*60*60, 0).UTC().Format("2006-01-02"), nil
+		case lt.Time != nil:
+			var tm time.Time
+			if lt.Time.Unit.Millis != nil {
+				tm = parquetTime(int64(v.Int32()), &lt.Time.Unit)
+			} else {
+				tm = parquetTime(v.Int64(), &lt.Time.Unit)
+			}
+			return tm.Format("15:04:05.999999"), nil
+		case lt.Decimal != nil:
+			return parquetDecimal(v, int(lt.Decimal.Scale)), nil
+		}
+	}
+
+	switch v.Kind() {
+	case parquet.Boolean:
+		return strconv.FormatBool(v.Boolean()), nil
+	case parquet.Int32:
+		return strconv.FormatInt(int64(v.Int32()), 10), nil
+	case parquet.Int64:
+		return strconv.FormatInt(v.Int64(), 10), nil
+	case parquet.Int96:
+		return parquetInt96Time(v.Int96()).Format("2006-01-02T15:04:05.999999"), nil
+	case parquet.Float:
+		return strconv.FormatFloat(float64(v.Float()), 'f', -1, 32), nil
+	case parquet.Double:
+		return strconv.FormatFloat(v.Double(), 'f', -1, 64), nil
+	case parquet.ByteArray, parquet.FixedLenByteArray:
+		return string(v.ByteArray()), nil
+	}
+	return "", errors.NewImportError(errors.ErrUnsupportedColumn, "parquet kind %s", v.Kind())
+}
+
+func parquetTime(v int64, unit *format.TimeUnit) time.Time {
+	switch {
+	case unit.Millis != nil:
+		return time.UnixMilli(v).UTC()
+	case unit.Nanos != nil:
+		return time.Unix(0, v).UTC()
+	}
+	return time.UnixMicro(v).UTC()
+}
+
+func parquetInt96Time(v deprecated.Int96) time.Time {
+	nanosOfDay := int64(v[1])<<32 | int64(v[0])
+	days := int64(v[2]) - julianDayOfUnixEpoch
+	return time.Unix(days*24*60*60, nanosOfDay).UTC()
+}
+
+func parquetDecimal(v parquet.Value, scale int) string {
+	var unscaled *big.Int
+	switch v.Kind() { //nolint:exhaustive
+	case parquet.Int32:
+		unscaled = big.NewInt(int64(v.Int32()))
+	case parquet.Int64:
+		unscaled = big.NewInt(v.Int64())
+	default:
+		// big-endian two's complement
+		bs := v.ByteArray()
+		unscaled = new(big.Int).SetBytes(bs)
+		if len(bs) > 0 && bs[0]&0x80 != 0 {
+			unscaled.Sub(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(len(bs))*8))
+		}
+	}
+
+	s := unscaled.String()
+	if scale <= 0 {
+		return s
+	}
+	sign := ""
+	if s[0] == '-' {
+		sign, s = "-", s[1:]
+	}
+	if len(s) <= scale {
+		s = strings.Repeat("0", scale-len(s)+1) + s
+	}
+	return sign + s[:len(s)-scale] + "." + s[len(s)-scale:]
+}
@@ -0,0 +1,190 @@
+package reader
+
+import (
+	stderrors "errors"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/source"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/spec"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/parquet-go/parquet-go"
+)
+
+var _ = Describe("parquetReader", func() {
+	type (
+		parquetAddress struct {
+			City string `parquet:"city"`
+		}
+		parquetRow struct {
+			ID        int64          `parquet:"id"`
+			Name      *string        `parquet:"name,optional"`
+			Score     float64        `parquet:"score"`
+			Active    bool           `parquet:"active"`
+			CreatedAt time.Time      `parquet:"created_at,timestamp(millisecond)"`
+			Birthday  int32          `parquet:"birthday,date"`
+			Address   parquetAddress `parquet:"address"`
+		}
+		// readerOnlySource hides the io.ReaderAt of the local source.
+		readerOnlySource struct {
+			source.Source
+		}
+	)
+
+	var (
+		tmpdir string
+		path   string
+		size   int64
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpdir, err = os.MkdirTemp("", "test")
+		Expect(err).NotTo(HaveOccurred())
+		path = filepath.Join(tmpdir, "local.parquet")
+
+		name := "Tom"
+		err = parquet.WriteFile(path, []parquetRow{
+			{1, &name, 1.5, true, time.Date(2023, 1, 2, 3, 4, 5, 6e6, time.UTC), 19000, parquetAddress{"Paris"}},
+			{2, nil, 2, false, time.Unix(0, 0), 0, parquetAddress{"London"}},
+			{3, &name, -0.25, true, time.Unix(1, 0), 1, parquetAddress{""}},
+		}, parquet.MaxRowsPerRowGroup(2))
+		Expect(err).NotTo(HaveOccurred())
+
+		fi, err := os.Stat(path)
+		Expect(err).NotTo(HaveOccurred())
+		size = fi.Size()
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(tmpdir)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	openSource := func(c *source.ParquetConfig) source.Source {
+		s, err := source.New(&source.Config{
+			Local: &source.LocalConfig{
+				Path: path,
+			},
+			Parquet: c,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Open()).NotTo(HaveOccurred())
+		DeferCleanup(s.Close)
+		return s
+	}
+
+	readAll := func(r RecordReader) (int64, []spec.Record) {
+		var (
+			totalBytes int64
+			records    []spec.Record
+		)
+		for {
+			n, record, err := r.Read()
+			totalBytes += int64(n)
+			if err != nil {
+				Expect(stderrors.Is(err, io.EOF)).To(BeTrue())
+				return totalBytes, records
+			}
+			records = append(records, record)
+		}
+	}
+
+	It("all columns", func() {
+		r := NewRecordReader(openSource(&source.ParquetConfig{}))
+		Expect(r).To(BeAssignableToTypeOf(&parquetReader{}))
+
+		nBytes, err := r.Size()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nBytes).To(Equal(size))
+
+		// The progress is reported per row group.
+		n, record, err := r.Read()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(n).To(Equal(0))
+		Expect(record).To(Equal(spec.Record{"1", "Tom", "1.5", "true", "2023-01-02T03:04:05.006", "2022-01-08", "Paris"}))
+
+		n, record, err = r.Read()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(n).To(BeNumerically(">", 0))
+		Expect(record).To(Equal(spec.Record{"2", "", "2", "false", "1970-01-01T00:00:00", "1970-01-01", "London"}))
+
+		totalBytes, records := readAll(r)
+		Expect(totalBytes + int64(n)).To(Equal(size))
+		Expect(records).To(Equal([]spec.Record{
+			{"3", "Tom", "-0.25", "true", "1970-01-01T00:00:01", "1970-01-02", ""},
+		}))
+	})
+
+	It("columns and used indices", func() {
+		s := openSource(&source.ParquetConfig{
+			Columns: []string{"address.city", "id", "score"},
+		})
+		totalBytes, records := readAll(NewRecordReader(s, WithUsedIndices(1, 0)))
+		Expect(totalBytes).To(Equal(size))
+		Expect(records).To(Equal([]spec.Record{
+			{"Paris", "1", ""},
+			{"London", "2", ""},
+			{"", "3", ""},
+		}))
+	})
+
+	It("Header", func() {
+		header, err := ReadHeader(openSource(&source.ParquetConfig{}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(header).To(Equal([]string{"id", "name", "score", "active", "created_at", "birthday", "address.city"}))
+
+		header, err = ReadHeader(openSource(&source.ParquetConfig{
+			Columns: []string{"address.city", "id"},
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(header).To(Equal([]string{"address.city", "id"}))
+	})
+
+	It("not io.ReaderAt", func() {
+		s := openSource(&source.ParquetConfig{
+			Columns: []string{"id"},
+		})
+		totalBytes, records := readAll(NewParquetReader(readerOnlySource{Source: s}))
+		Expect(totalBytes).To(Equal(size))
+		Expect(records).To(Equal([]spec.Record{{"1"}, {"2"}, {"3"}}))
+	})
+
+	It("no column", func() {
+		s := openSource(&source.ParquetConfig{
+			Columns: []string{"id", "not-exists"},
+		})
+		r := NewParquetReader(s)
+		_, _, err := r.Read()
+		Expect(stderrors.Is(err, errors.ErrNoColumn)).To(BeTrue())
+		_, err = r.(HeaderReader).Header()
+		Expect(stderrors.Is(err, errors.ErrNoColumn)).To(BeTrue())
+
+		// skip the unused columns
+		totalBytes, records := readAll(NewParquetReader(openSource(&source.ParquetConfig{
+			Columns: []string{"id", "not-exists"},
+		}), WithUsedIndices(0)))
+		Expect(totalBytes).To(Equal(size))
+		Expect(records).To(Equal([]spec.Record{{"1", ""}, {"2", ""}, {"3", ""}}))
+	})
+
+	It("not parquet", func() {
+		s, err := source.New(&source.Config{
+			Local: &source.LocalConfig{
+				Path: "testdata/local.csv",
+			},
+			Parquet: &source.ParquetConfig{},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Open()).NotTo(HaveOccurred())
+		defer s.Close()
+
+		_, _, err = NewParquetReader(s).Read()
+		Expect(err).To(HaveOccurred())
+	})
+})
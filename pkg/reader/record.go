@@ -2,6 +2,9 @@
 package reader
 
 import (
+	"io"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/source"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/spec"
 )
@@ -12,9 +15,68 @@ type (
 		source.Sizer
 		Read() (int, spec.Record, error)
 	}
+
+	// HeaderReader is implemented by the record readers which know the names of the columns.
+	HeaderReader interface {
+		Header() ([]string, error)
+	}
+
+	// compressedRecordReader reports the compressed bytes instead of the decompressed bytes,
+	// to be consistent with the size of the source.
+	compressedRecordReader struct {
+		RecordReader
+		c        source.Compressed
+		reported int64
+	}
 )
 
-func NewRecordReader(s source.Source) RecordReader {
-	// TODO: support other source formats
+func NewRecordReader(s source.Source, opts ...Option) RecordReader {
+	rr := newRecordReader(s, opts...)
+	if c, ok := s.(source.Compressed); ok {
+		rr = &compressedRecordReader{
+			RecordReader: rr,
+			c:            c,
+		}
+	}
+	return rr
+}
+
+// ReadHeader reads the names of the columns from the opened source,
+// such as the header of csv files, the fields of json files and the columns of parquet files.
+func ReadHeader(s source.Source) ([]string, error) {
+	hr, ok := newRecordReader(s).(HeaderReader)
+	if !ok {
+		return nil, errors.ErrNoHeader
+	}
+	return hr.Header()
+}
+
+func newRecordReader(s source.Source, opts ...Option) RecordReader {
+	if c := s.Config(); c != nil {
+		switch {
+		case c.JSON != nil:
+			return NewJSONReader(s)
+		case c.Parquet != nil:
+			return NewParquetReader(s, opts...)
+		}
+	}
 	return NewCSVReader(s)
 }
+
+func (r *compressedRecordReader) Read() (int, spec.Record, error) { //nolint:gocritic
+	n, record, err := r.RecordReader.Read()
+	consumed, compressed := r.c.CompressedBytes()
+	if !compressed {
+		return n, record, err
+	}
+
+	if err == io.EOF {
+		// The decompressor may not consume the trailing bytes, report all the remaining bytes.
+		if size, sizeErr := r.Size(); sizeErr == nil && size > consumed {
+			consumed = size
+		}
+	}
+	n = int(consumed - r.reported)
+	r.reported = consumed
+	return n, record, err
+}
@@ -1,7 +1,11 @@
 package reader
 
 import (
+	stderrors "errors"
+	"io"
+
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/source"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/spec"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -31,3 +35,55 @@ var _ = Describe("RecordReader", func() {
 		Expect(r).NotTo(BeNil())
 	})
 })
+
+var _ = Describe("compressedRecordReader", func() {
+	It("should report the compressed bytes", func() {
+		s := source.NewDecompressSource(newLocalSourceForTest("testdata/local.csv.gz"))
+		Expect(s.Open()).NotTo(HaveOccurred())
+		defer s.Close()
+
+		r := NewRecordReader(s)
+		Expect(r).To(BeAssignableToTypeOf(&compressedRecordReader{}))
+
+		nBytes, err := r.Size()
+		Expect(err).NotTo(HaveOccurred())
+
+		var (
+			totalBytes int64
+			records    []spec.Record
+		)
+		for {
+			n, record, err := r.Read()
+			totalBytes += int64(n)
+			if err != nil {
+				Expect(stderrors.Is(err, io.EOF)).To(BeTrue())
+				break
+			}
+			records = append(records, record)
+		}
+		Expect(totalBytes).To(Equal(nBytes))
+		Expect(records).To(HaveLen(4))
+		Expect(records[0]).To(Equal(spec.Record{"1", "2", "3"}))
+	})
+
+	It("not compressed", func() {
+		s := source.NewDecompressSource(newLocalSourceForTest("testdata/local.csv"))
+		Expect(s.Open()).NotTo(HaveOccurred())
+		defer s.Close()
+
+		n, record, err := NewRecordReader(s).Read()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(n).To(Equal(6))
+		Expect(record).To(Equal(spec.Record{"1", "2", "3"}))
+	})
+})
+
+func newLocalSourceForTest(path string) source.Source {
+	s, err := source.New(&source.Config{
+		Local: &source.LocalConfig{
+			Path: path,
+		},
+	})
+	Expect(err).NotTo(HaveOccurred())
+	return s
+}
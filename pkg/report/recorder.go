@@ -0,0 +1,120 @@
+package report
+
+import (
+	stderrors "errors"
+	"strings"
+	"sync"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+)
+
+const (
+	// CategoryBuild is the failure to build the statement from the records, such as the invalid values.
+	CategoryBuild = "build"
+	// CategoryConnection is the failure to send the statement to the graphd.
+	CategoryConnection = "connection"
+	// CategoryExecution is the failure returned by the graphd when executing the statement.
+	CategoryExecution = "execution"
+
+	DefaultMaxFailedStatements = 10
+)
+
+type (
+	// Recorder collects the failures and the hook outcomes during the import, it's safe for concurrent use.
+	Recorder struct {
+		mu                  sync.Mutex
+		maxFailedStatements int
+		failures            map[string]int64
+		failedStatements    []*FailedStatement
+		hooks               []*HookOutcome
+	}
+
+	RecorderOption func(*Recorder)
+)
+
+func NewRecorder(opts ...RecorderOption) *Recorder {
+	r := &Recorder{
+		maxFailedStatements: DefaultMaxFailedStatements,
+		failures:            map[string]int64{},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// WithMaxFailedStatements sets the max number of the failing statements sampled.
+func WithMaxFailedStatements(n int) RecorderOption {
+	return func(r *Recorder) {
+		if n >= 0 {
+			r.maxFailedStatements = n
+		}
+	}
+}
+
+// RecordError counts the failed request of the source by the category, and samples the failing statement.
+func (r *Recorder) RecordError(source string, err error) {
+	category := Category(err)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.failures[category]++
+	if len(r.failedStatements) >= r.maxFailedStatements {
+		return
+	}
+	failed := &FailedStatement{
+		Source:   source,
+		Category: category,
+		Error:    errorMessage(err),
+	}
+	if e, ok := errors.AsImportError(err); ok {
+		failed.Tag = e.NodeName()
+		failed.Edge = e.EdgeName()
+		failed.Statement = e.Statement()
+	}
+	r.failedStatements = append(r.failedStatements, failed)
+}
+
+// RecordHook records the outcome of the statement executed in the hook.
+func (r *Recorder) RecordHook(hook, statement string, err error) {
+	outcome := &HookOutcome{
+		Hook:      hook,
+		Statement: statement,
+		Succeeded: err == nil,
+	}
+	if err != nil {
+		outcome.Error = errorMessage(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks = append(r.hooks, outcome)
+}
+
+// Category returns the category of the error returned by the importer.
+func Category(err error) string {
+	if stderrors.Is(err, errors.ErrExecuteFailed) {
+		return CategoryExecution
+	}
+	if e, ok := errors.AsImportError(err); ok && e.Statement() != "" {
+		return CategoryConnection
+	}
+	return CategoryBuild
+}
+
+// errorMessage returns the messages and the cause of the error without the fields, such as the statement.
+func errorMessage(err error) string {
+	e, ok := errors.AsImportError(err)
+	if !ok {
+		return err.Error()
+	}
+	messages := make([]string, 0, len(e.Messages)+1)
+	for _, m := range e.Messages {
+		messages = append(messages, strings.TrimSpace(m))
+	}
+	if e.Err != nil {
+		messages = append(messages, e.Err.Error())
+	}
+	return strings.Join(messages, ": ")
+}
@@ -0,0 +1,72 @@
+package report
+
+import (
+	stderrors "errors"
+	"sync"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Recorder", func() {
+	DescribeTable("Category",
+		func(err error, expected string) {
+			Expect(Category(err)).To(Equal(expected))
+		},
+		Entry("execution", errors.NewImportError(errors.ErrExecuteFailed).SetStatement("s"), CategoryExecution),
+		Entry("connection", errors.NewImportError(stderrors.New("test error")).SetStatement("s"), CategoryConnection),
+		Entry("build", errors.NewImportError(errors.ErrUnsupportedValueType).SetRecord([]string{"a"}), CategoryBuild),
+		Entry("not import error", stderrors.New("test error"), CategoryBuild),
+	)
+
+	It("RecordError", func() {
+		r := NewRecorder(WithMaxFailedStatements(2))
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				r.RecordError("s1", errors.NewImportError(errors.ErrExecuteFailed, "the execute error is -1005:failed ").
+					SetEdgeName("e1").SetStatement("INSERT EDGE ..."))
+			}()
+		}
+		wg.Wait()
+		r.RecordError("s2", stderrors.New("test error"))
+
+		rpt := New(r, nil)
+		Expect(rpt.Total).To(BeNil())
+		Expect(rpt.Failures).To(Equal(map[string]int64{
+			CategoryExecution: 10,
+			CategoryBuild:     1,
+		}))
+		Expect(rpt.FailedStatements).To(HaveLen(2))
+		Expect(rpt.FailedStatements[0]).To(Equal(&FailedStatement{
+			Source:    "s1",
+			Category:  CategoryExecution,
+			Edge:      "e1",
+			Statement: "INSERT EDGE ...",
+			Error:     "the execute error is -1005:failed: execute failed",
+		}))
+	})
+
+	It("WithMaxFailedStatements", func() {
+		r := NewRecorder(WithMaxFailedStatements(0))
+		r.RecordError("s1", stderrors.New("test error"))
+		Expect(New(r, nil).FailedStatements).To(BeEmpty())
+
+		r = NewRecorder(WithMaxFailedStatements(-1))
+		Expect(r.maxFailedStatements).To(Equal(DefaultMaxFailedStatements))
+	})
+
+	It("RecordHook", func() {
+		r := NewRecorder()
+		r.RecordHook("before", "s1", nil)
+		r.RecordHook("after", "s2", stderrors.New("test error"))
+		Expect(New(r, nil).Hooks).To(Equal([]*HookOutcome{
+			{Hook: "before", Statement: "s1", Succeeded: true},
+			{Hook: "after", Statement: "s2", Error: "test error"},
+		}))
+	})
+})
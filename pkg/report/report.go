@@ -0,0 +1,182 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/stats"
+)
+
+type (
+	// RunReport is the machine-readable summary of the import, which is written in JSON.
+	RunReport struct {
+		Version          string                   `json:"version"`
+		ConfigFile       string                   `json:"configFile"`
+		ConfigDigest     string                   `json:"configDigest,omitempty"`
+		StartTime        time.Time                `json:"startTime"`
+		EndTime          time.Time                `json:"endTime"`
+		Succeeded        bool                     `json:"succeeded"`
+		ExitCode         int                      `json:"exitCode"`
+		Error            string                   `json:"error,omitempty"`
+		Total            *SourceCounts            `json:"total,omitempty"`
+		Sources          map[string]*SourceCounts `json:"sources,omitempty"`
+		Targets          []*TargetCounts          `json:"targets,omitempty"`
+		Failures         map[string]int64         `json:"failures,omitempty"`
+		FailedStatements []*FailedStatement       `json:"failedStatements,omitempty"`
+		Hooks            []*HookOutcome           `json:"hooks,omitempty"`
+	}
+
+	SourceCounts struct {
+		TotalBytes     int64 `json:"totalBytes"`
+		ProcessedBytes int64 `json:"processedBytes"`
+		TotalRecords   int64 `json:"totalRecords"`
+		FailedRecords  int64 `json:"failedRecords"`
+		SkippedRecords int64 `json:"skippedRecords"`
+		RequestCounts
+	}
+
+	// TargetCounts are the counts of the tag or edge in the mode.
+	TargetCounts struct {
+		Kind string `json:"kind"`
+		Name string `json:"name"`
+		Mode string `json:"mode,omitempty"`
+		RequestCounts
+	}
+
+	RequestCounts struct {
+		TotalRequests   int64        `json:"totalRequests"`
+		FailedRequests  int64        `json:"failedRequests"`
+		TotalProcessed  int64        `json:"totalProcessed"`
+		FailedProcessed int64        `json:"failedProcessed"`
+		Latency         *Percentiles `json:"latency,omitempty"`
+		RespTime        *Percentiles `json:"respTime,omitempty"`
+	}
+
+	// Percentiles are in seconds.
+	Percentiles struct {
+		P50 float64 `json:"p50"`
+		P90 float64 `json:"p90"`
+		P99 float64 `json:"p99"`
+		Max float64 `json:"max"`
+	}
+
+	FailedStatement struct {
+		Source    string `json:"source,omitempty"`
+		Category  string `json:"category"`
+		Tag       string `json:"tag,omitempty"`
+		Edge      string `json:"edge,omitempty"`
+		Statement string `json:"statement,omitempty"`
+		Error     string `json:"error"`
+	}
+
+	HookOutcome struct {
+		Hook      string `json:"hook"`
+		Statement string `json:"statement"`
+		Succeeded bool   `json:"succeeded"`
+		Error     string `json:"error,omitempty"`
+	}
+)
+
+// New returns the report of the stats and the outcomes collected by the recorder, both can be nil
+// if the import is not started.
+func New(r *Recorder, s *stats.Stats) *RunReport {
+	rpt := &RunReport{}
+	if s != nil {
+		rpt.Total = sourceCounts(s)
+		if len(s.Sources) > 0 {
+			rpt.Sources = make(map[string]*SourceCounts, len(s.Sources))
+			for name, st := range s.Sources {
+				rpt.Sources[name] = sourceCounts(st)
+			}
+		}
+		targets := make([]stats.Target, 0, len(s.Targets))
+		for t := range s.Targets {
+			targets = append(targets, t)
+		}
+		sort.Slice(targets, func(i, j int) bool {
+			return targets[i].String() < targets[j].String()
+		})
+		for _, t := range targets {
+			rpt.Targets = append(rpt.Targets, &TargetCounts{
+				Kind:          t.Kind,
+				Name:          t.Name,
+				Mode:          t.Mode,
+				RequestCounts: requestCounts(s.Targets[t]),
+			})
+		}
+	}
+	if r != nil {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if len(r.failures) > 0 {
+			rpt.Failures = make(map[string]int64, len(r.failures))
+			for category, n := range r.failures {
+				rpt.Failures[category] = n
+			}
+		}
+		rpt.FailedStatements = append(rpt.FailedStatements, r.failedStatements...)
+		rpt.Hooks = append(rpt.Hooks, r.hooks...)
+	}
+	return rpt
+}
+
+// SetConfigFile sets the config file and its sha256 digest, the digest is empty if the file is not readable.
+func (rpt *RunReport) SetConfigFile(configFile string) *RunReport {
+	rpt.ConfigFile = configFile
+	if content, err := os.ReadFile(configFile); err == nil {
+		sum := sha256.Sum256(content)
+		rpt.ConfigDigest = "sha256:" + hex.EncodeToString(sum[:])
+	}
+	return rpt
+}
+
+// WriteFile writes the report in JSON to the file.
+func (rpt *RunReport) WriteFile(filename string) error {
+	content, err := json.MarshalIndent(rpt, "", "  ")
+	if err != nil {
+		return errors.NewImportError(err, "report: marshal failed")
+	}
+	if err = os.WriteFile(filename, append(content, '\n'), 0o644); err != nil { //nolint:gosec
+		return errors.NewImportError(err, "report: write failed").SetFileName(filename)
+	}
+	return nil
+}
+
+func sourceCounts(s *stats.Stats) *SourceCounts {
+	return &SourceCounts{
+		TotalBytes:     s.TotalBytes,
+		ProcessedBytes: s.ProcessedBytes,
+		TotalRecords:   s.TotalRecords,
+		FailedRecords:  s.FailedRecords,
+		SkippedRecords: s.SkippedRecords,
+		RequestCounts:  requestCounts(s),
+	}
+}
+
+func requestCounts(s *stats.Stats) RequestCounts {
+	return RequestCounts{
+		TotalRequests:   s.TotalRequest,
+		FailedRequests:  s.FailedRequest,
+		TotalProcessed:  s.TotalProcessed,
+		FailedProcessed: s.FailedProcessed,
+		Latency:         percentiles(s.LatencyHistogram),
+		RespTime:        percentiles(s.RespTimeHistogram),
+	}
+}
+
+func percentiles(h *stats.Histogram) *Percentiles {
+	if h.Count() == 0 {
+		return nil
+	}
+	return &Percentiles{
+		P50: h.Percentile(50).Seconds(),
+		P90: h.Percentile(90).Seconds(),
+		P99: h.Percentile(99).Seconds(),
+		Max: h.Max().Seconds(),
+	}
+}
@@ -0,0 +1,94 @@
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/stats"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Report", func() {
+	It("New", func() {
+		tag := stats.Target{Kind: "tag", Name: "n1", Mode: "INSERT"}
+		edge := stats.Target{Kind: "edge", Name: "e1"}
+		cs := stats.NewConcurrencyStats()
+		cs.Init()
+		cs.AddSourceTotalBytes("s1", 100)
+		cs.SourceSucceeded("s1", 80, 8)
+		cs.SourceFailed("s1", 20, 2)
+		cs.TargetRequestSucceeded("s1", tag, 8, time.Millisecond, 2*time.Millisecond)
+		cs.TargetRequestFailed("s1", edge, 2)
+
+		rpt := New(nil, cs.Stats())
+		Expect(rpt.Total).To(Equal(&SourceCounts{
+			TotalBytes:     100,
+			ProcessedBytes: 100,
+			TotalRecords:   10,
+			FailedRecords:  2,
+			RequestCounts: RequestCounts{
+				TotalRequests:   2,
+				FailedRequests:  1,
+				TotalProcessed:  10,
+				FailedProcessed: 2,
+				Latency:         &Percentiles{P50: 0.001, P90: 0.001, P99: 0.001, Max: 0.001},
+				RespTime:        &Percentiles{P50: 0.002, P90: 0.002, P99: 0.002, Max: 0.002},
+			},
+		}))
+		Expect(rpt.Sources).To(Equal(map[string]*SourceCounts{"s1": rpt.Total}))
+		Expect(rpt.Targets).To(HaveLen(2))
+		Expect(rpt.Targets[0]).To(Equal(&TargetCounts{
+			Kind: "edge",
+			Name: "e1",
+			RequestCounts: RequestCounts{
+				TotalRequests:   1,
+				FailedRequests:  1,
+				TotalProcessed:  2,
+				FailedProcessed: 2,
+			},
+		}))
+		Expect(rpt.Targets[1].Kind).To(Equal("tag"))
+		Expect(rpt.Targets[1].Mode).To(Equal("INSERT"))
+		Expect(rpt.Targets[1].Latency).NotTo(BeNil())
+		Expect(rpt.Failures).To(BeNil())
+		Expect(rpt.Hooks).To(BeNil())
+	})
+
+	It("SetConfigFile", func() {
+		file := filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		Expect(os.WriteFile(file, []byte("abc"), 0o600)).NotTo(HaveOccurred())
+
+		rpt := (&RunReport{}).SetConfigFile(file)
+		Expect(rpt.ConfigFile).To(Equal(file))
+		Expect(rpt.ConfigDigest).To(Equal("sha256:ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"))
+
+		rpt = (&RunReport{}).SetConfigFile(filepath.Join(GinkgoT().TempDir(), "not-exists.yaml"))
+		Expect(rpt.ConfigDigest).To(BeEmpty())
+	})
+
+	It("WriteFile", func() {
+		file := filepath.Join(GinkgoT().TempDir(), "report.json")
+		rpt := &RunReport{
+			ConfigFile: "config.yaml",
+			Succeeded:  false,
+			ExitCode:   4,
+			Error:      "failed to import",
+			Failures:   map[string]int64{CategoryExecution: 1},
+		}
+		Expect(rpt.WriteFile(file)).NotTo(HaveOccurred())
+
+		content, err := os.ReadFile(file)
+		Expect(err).NotTo(HaveOccurred())
+		var m map[string]any
+		Expect(json.Unmarshal(content, &m)).NotTo(HaveOccurred())
+		Expect(m).To(HaveKeyWithValue("exitCode", 4.0))
+		Expect(m).To(HaveKeyWithValue("failures", map[string]any{"execution": 1.0}))
+		Expect(m).NotTo(HaveKey("total"))
+
+		Expect(rpt.WriteFile(filepath.Join(file, "not-exists"))).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,375 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/client"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/picker"
+	specv3 "github.com/vesoft-inc/nebula-importer/v4/pkg/spec/v3"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/utils"
+)
+
+const (
+	// DefaultStringVidType is the vid type of the created space if any vid is a string.
+	DefaultStringVidType = "FIXED_STRING(64)"
+	DefaultWaitTimeout   = time.Minute
+	DefaultWaitInterval  = time.Second
+	// DefaultHeartbeatDelay is two heartbeats of the default heartbeat_interval_secs, the graph and storage services
+	// cache the schema of the meta service, and refresh the caches by the heartbeats.
+	DefaultHeartbeatDelay = 2 * 10 * time.Second
+)
+
+type (
+	CreateOption func(*creator)
+
+	creator struct {
+		cli            client.Client
+		graphName      string
+		alter          bool
+		vidType        string
+		partitionNum   int
+		replicaFactor  int
+		waitTimeout    time.Duration
+		waitInterval   time.Duration
+		heartbeatDelay time.Duration
+		changed        bool // whether any schema is created or altered
+	}
+
+	// definition is the schema of a tag or edge derived from the config.
+	definition struct {
+		kind  string
+		name  string
+		props specv3.Props
+	}
+)
+
+// WithAlter adds the props missing in the existing tags and edges.
+func WithAlter() CreateOption {
+	return func(c *creator) {
+		c.alter = true
+	}
+}
+
+// WithVidType sets the vid type of the created space, it's derived from the vids in the config if not set.
+func WithVidType(vidType string) CreateOption {
+	return func(c *creator) {
+		c.vidType = vidType
+	}
+}
+
+func WithPartitionNum(partitionNum int) CreateOption {
+	return func(c *creator) {
+		c.partitionNum = partitionNum
+	}
+}
+
+func WithReplicaFactor(replicaFactor int) CreateOption {
+	return func(c *creator) {
+		c.replicaFactor = replicaFactor
+	}
+}
+
+// WithWaitTimeout sets the max time to wait for the created schema to be described.
+func WithWaitTimeout(timeout time.Duration) CreateOption {
+	return func(c *creator) {
+		if timeout > 0 {
+			c.waitTimeout = timeout
+		}
+	}
+}
+
+// WithHeartbeatDelay sets the time to wait after the schema is created or altered, since it's described from the
+// meta service, but the graph and storage services only load it in the next heartbeats.
+func WithHeartbeatDelay(delay time.Duration) CreateOption {
+	return func(c *creator) {
+		if delay > 0 {
+			c.heartbeatDelay = delay
+		}
+	}
+}
+
+func WithWaitInterval(interval time.Duration) CreateOption {
+	return func(c *creator) {
+		if interval > 0 {
+			c.waitInterval = interval
+		}
+	}
+}
+
+// Create creates the space, tags and edges which do not exist, waits until they can be described,
+// and then waits for the heartbeat delay if any is created or altered.
+// The existing tags and edges are not changed unless WithAlter is set.
+func Create(cli client.Client, graphName string, nodes specv3.Nodes, edges specv3.Edges, opts ...CreateOption) error {
+	c := &creator{
+		cli:            cli,
+		graphName:      graphName,
+		waitTimeout:    DefaultWaitTimeout,
+		waitInterval:   DefaultWaitInterval,
+		heartbeatDelay: DefaultHeartbeatDelay,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.vidType == "" {
+		c.vidType = deriveVidType(nodes, edges)
+	}
+
+	if err := c.create(definitions(nodes, edges)); err != nil {
+		return errors.AsOrNewImportError(err).SetGraphName(graphName)
+	}
+	return nil
+}
+
+func (c *creator) create(defs []*definition) error {
+	if err := c.createSpace(); err != nil {
+		return err
+	}
+	for _, def := range defs {
+		if err := c.createDefinition(def); err != nil {
+			return err
+		}
+	}
+	if c.changed {
+		// The DESCRIBE statements are answered by the meta service, the inserts fail until the caches are refreshed.
+		time.Sleep(c.heartbeatDelay)
+	}
+	return nil
+}
+
+func (c *creator) createSpace() error {
+	_, err := describeSpace(c.cli, c.graphName)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	if err != nil {
+		if err = c.execute(c.createSpaceStatement()); err != nil {
+			return err
+		}
+	}
+
+	// The USE statement fails until the space is propagated to the graph service.
+	return c.wait(fmt.Sprintf("space %s", c.graphName), func() (bool, error) {
+		if err := use(c.cli, c.graphName); err != nil {
+			if isNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	})
+}
+
+func (c *creator) createDefinition(def *definition) error {
+	fields, err := describeFields(c.cli, def.kind, def.name)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+
+	if err != nil {
+		err = c.execute(fmt.Sprintf("CREATE %s IF NOT EXISTS %s(%s)",
+			def.kind, utils.ConvertIdentifier(def.name), propDefinitions(def.props)))
+	} else {
+		missing := missingProps(def.props, fields)
+		if len(missing) == 0 || !c.alter {
+			return nil
+		}
+		err = c.execute(fmt.Sprintf("ALTER %s %s ADD (%s)",
+			def.kind, utils.ConvertIdentifier(def.name), propDefinitions(missing)))
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.wait(fmt.Sprintf("%s %s", strings.ToLower(def.kind), def.name), func() (bool, error) {
+		fields, err := describeFields(c.cli, def.kind, def.name)
+		if err != nil {
+			if isNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		return len(missingProps(def.props, fields)) == 0, nil
+	})
+}
+
+func (c *creator) createSpaceStatement() string {
+	var sb strings.Builder
+	sb.WriteString("CREATE SPACE IF NOT EXISTS ")
+	sb.WriteString(utils.ConvertIdentifier(c.graphName))
+	sb.WriteString("(")
+	if c.partitionNum > 0 {
+		fmt.Fprintf(&sb, "partition_num = %d, ", c.partitionNum)
+	}
+	if c.replicaFactor > 0 {
+		fmt.Fprintf(&sb, "replica_factor = %d, ", c.replicaFactor)
+	}
+	sb.WriteString("vid_type = ")
+	sb.WriteString(c.vidType)
+	sb.WriteString(")")
+	return sb.String()
+}
+
+func (c *creator) execute(statement string) error {
+	resp, err := c.cli.Execute(statement)
+	if err != nil {
+		return errors.NewImportError(err).SetStatement(statement)
+	}
+	if !resp.IsSucceed() {
+		return errors.NewImportError(resp.GetError()).SetStatement(statement)
+	}
+	c.changed = true
+	return nil
+}
+
+// wait calls fn until it returns true or an error, or the timeout is reached.
+func (c *creator) wait(name string, fn func() (bool, error)) error {
+	deadline := time.Now().Add(c.waitTimeout)
+	for {
+		ok, err := fn()
+		if err != nil || ok {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return errors.NewImportError(errors.ErrSchemaNotReady, "%s is not ready in %s", name, c.waitTimeout)
+		}
+		time.Sleep(c.waitInterval)
+	}
+}
+
+// definitions merges the props of the tags and edges with the same name, the first one wins.
+func definitions(nodes specv3.Nodes, edges specv3.Edges) []*definition {
+	var defs []*definition
+	defMap := map[string]*definition{}
+	add := func(kind, name string, props specv3.Props) {
+		key := kind + " " + name
+		def, ok := defMap[key]
+		if !ok {
+			def = &definition{kind: kind, name: name}
+			defMap[key] = def
+			defs = append(defs, def)
+		}
+		for _, prop := range props {
+			if !hasProp(def.props, prop.Name) {
+				def.props = append(def.props, prop)
+			}
+		}
+	}
+	for _, node := range nodes {
+		add(KindTag, node.Name, node.Props)
+	}
+	for _, edge := range edges {
+		add(KindEdge, edge.Name, edge.Props)
+	}
+	return defs
+}
+
+// deriveVidType returns INT64 if all the vids are int, DefaultStringVidType otherwise.
+func deriveVidType(nodes specv3.Nodes, edges specv3.Edges) string {
+	ids := make([]*specv3.NodeID, 0, len(nodes)+2*len(edges))
+	for _, node := range nodes {
+		ids = append(ids, node.ID)
+	}
+	for _, edge := range edges {
+		if edge.Src != nil {
+			ids = append(ids, edge.Src.ID)
+		}
+		if edge.Dst != nil {
+			ids = append(ids, edge.Dst.ID)
+		}
+	}
+
+	for _, id := range ids {
+		if id == nil || id.Function != nil {
+			// The hash function returns int64.
+			continue
+		}
+		if !strings.EqualFold(id.Type.String(), specv3.ValueTypeInt.String()) {
+			return DefaultStringVidType
+		}
+	}
+	return "INT64"
+}
+
+func propDefinitions(props specv3.Props) string {
+	defs := make([]string, 0, len(props))
+	for _, prop := range props {
+		defs = append(defs, propDefinition(prop))
+	}
+	return strings.Join(defs, ", ")
+}
+
+// propDefinition returns the definition of the prop, such as "`age` INT64 NULL DEFAULT 18".
+func propDefinition(prop *specv3.Prop) string {
+	var sb strings.Builder
+	sb.WriteString(utils.ConvertIdentifier(prop.Name))
+	sb.WriteString(" ")
+	sb.WriteString(schemaType(prop.Type))
+	if prop.Nullable {
+		sb.WriteString(" NULL")
+	} else {
+		sb.WriteString(" NOT NULL")
+	}
+	if prop.DefaultValue != nil {
+		if value, ok := defaultValue(prop); ok {
+			sb.WriteString(" DEFAULT ")
+			sb.WriteString(value)
+		}
+	}
+	return sb.String()
+}
+
+// schemaType returns the type in the schema of the type in the config.
+func schemaType(t specv3.ValueType) string {
+	t = specv3.ValueType(strings.ToUpper(t.String()))
+	if t == specv3.ValueTypeInt {
+		return "INT64"
+	}
+	return t.String()
+}
+
+// defaultValue converts the default value to the literal of the type, the same as the values to be imported.
+func defaultValue(prop *specv3.Prop) (string, bool) {
+	c := picker.Config{
+		Indices: []int{0},
+		Type:    prop.Type.String(),
+	}
+	p, err := c.Build()
+	if err != nil {
+		return "", false
+	}
+	v, err := p.Pick([]string{*prop.DefaultValue})
+	if err != nil {
+		return "", false
+	}
+	defer v.Release()
+	return v.Val, true
+}
+
+func missingProps(props specv3.Props, fields []Field) specv3.Props {
+	var missing specv3.Props
+	for _, prop := range props {
+		found := false
+		for _, f := range fields {
+			if f.Name == prop.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, prop)
+		}
+	}
+	return missing
+}
+
+func hasProp(props specv3.Props, name string) bool {
+	for _, prop := range props {
+		if prop.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+package schema
+
+import (
+	stderrors "errors"
+	"time"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+	specv3 "github.com/vesoft-inc/nebula-importer/v4/pkg/spec/v3"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Create", func() {
+	var (
+		nodes specv3.Nodes
+		edges specv3.Edges
+		opts  []CreateOption
+	)
+	BeforeEach(func() {
+		defaultAge := "18"
+		defaultName := "unknown"
+		nodes = specv3.Nodes{
+			specv3.NewNode("person",
+				specv3.WithNodeID(&specv3.NodeID{Name: "id", Type: specv3.ValueTypeString}),
+				specv3.WithNodeProps(
+					&specv3.Prop{Name: "name", Type: specv3.ValueTypeString, Nullable: true, DefaultValue: &defaultName},
+					&specv3.Prop{Name: "age", Type: specv3.ValueTypeInt, DefaultValue: &defaultAge},
+				),
+			),
+			specv3.NewNode("person",
+				specv3.WithNodeID(&specv3.NodeID{Name: "id", Type: specv3.ValueTypeString}),
+				specv3.WithNodeProps(
+					&specv3.Prop{Name: "age", Type: specv3.ValueTypeInt},
+					&specv3.Prop{Name: "birthday", Type: specv3.ValueTypeDate, Nullable: true},
+				),
+			),
+		}
+		edges = specv3.Edges{
+			specv3.NewEdge("follow",
+				specv3.WithEdgeSrc(&specv3.EdgeNodeRef{ID: &specv3.NodeID{Name: "id", Type: specv3.ValueTypeString}}),
+				specv3.WithEdgeDst(&specv3.EdgeNodeRef{ID: &specv3.NodeID{Name: "id", Type: specv3.ValueTypeString}}),
+				specv3.WithEdgeProps(&specv3.Prop{Name: "degree", Type: specv3.ValueTypeDouble}),
+			),
+		}
+		opts = []CreateOption{WithWaitInterval(time.Microsecond), WithWaitTimeout(10 * time.Millisecond), WithHeartbeatDelay(time.Microsecond)}
+	})
+
+	It("create all", func() {
+		cli := newFakeClient()
+		delete(cli.responses, "USE `graphName`")
+		cli.withHook("CREATE SPACE IF NOT EXISTS `graphName`(partition_num = 10, replica_factor = 1, vid_type = FIXED_STRING(32))", func() {
+			cli.withSpace("FIXED_STRING(32)")
+			cli.responses["USE `graphName`"] = &fakeResponse{}
+		}).withHook("CREATE TAG IF NOT EXISTS `person`(`name` STRING NULL DEFAULT \"unknown\", `age` INT64 NOT NULL DEFAULT 18, `birthday` DATE NULL)", func() {
+			cli.withSchema(KindTag, "person",
+				Field{Name: "name", Type: "string", IsNullable: true, Default: "\"unknown\""},
+				Field{Name: "age", Type: "int64", Default: "18"},
+				Field{Name: "birthday", Type: "date", IsNullable: true},
+			)
+		}).withHook("CREATE EDGE IF NOT EXISTS `follow`(`degree` DOUBLE NOT NULL)", func() {
+			cli.withSchema(KindEdge, "follow", Field{Name: "degree", Type: "double"})
+		})
+		cli.responses["CREATE SPACE IF NOT EXISTS `graphName`(partition_num = 10, replica_factor = 1, vid_type = FIXED_STRING(32))"] = &fakeResponse{}
+		cli.responses["CREATE TAG IF NOT EXISTS `person`(`name` STRING NULL DEFAULT \"unknown\", `age` INT64 NOT NULL DEFAULT 18, `birthday` DATE NULL)"] = &fakeResponse{}
+		cli.responses["CREATE EDGE IF NOT EXISTS `follow`(`degree` DOUBLE NOT NULL)"] = &fakeResponse{}
+
+		opts = append(opts, WithPartitionNum(10), WithReplicaFactor(1), WithVidType("FIXED_STRING(32)"))
+		Expect(Create(cli, "graphName", nodes, edges, opts...)).NotTo(HaveOccurred())
+		Expect(cli.statements).To(Equal([]string{
+			"DESCRIBE SPACE `graphName`",
+			"CREATE SPACE IF NOT EXISTS `graphName`(partition_num = 10, replica_factor = 1, vid_type = FIXED_STRING(32))",
+			"USE `graphName`",
+			"DESCRIBE TAG `person`",
+			"CREATE TAG IF NOT EXISTS `person`(`name` STRING NULL DEFAULT \"unknown\", `age` INT64 NOT NULL DEFAULT 18, `birthday` DATE NULL)",
+			"DESCRIBE TAG `person`",
+			"DESCRIBE EDGE `follow`",
+			"CREATE EDGE IF NOT EXISTS `follow`(`degree` DOUBLE NOT NULL)",
+			"DESCRIBE EDGE `follow`",
+		}))
+	})
+
+	It("alter", func() {
+		cli := newFakeClient().withSpace("FIXED_STRING(32)").
+			withSchema(KindTag, "person", Field{Name: "name", Type: "string", IsNullable: true}).
+			withSchema(KindEdge, "follow", Field{Name: "degree", Type: "double"})
+		statement := "ALTER TAG `person` ADD (`age` INT64 NOT NULL DEFAULT 18, `birthday` DATE NULL)"
+		cli.responses[statement] = &fakeResponse{}
+		waited := 0
+		cli.withHook("DESCRIBE TAG `person`", func() {
+			// The altered props are described after a while.
+			if waited++; waited == 3 {
+				cli.withSchema(KindTag, "person",
+					Field{Name: "name", Type: "string", IsNullable: true},
+					Field{Name: "age", Type: "int64", Default: "18"},
+					Field{Name: "birthday", Type: "date", IsNullable: true},
+				)
+			}
+		})
+
+		start := time.Now()
+		Expect(Create(cli, "graphName", nodes, edges, append(opts, WithAlter(), WithHeartbeatDelay(50*time.Millisecond))...)).NotTo(HaveOccurred())
+		Expect(cli.statements).To(ContainElement(statement))
+		Expect(waited).To(Equal(3))
+		// The altered props are described by the meta service, the caches of the other services are refreshed later.
+		Expect(time.Since(start)).To(BeNumerically(">=", 50*time.Millisecond))
+	})
+
+	It("without alter", func() {
+		cli := newFakeClient().withSpace("FIXED_STRING(32)").
+			withSchema(KindTag, "person", Field{Name: "name", Type: "string", IsNullable: true}).
+			withSchema(KindEdge, "follow", Field{Name: "degree", Type: "double"})
+		// No heartbeat delay since nothing is changed.
+		Expect(Create(cli, "graphName", nodes, edges, append(opts, WithHeartbeatDelay(time.Hour))...)).NotTo(HaveOccurred())
+		Expect(cli.statements).To(Equal([]string{
+			"DESCRIBE SPACE `graphName`",
+			"USE `graphName`",
+			"DESCRIBE TAG `person`",
+			"DESCRIBE EDGE `follow`",
+		}))
+	})
+
+	It("wait timeout", func() {
+		cli := newFakeClient().withSpace("FIXED_STRING(32)").
+			withSchema(KindTag, "person")
+		cli.responses["CREATE EDGE IF NOT EXISTS `follow`(`degree` DOUBLE NOT NULL)"] = &fakeResponse{}
+		err := Create(cli, "graphName", nodes[:1], edges, opts...)
+		Expect(err).To(HaveOccurred())
+		Expect(stderrors.Is(err, errors.ErrSchemaNotReady)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("edge follow is not ready"))
+	})
+
+	It("create failed", func() {
+		cli := newFakeClient()
+		err := Create(cli, "graphName", nodes, edges, opts...)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("CREATE SPACE IF NOT EXISTS `graphName`(vid_type = FIXED_STRING(64))"))
+	})
+
+	It("execute failed", func() {
+		cli := newFakeClient()
+		cli.err = stderrors.New("test error")
+		err := Create(cli, "graphName", nodes, edges, opts...)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("test error"))
+	})
+
+	DescribeTable("deriveVidType",
+		func(t specv3.ValueType, function *string, expected string) {
+			nodes := specv3.Nodes{
+				specv3.NewNode("n1", specv3.WithNodeID(&specv3.NodeID{Name: "id", Type: specv3.ValueTypeInt})),
+				specv3.NewNode("n2", specv3.WithNodeID(&specv3.NodeID{Name: "id", Type: t, Function: function})),
+			}
+			Expect(deriveVidType(nodes, nil)).To(Equal(expected))
+		},
+		Entry("int", specv3.ValueTypeInt, nil, "INT64"),
+		Entry("string", specv3.ValueTypeString, nil, DefaultStringVidType),
+		Entry("hash", specv3.ValueTypeString, new(string), "INT64"),
+	)
+
+	DescribeTable("propDefinition",
+		func(prop *specv3.Prop, expected string) {
+			Expect(propDefinition(prop)).To(Equal(expected))
+		},
+		Entry("int", &specv3.Prop{Name: "p", Type: "int"}, "`p` INT64 NOT NULL"),
+		Entry("nullable", &specv3.Prop{Name: "p", Type: specv3.ValueTypeFloat, Nullable: true}, "`p` FLOAT NULL"),
+		Entry("string default", &specv3.Prop{Name: "p", Type: specv3.ValueTypeString, DefaultValue: new(string)}, "`p` STRING NOT NULL DEFAULT \"\""),
+		Entry("date default", &specv3.Prop{Name: "p", Type: specv3.ValueTypeDate, DefaultValue: func() *string { s := "2020-01-01"; return &s }()}, "`p` DATE NOT NULL DEFAULT DATE(\"2020-01-01\")"),
+		Entry("geo", &specv3.Prop{Name: "p", Type: specv3.ValueTypeGeoPoint}, "`p` GEOGRAPHY(POINT) NOT NULL"),
+	)
+})
@@ -0,0 +1,135 @@
+package schema
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strings"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/client"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/utils"
+)
+
+const (
+	KindTag  = "TAG"
+	KindEdge = "EDGE"
+)
+
+type (
+	// Space is the result of DESCRIBE SPACE.
+	Space struct {
+		Name    string
+		VidType string
+	}
+
+	// Field is a row of the result of DESCRIBE TAG or DESCRIBE EDGE.
+	Field struct {
+		Name       string
+		Type       string
+		IsNullable bool
+		// Default is the default value expression, empty if it has no default value.
+		Default string
+	}
+
+	// notFoundError is returned when the space, tag or edge does not exist, the statement is succeeded otherwise.
+	notFoundError struct {
+		err error
+	}
+)
+
+func (e notFoundError) Error() string {
+	return e.err.Error()
+}
+
+func isNotFound(err error) bool {
+	var e notFoundError
+	return stderrors.As(err, &e)
+}
+
+// describeSpace returns the space, or a notFoundError if it does not exist.
+func describeSpace(cli client.Client, name string) (*Space, error) {
+	rows, err := describe(cli, fmt.Sprintf("DESCRIBE SPACE %s", utils.ConvertIdentifier(name)), "Vid Type")
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, notFoundError{err: fmt.Errorf("space %s not found", name)}
+	}
+	return &Space{
+		Name:    name,
+		VidType: rows[0][0],
+	}, nil
+}
+
+// describeFields returns the fields of the tag or edge in the current space, or a notFoundError if it does not exist.
+func describeFields(cli client.Client, kind, name string) ([]Field, error) {
+	rows, err := describe(cli, fmt.Sprintf("DESCRIBE %s %s", kind, utils.ConvertIdentifier(name)), "Field", "Type", "Null", "Default")
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]Field, 0, len(rows))
+	for _, row := range rows {
+		fields = append(fields, Field{
+			Name:       row[0],
+			Type:       row[1],
+			IsNullable: strings.EqualFold(row[2], "YES"),
+			Default:    row[3],
+		})
+	}
+	return fields, nil
+}
+
+func use(cli client.Client, graphName string) error {
+	statement := fmt.Sprintf("USE %s", utils.ConvertIdentifier(graphName))
+	resp, err := cli.Execute(statement)
+	if err != nil {
+		return errors.NewImportError(err).SetGraphName(graphName).SetStatement(statement)
+	}
+	if !resp.IsSucceed() {
+		return notFoundError{err: resp.GetError()}
+	}
+	return nil
+}
+
+// describe returns the values of the columns, a failed DESCRIBE is taken as not found.
+func describe(cli client.Client, statement string, columns ...string) ([][]string, error) {
+	resp, err := cli.Execute(statement)
+	if err != nil {
+		return nil, errors.NewImportError(err).SetStatement(statement)
+	}
+	if !resp.IsSucceed() {
+		return nil, notFoundError{err: resp.GetError()}
+	}
+
+	colNames := resp.GetColNames()
+	indices := make([]int, len(columns))
+	for i, column := range columns {
+		indices[i] = -1
+		for j, colName := range colNames {
+			if strings.EqualFold(colName, column) {
+				indices[i] = j
+				break
+			}
+		}
+		if indices[i] < 0 {
+			return nil, errors.NewImportError(errors.ErrNoColumn, "column %s not found in the result", column).
+				SetStatement(statement)
+		}
+	}
+
+	rows, err := resp.GetRowValues()
+	if err != nil {
+		return nil, errors.NewImportError(err).SetStatement(statement)
+	}
+	values := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		value := make([]string, len(indices))
+		for i, index := range indices {
+			if index < len(row) {
+				value[i] = row[index]
+			}
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
@@ -0,0 +1,121 @@
+package schema
+
+import (
+	stderrors "errors"
+	"time"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/client"
+)
+
+var (
+	_ client.Client   = (*fakeClient)(nil)
+	_ client.Response = (*fakeResponse)(nil)
+)
+
+type (
+	// fakeClient returns the responses by the statements, and fails the statements not found.
+	fakeClient struct {
+		responses map[string]*fakeResponse
+		// hooks are called before the responses are looked up, such as to create the schema.
+		hooks      map[string]func()
+		statements []string
+		err        error
+	}
+
+	fakeResponse struct {
+		colNames []string
+		rows     [][]string
+		err      error
+	}
+)
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		responses: map[string]*fakeResponse{
+			"USE `graphName`": {},
+		},
+		hooks: map[string]func(){},
+	}
+}
+
+func (c *fakeClient) withSpace(vidType string) *fakeClient {
+	c.responses["DESCRIBE SPACE `graphName`"] = &fakeResponse{
+		colNames: []string{"ID", "Name", "Partition Number", "Replica Factor", "Charset", "Collate", "Vid Type"},
+		rows:     [][]string{{"1", "graphName", "10", "1", "utf8", "utf8_bin", vidType}},
+	}
+	return c
+}
+
+func (c *fakeClient) withSchema(kind, name string, fields ...Field) *fakeClient {
+	resp := &fakeResponse{
+		colNames: []string{"Field", "Type", "Null", "Default", "Comment"},
+	}
+	for _, f := range fields {
+		null := "NO"
+		if f.IsNullable {
+			null = "YES"
+		}
+		resp.rows = append(resp.rows, []string{f.Name, f.Type, null, f.Default, ""})
+	}
+	c.responses["DESCRIBE "+kind+" `"+name+"`"] = resp
+	return c
+}
+
+func (c *fakeClient) withHook(statement string, hook func()) *fakeClient {
+	c.hooks[statement] = hook
+	return c
+}
+
+func (*fakeClient) Open() error {
+	return nil
+}
+
+func (c *fakeClient) Execute(statement string) (client.Response, error) {
+	c.statements = append(c.statements, statement)
+	if c.err != nil {
+		return nil, c.err
+	}
+	if hook, ok := c.hooks[statement]; ok {
+		hook()
+	}
+	if resp, ok := c.responses[statement]; ok {
+		return resp, nil
+	}
+	return &fakeResponse{err: stderrors.New("-1005:not existed")}, nil
+}
+
+func (*fakeClient) Close() error {
+	return nil
+}
+
+func (r *fakeResponse) IsSucceed() bool {
+	return r.err == nil
+}
+
+func (*fakeResponse) GetLatency() time.Duration {
+	return 0
+}
+
+func (*fakeResponse) GetRespTime() time.Duration {
+	return 0
+}
+
+func (r *fakeResponse) GetError() error {
+	return r.err
+}
+
+func (*fakeResponse) IsPermanentError() bool {
+	return false
+}
+
+func (*fakeResponse) IsRetryMoreError() bool {
+	return false
+}
+
+func (r *fakeResponse) GetColNames() []string {
+	return r.colNames
+}
+
+func (r *fakeResponse) GetRowValues() ([][]string, error) {
+	return r.rows, nil
+}
@@ -0,0 +1,218 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/client"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+	specbase "github.com/vesoft-inc/nebula-importer/v4/pkg/spec/base"
+	specv3 "github.com/vesoft-inc/nebula-importer/v4/pkg/spec/v3"
+)
+
+// reGeoType matches the geography types, such as "geography" and "geography(point)".
+var reGeoType = regexp.MustCompile(`^geography(\((\w+)\))?$`)
+
+type validator struct {
+	cli        client.Client
+	graphName  string
+	space      *Space
+	fields     map[string][]Field
+	notFound   map[string]error
+	mismatches []string
+}
+
+// Validate checks the tags and edges against the schema of the space.
+// It returns an error with ErrSchemaMismatch listing all the mismatches if any.
+func Validate(cli client.Client, graphName string, nodes specv3.Nodes, edges specv3.Edges) error {
+	v := &validator{
+		cli:       cli,
+		graphName: graphName,
+		fields:    map[string][]Field{},
+		notFound:  map[string]error{},
+	}
+	if err := v.validate(nodes, edges); err != nil {
+		return errors.AsOrNewImportError(err).SetGraphName(graphName)
+	}
+	if len(v.mismatches) == 0 {
+		return nil
+	}
+
+	e := errors.NewImportError(errors.ErrSchemaMismatch).SetGraphName(graphName)
+	for _, mismatch := range v.mismatches {
+		e.AppendMessage(mismatch)
+	}
+	return e
+}
+
+func (v *validator) validate(nodes specv3.Nodes, edges specv3.Edges) error {
+	space, err := describeSpace(v.cli, v.graphName)
+	if err != nil {
+		if isNotFound(err) {
+			v.addMismatch("space %s not found: %s", v.graphName, err)
+			return nil
+		}
+		return err
+	}
+	v.space = space
+
+	if err = use(v.cli, v.graphName); err != nil {
+		return err
+	}
+
+	for _, node := range nodes {
+		if err = v.validateNode(node); err != nil {
+			return err
+		}
+	}
+	for _, edge := range edges {
+		if err = v.validateEdge(edge); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *validator) validateNode(node *specv3.Node) error {
+	prefix := fmt.Sprintf("tag %s", node.Name)
+	v.validateVID(prefix+" id", node.ID)
+	return v.validateProps(prefix, KindTag, node.Name, node.Modes(), node.Props)
+}
+
+func (v *validator) validateEdge(edge *specv3.Edge) error {
+	prefix := fmt.Sprintf("edge %s", edge.Name)
+	if edge.Src != nil {
+		v.validateVID(prefix+" src id", edge.Src.ID)
+	}
+	if edge.Dst != nil {
+		v.validateVID(prefix+" dst id", edge.Dst.ID)
+	}
+	return v.validateProps(prefix, KindEdge, edge.Name, edge.Modes(), edge.Props)
+}
+
+func (v *validator) validateVID(prefix string, id *specv3.NodeID) {
+	if id == nil {
+		return
+	}
+	t := id.Type
+	if id.Function != nil {
+		// The hash function returns int64.
+		t = specv3.ValueTypeInt
+	}
+	isIntVID := strings.EqualFold(v.space.VidType, "INT64")
+	if isIntVID != strings.EqualFold(t.String(), specv3.ValueTypeInt.String()) {
+		v.addMismatch("%s is %s in the config, but the vid type of the space is %s", prefix, t, v.space.VidType)
+	}
+}
+
+func (v *validator) validateProps(prefix, kind, name string, modes []specbase.Mode, props specv3.Props) error {
+	fields, err := v.describeFields(kind, name)
+	if err != nil {
+		if isNotFound(err) {
+			v.addMismatch("%s not found: %s", prefix, err)
+			return nil
+		}
+		return err
+	}
+
+	// The props are not written when deleting.
+	isDelete, isInsert := true, false
+	for _, mode := range modes {
+		mode = mode.Convert()
+		isDelete = isDelete && mode.IsDelete()
+		isInsert = isInsert || mode == specbase.InsertMode || mode == specbase.UpsertMode
+	}
+	if isDelete {
+		return nil
+	}
+
+	fieldMap := make(map[string]Field, len(fields))
+	for _, f := range fields {
+		fieldMap[f.Name] = f
+	}
+
+	propNames := make(map[string]struct{}, len(props))
+	for _, prop := range props {
+		propNames[prop.Name] = struct{}{}
+		f, ok := fieldMap[prop.Name]
+		if !ok {
+			v.addMismatch("%s prop %s not found in the schema", prefix, prop.Name)
+			continue
+		}
+		if !isTypeMatched(prop.Type, f.Type) {
+			v.addMismatch("%s prop %s is %s in the config, but %s in the schema", prefix, prop.Name, prop.Type, f.Type)
+		}
+		if prop.Nullable && !f.IsNullable {
+			v.addMismatch("%s prop %s is nullable in the config, but NOT NULL in the schema", prefix, prop.Name)
+		}
+	}
+
+	// The props without values must be nullable or have default values when inserting or upserting.
+	if isInsert {
+		for _, f := range fields {
+			if _, ok := propNames[f.Name]; ok || f.IsNullable || f.Default != "" {
+				continue
+			}
+			v.addMismatch("%s prop %s is NOT NULL without default value in the schema, but missing in the config", prefix, f.Name)
+		}
+	}
+	return nil
+}
+
+func (v *validator) describeFields(kind, name string) ([]Field, error) {
+	key := kind + " " + name
+	if err, ok := v.notFound[key]; ok {
+		return nil, err
+	}
+	if fields, ok := v.fields[key]; ok {
+		return fields, nil
+	}
+	fields, err := describeFields(v.cli, kind, name)
+	if err != nil {
+		if isNotFound(err) {
+			v.notFound[key] = err
+		}
+		return nil, err
+	}
+	v.fields[key] = fields
+	return fields, nil
+}
+
+func (v *validator) addMismatch(format string, args ...any) {
+	v.mismatches = append(v.mismatches, fmt.Sprintf(format, args...))
+}
+
+// isTypeMatched returns whether the values of the type in the config can be written to the type in the schema.
+func isTypeMatched(t specv3.ValueType, schemaType string) bool {
+	schemaType = strings.ToLower(strings.TrimSpace(schemaType))
+	switch specv3.ValueType(strings.ToUpper(t.String())) {
+	case specv3.ValueTypeBool:
+		return schemaType == "bool"
+	case specv3.ValueTypeInt:
+		return schemaType == "int64" || schemaType == "int32" || schemaType == "int16" || schemaType == "int8"
+	case specv3.ValueTypeString:
+		return schemaType == "string" || strings.HasPrefix(schemaType, "fixed_string")
+	case specv3.ValueTypeFloat, specv3.ValueTypeDouble:
+		// The literals of them are the same.
+		return schemaType == "float" || schemaType == "double"
+	case specv3.ValueTypeDate:
+		return schemaType == "date"
+	case specv3.ValueTypeTime:
+		return schemaType == "time"
+	case specv3.ValueTypeDateTime:
+		return schemaType == "datetime"
+	case specv3.ValueTypeTimestamp:
+		return schemaType == "timestamp"
+	case specv3.ValueTypeGeo:
+		return reGeoType.MatchString(schemaType)
+	case specv3.ValueTypeGeoPoint, specv3.ValueTypeGeoLineString, specv3.ValueTypeGeoPolygon:
+		matches := reGeoType.FindStringSubmatch(schemaType)
+		if matches == nil {
+			return false
+		}
+		// The geography without shape accepts all the shapes.
+		return matches[2] == "" || strings.EqualFold(t.String(), "GEOGRAPHY("+matches[2]+")")
+	}
+	return false
+}
@@ -0,0 +1,192 @@
+package schema
+
+import (
+	stderrors "errors"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+	specbase "github.com/vesoft-inc/nebula-importer/v4/pkg/spec/base"
+	specv3 "github.com/vesoft-inc/nebula-importer/v4/pkg/spec/v3"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Validate", func() {
+	var (
+		nodes specv3.Nodes
+		edges specv3.Edges
+	)
+	BeforeEach(func() {
+		nodes = specv3.Nodes{
+			specv3.NewNode("person",
+				specv3.WithNodeID(&specv3.NodeID{Name: "id", Type: specv3.ValueTypeString}),
+				specv3.WithNodeProps(
+					&specv3.Prop{Name: "name", Type: specv3.ValueTypeString},
+					&specv3.Prop{Name: "age", Type: specv3.ValueTypeInt, Nullable: true},
+					&specv3.Prop{Name: "location", Type: specv3.ValueTypeGeoPoint},
+				),
+			),
+		}
+		edges = specv3.Edges{
+			specv3.NewEdge("follow",
+				specv3.WithEdgeSrc(&specv3.EdgeNodeRef{ID: &specv3.NodeID{Name: "id", Type: specv3.ValueTypeString}}),
+				specv3.WithEdgeDst(&specv3.EdgeNodeRef{ID: &specv3.NodeID{Name: "id", Type: specv3.ValueTypeString}}),
+				specv3.WithEdgeProps(&specv3.Prop{Name: "degree", Type: specv3.ValueTypeFloat}),
+			),
+		}
+	})
+
+	It("successfully", func() {
+		cli := newFakeClient().withSpace("FIXED_STRING(32)").
+			withSchema(KindTag, "person",
+				Field{Name: "name", Type: "fixed_string(16)"},
+				Field{Name: "age", Type: "int32", IsNullable: true},
+				Field{Name: "location", Type: "geography"},
+				Field{Name: "created", Type: "timestamp", Default: "now()"},
+				Field{Name: "comment", Type: "string", IsNullable: true},
+			).
+			withSchema(KindEdge, "follow",
+				Field{Name: "degree", Type: "double"},
+			)
+		Expect(Validate(cli, "graphName", nodes, edges)).NotTo(HaveOccurred())
+	})
+
+	It("mismatches", func() {
+		cli := newFakeClient().withSpace("INT64").
+			withSchema(KindTag, "person",
+				Field{Name: "name", Type: "int64"},
+				Field{Name: "age", Type: "int64"},
+				Field{Name: "location", Type: "geography(polygon)"},
+				Field{Name: "created", Type: "timestamp"},
+			)
+		nodes = append(nodes, specv3.NewNode("person",
+			specv3.WithNodeID(&specv3.NodeID{Name: "id", Type: specv3.ValueTypeString, Function: new(string)}),
+			specv3.WithNodeProps(&specv3.Prop{Name: "nickname", Type: specv3.ValueTypeString}),
+		))
+		nodes[1].Mode = specbase.UpdateMode
+
+		err := Validate(cli, "graphName", nodes, edges)
+		Expect(err).To(HaveOccurred())
+		Expect(stderrors.Is(err, errors.ErrSchemaMismatch)).To(BeTrue())
+		e, ok := errors.AsImportError(err)
+		Expect(ok).To(BeTrue())
+		Expect(e.GraphName()).To(Equal("graphName"))
+		Expect(e.Messages).To(Equal([]string{
+			"tag person id is STRING in the config, but the vid type of the space is INT64",
+			"tag person prop name is STRING in the config, but int64 in the schema",
+			"tag person prop age is nullable in the config, but NOT NULL in the schema",
+			"tag person prop location is GEOGRAPHY(POINT) in the config, but geography(polygon) in the schema",
+			"tag person prop created is NOT NULL without default value in the schema, but missing in the config",
+			"tag person prop nickname not found in the schema",
+			"edge follow src id is STRING in the config, but the vid type of the space is INT64",
+			"edge follow dst id is STRING in the config, but the vid type of the space is INT64",
+			"edge follow not found: -1005:not existed",
+		}))
+		Expect(cli.statements).To(HaveLen(4))
+	})
+
+	It("delete mode", func() {
+		cli := newFakeClient().withSpace("FIXED_STRING(32)").
+			withSchema(KindEdge, "follow",
+				Field{Name: "since", Type: "date"},
+			)
+		edges[0].Mode = specbase.DeleteMode
+		Expect(Validate(cli, "graphName", nil, edges)).NotTo(HaveOccurred())
+	})
+
+	It("delete vertex mode", func() {
+		cli := newFakeClient().withSpace("FIXED_STRING(32)").
+			withSchema(KindTag, "person",
+				Field{Name: "name", Type: "int64"},
+			)
+		nodes[0].Mode = specbase.DeleteVertexMode
+		Expect(Validate(cli, "graphName", nodes, nil)).NotTo(HaveOccurred())
+	})
+
+	It("upsert mode", func() {
+		cli := newFakeClient().withSpace("FIXED_STRING(32)").
+			withSchema(KindEdge, "follow",
+				Field{Name: "degree", Type: "double"},
+				Field{Name: "since", Type: "date"},
+			)
+		edges[0].Mode = specbase.UpdateMode
+		Expect(Validate(cli, "graphName", nil, edges)).NotTo(HaveOccurred())
+
+		// The vertices and edges are inserted by upsert if not exist.
+		edges[0].Mode = specbase.UpsertMode
+		err := Validate(cli, "graphName", nil, edges)
+		Expect(stderrors.Is(err, errors.ErrSchemaMismatch)).To(BeTrue())
+		e, ok := errors.AsImportError(err)
+		Expect(ok).To(BeTrue())
+		Expect(e.Messages).To(Equal([]string{
+			"edge follow prop since is NOT NULL without default value in the schema, but missing in the config",
+		}))
+	})
+
+	It("mode column", func() {
+		cli := newFakeClient().withSpace("FIXED_STRING(32)").
+			withSchema(KindEdge, "follow",
+				Field{Name: "degree", Type: "double"},
+				Field{Name: "since", Type: "date"},
+			)
+		edges[0].ModeColumn = &specv3.ModeColumn{Modes: map[string]specbase.Mode{
+			"U": specbase.UpdateMode,
+			"D": specbase.DeleteMode,
+		}}
+		Expect(Validate(cli, "graphName", nil, edges)).NotTo(HaveOccurred())
+
+		// The props are checked if any of the modes inserts.
+		edges[0].ModeColumn.Modes["I"] = specbase.InsertMode
+		err := Validate(cli, "graphName", nil, edges)
+		Expect(stderrors.Is(err, errors.ErrSchemaMismatch)).To(BeTrue())
+	})
+
+	It("space not found", func() {
+		cli := newFakeClient()
+		err := Validate(cli, "graphName", nodes, edges)
+		Expect(err).To(HaveOccurred())
+		Expect(stderrors.Is(err, errors.ErrSchemaMismatch)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("space graphName not found"))
+	})
+
+	It("execute failed", func() {
+		cli := newFakeClient()
+		cli.err = stderrors.New("test error")
+		err := Validate(cli, "graphName", nodes, edges)
+		Expect(err).To(HaveOccurred())
+		Expect(stderrors.Is(err, errors.ErrSchemaMismatch)).To(BeFalse())
+		Expect(err.Error()).To(ContainSubstring("test error"))
+	})
+
+	It("unexpected result", func() {
+		cli := newFakeClient()
+		cli.responses["DESCRIBE SPACE `graphName`"] = &fakeResponse{colNames: []string{"Name"}}
+		err := Validate(cli, "graphName", nodes, edges)
+		Expect(err).To(HaveOccurred())
+		Expect(stderrors.Is(err, errors.ErrNoColumn)).To(BeTrue())
+	})
+
+	DescribeTable("isTypeMatched",
+		func(t specv3.ValueType, schemaType string, expected bool) {
+			Expect(isTypeMatched(t, schemaType)).To(Equal(expected))
+		},
+		EntryDescription("%[1]s : %[2]s => %[3]t"),
+		Entry(nil, specv3.ValueTypeBool, "bool", true),
+		Entry(nil, specv3.ValueTypeBool, "int64", false),
+		Entry(nil, specv3.ValueType("int"), "int8", true),
+		Entry(nil, specv3.ValueTypeInt, "double", false),
+		Entry(nil, specv3.ValueTypeString, "FIXED_STRING(8)", true),
+		Entry(nil, specv3.ValueTypeFloat, "double", true),
+		Entry(nil, specv3.ValueTypeDouble, "float", true),
+		Entry(nil, specv3.ValueTypeDate, "date", true),
+		Entry(nil, specv3.ValueTypeTime, "time", true),
+		Entry(nil, specv3.ValueTypeDateTime, "datetime", true),
+		Entry(nil, specv3.ValueTypeTimestamp, "timestamp", true),
+		Entry(nil, specv3.ValueTypeTimestamp, "datetime", false),
+		Entry(nil, specv3.ValueTypeGeo, "geography(linestring)", true),
+		Entry(nil, specv3.ValueTypeGeoLineString, "geography(linestring)", true),
+		Entry(nil, specv3.ValueTypeGeoLineString, "geography(point)", false),
+		Entry(nil, specv3.ValueTypeGeoPolygon, "string", false),
+		Entry(nil, specv3.ValueType("DURATION"), "duration", false),
+	)
+})
@@ -0,0 +1,251 @@
+package source
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"io"
+	"strings"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+	"github.com/ulikunitz/xz"
+)
+
+const (
+	CompressionNone   = "none"
+	CompressionGzip   = "gzip"
+	CompressionZstd   = "zstd"
+	CompressionBzip2  = "bzip2"
+	CompressionXz     = "xz"
+	CompressionLz4    = "lz4"
+	CompressionSnappy = "snappy"
+
+	compressionMagicSize = 10
+)
+
+var (
+	_ Source     = (*decompressSource)(nil)
+	_ Compressed = (*decompressSource)(nil)
+
+	compressionExtensions = map[string]string{
+		".gz":     CompressionGzip,
+		".gzip":   CompressionGzip,
+		".zst":    CompressionZstd,
+		".zstd":   CompressionZstd,
+		".bz2":    CompressionBzip2,
+		".bzip2":  CompressionBzip2,
+		".xz":     CompressionXz,
+		".lz4":    CompressionLz4,
+		".sz":     CompressionSnappy,
+		".snappy": CompressionSnappy,
+	}
+
+	// plainExtensions are not compressed, the magic bytes are not sniffed for them,
+	// since a text line could start with the magic bytes, such as "BZh".
+	plainExtensions = map[string]struct{}{
+		".csv":     {},
+		".json":    {},
+		".jsonl":   {},
+		".parquet": {},
+	}
+
+	compressionMagics = []struct {
+		compression string
+		magic       []byte
+		match       func(magic []byte) bool // overrides the prefix of magic if not nil
+	}{
+		{CompressionGzip, []byte{0x1f, 0x8b}, nil},
+		{CompressionZstd, []byte{0x28, 0xb5, 0x2f, 0xfd}, nil},
+		{CompressionBzip2, []byte("BZh"), isBzip2Magic},
+		{CompressionXz, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}, nil},
+		{CompressionLz4, []byte{0x04, 0x22, 0x4d, 0x18}, nil},
+		{CompressionSnappy, []byte("\xff\x06\x00\x00sNaPpY"), nil},
+	}
+
+	bzip2BlockMagic = []byte{0x31, 0x41, 0x59, 0x26, 0x53, 0x59}
+)
+
+type (
+	// Compressed is implemented by the sources which decompress the data read,
+	// Size returns the compressed size, but Read returns the decompressed data.
+	Compressed interface {
+		// CompressedBytes returns the compressed bytes consumed so far,
+		// and whether the data is compressed or not.
+		CompressedBytes() (int64, bool)
+	}
+
+	decompressSource struct {
+		Source
+		compression string
+		cr          *countReader
+		r           io.Reader
+		closer      io.Closer
+	}
+
+	countReader struct {
+		io.Reader
+		n int64
+	}
+)
+
+// NewDecompressSource wraps the source to decompress the data, the compression is detected when open,
+// by the compression of the config, the extension of the path or the magic bytes in turn.
+func NewDecompressSource(s Source) Source {
+	return &decompressSource{
+		Source: s,
+	}
+}
+
+// TrimCompressionExt returns the path without the compression extension, such as "a.csv.gz" => "a.csv".
+func TrimCompressionExt(path string) string {
+	if i := strings.LastIndexByte(path, '.'); i >= 0 {
+		if _, ok := compressionExtensions[strings.ToLower(path[i:])]; ok {
+			return path[:i]
+		}
+	}
+	return path
+}
+
+func (s *decompressSource) Open() error {
+	if err := s.Source.Open(); err != nil {
+		return err
+	}
+	if err := s.open(); err != nil {
+		_ = s.Source.Close()
+		return err
+	}
+	return nil
+}
+
+func (s *decompressSource) open() error {
+	br := bufio.NewReader(s.Source)
+	s.cr = &countReader{Reader: br}
+
+	compression, err := s.detect(br)
+	if err != nil {
+		return err
+	}
+
+	s.compression = compression
+	switch compression {
+	case CompressionNone:
+		s.r = s.cr
+	case CompressionGzip:
+		gr, err := gzip.NewReader(s.cr)
+		if err != nil {
+			return err
+		}
+		s.r, s.closer = gr, gr
+	case CompressionZstd:
+		zr, err := zstd.NewReader(s.cr)
+		if err != nil {
+			return err
+		}
+		s.r, s.closer = zr, zstdCloser{zr}
+	case CompressionBzip2:
+		s.r = bzip2.NewReader(s.cr)
+	case CompressionXz:
+		xr, err := xz.NewReader(s.cr)
+		if err != nil {
+			return err
+		}
+		s.r = xr
+	case CompressionLz4:
+		s.r = lz4.NewReader(s.cr)
+	case CompressionSnappy:
+		s.r = snappy.NewReader(s.cr)
+	}
+	return nil
+}
+
+func (s *decompressSource) detect(br *bufio.Reader) (string, error) {
+	if c := s.Config(); c != nil && c.Compression != "" {
+		compression := strings.ToLower(c.Compression)
+		if compression == CompressionNone {
+			return compression, nil
+		}
+		for _, m := range compressionMagics {
+			if m.compression == compression {
+				return compression, nil
+			}
+		}
+		return "", errors.NewImportError(errors.ErrUnsupportedCompression, "compression %s", c.Compression)
+	}
+
+	if c := s.Config(); c != nil {
+		path := strings.ToLower(c.Path())
+		if i := strings.LastIndexByte(path, '.'); i >= 0 {
+			if compression, ok := compressionExtensions[path[i:]]; ok {
+				return compression, nil
+			}
+			if _, ok := plainExtensions[path[i:]]; ok {
+				return CompressionNone, nil
+			}
+		}
+	}
+
+	// The error is ignored here, and will be returned on read.
+	magic, _ := br.Peek(compressionMagicSize)
+	for _, m := range compressionMagics {
+		if m.match != nil {
+			if m.match(magic) {
+				return m.compression, nil
+			}
+		} else if bytes.HasPrefix(magic, m.magic) {
+			return m.compression, nil
+		}
+	}
+	return CompressionNone, nil
+}
+
+// isBzip2Magic matches "BZh" with the block size '1'-'9', followed by the magic of the first block.
+func isBzip2Magic(magic []byte) bool {
+	const n = len("BZh1")
+	return len(magic) >= n+len(bzip2BlockMagic) &&
+		bytes.HasPrefix(magic, []byte("BZh")) &&
+		magic[3] >= '1' && magic[3] <= '9' &&
+		bytes.Equal(magic[n:n+len(bzip2BlockMagic)], bzip2BlockMagic)
+}
+
+func (s *decompressSource) CompressedBytes() (int64, bool) {
+	if s.cr == nil {
+		return 0, false
+	}
+	return s.cr.n, s.compression != CompressionNone
+}
+
+func (s *decompressSource) Read(p []byte) (int, error) {
+	if s.r == nil {
+		// Not opened by the wrapper, read the raw data.
+		return s.Source.Read(p)
+	}
+	return s.r.Read(p)
+}
+
+func (s *decompressSource) Close() error {
+	if s.closer != nil {
+		_ = s.closer.Close()
+	}
+	return s.Source.Close()
+}
+
+func (r *countReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// zstdCloser adapts the zstd decoder, whose Close does not return an error.
+type zstdCloser struct {
+	d *zstd.Decoder
+}
+
+func (c zstdCloser) Close() error {
+	c.d.Close()
+	return nil
+}
@@ -0,0 +1,217 @@
+package source
+
+import (
+	stderrors "errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("decompressSource", func() {
+	readAll := func(c *Config) (Source, []byte) {
+		s := NewDecompressSource(newLocalSource(c))
+		Expect(s.Open()).NotTo(HaveOccurred())
+		DeferCleanup(s.Close)
+
+		data, err := io.ReadAll(s)
+		Expect(err).NotTo(HaveOccurred())
+		return s, data
+	}
+
+	DescribeTable("by extension",
+		func(path string) {
+			s, data := readAll(&Config{
+				Local: &LocalConfig{
+					Path: path,
+				},
+			})
+			Expect(string(data)).To(Equal("Hello\n"))
+
+			fi, err := os.Stat(path)
+			Expect(err).NotTo(HaveOccurred())
+			nBytes, err := s.Size()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nBytes).To(Equal(fi.Size()))
+
+			consumed, compressed := s.(Compressed).CompressedBytes()
+			Expect(compressed).To(BeTrue())
+			Expect(consumed).To(Equal(fi.Size()))
+
+			_, ok := ReaderAt(s)
+			Expect(ok).To(BeFalse())
+		},
+		EntryDescription("%s"),
+		Entry(nil, "testdata/local.txt.gz"),
+		Entry(nil, "testdata/local.txt.zst"),
+		Entry(nil, "testdata/local.txt.bz2"),
+		Entry(nil, "testdata/local.txt.xz"),
+		Entry(nil, "testdata/local.txt.lz4"),
+		Entry(nil, "testdata/local.txt.sz"),
+	)
+
+	DescribeTable("by magic bytes",
+		func(path string) {
+			bs, err := os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			tmpPath := filepath.Join(GinkgoT().TempDir(), "local")
+			Expect(os.WriteFile(tmpPath, bs, 0o600)).NotTo(HaveOccurred())
+
+			s, data := readAll(&Config{
+				Local: &LocalConfig{
+					Path: tmpPath,
+				},
+			})
+			Expect(string(data)).To(Equal("Hello\n"))
+			_, compressed := s.(Compressed).CompressedBytes()
+			Expect(compressed).To(BeTrue())
+		},
+		EntryDescription("%s"),
+		Entry(nil, "testdata/local.txt.gz"),
+		Entry(nil, "testdata/local.txt.zst"),
+		Entry(nil, "testdata/local.txt.bz2"),
+		Entry(nil, "testdata/local.txt.xz"),
+		Entry(nil, "testdata/local.txt.lz4"),
+		Entry(nil, "testdata/local.txt.sz"),
+	)
+
+	It("by config", func() {
+		_, data := readAll(&Config{
+			Local: &LocalConfig{
+				Path: "testdata/local.txt.gz",
+			},
+			Compression: "none",
+		})
+		Expect(data).To(HaveLen(26))
+
+		_, data = readAll(&Config{
+			Local: &LocalConfig{
+				Path: "testdata/local.txt.zst",
+			},
+			Compression: "ZSTD",
+		})
+		Expect(string(data)).To(Equal("Hello\n"))
+	})
+
+	It("not compressed", func() {
+		s, data := readAll(&Config{
+			Local: &LocalConfig{
+				Path: "testdata/local.txt",
+			},
+		})
+		Expect(string(data)).To(Equal("Hello\n"))
+
+		consumed, compressed := s.(Compressed).CompressedBytes()
+		Expect(compressed).To(BeFalse())
+		Expect(consumed).To(Equal(int64(6)))
+
+		ra, ok := ReaderAt(s)
+		Expect(ok).To(BeTrue())
+		var buf [4]byte
+		n, err := ra.ReadAt(buf[:], 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(buf[:n])).To(Equal("ello"))
+	})
+
+	It("unsupported compression", func() {
+		src := &closeCountSource{Source: newLocalSource(&Config{
+			Local: &LocalConfig{
+				Path: "testdata/local.txt",
+			},
+			Compression: "rar",
+		})}
+		s := NewDecompressSource(src)
+		err := s.Open()
+		Expect(stderrors.Is(err, errors.ErrUnsupportedCompression)).To(BeTrue())
+		Expect(src.nClose).To(Equal(1))
+	})
+
+	It("plain text starts with the bzip2 magic", func() {
+		tmpPath := filepath.Join(GinkgoT().TempDir(), "local")
+		Expect(os.WriteFile(tmpPath, []byte("BZh,1\n"), 0o600)).NotTo(HaveOccurred())
+
+		s, data := readAll(&Config{
+			Local: &LocalConfig{
+				Path: tmpPath,
+			},
+		})
+		Expect(string(data)).To(Equal("BZh,1\n"))
+		_, compressed := s.(Compressed).CompressedBytes()
+		Expect(compressed).To(BeFalse())
+	})
+
+	DescribeTable("plain extensions are not sniffed",
+		func(ext string) {
+			bs, err := os.ReadFile("testdata/local.txt.gz")
+			Expect(err).NotTo(HaveOccurred())
+			tmpPath := filepath.Join(GinkgoT().TempDir(), "local"+ext)
+			Expect(os.WriteFile(tmpPath, bs, 0o600)).NotTo(HaveOccurred())
+
+			s, data := readAll(&Config{
+				Local: &LocalConfig{
+					Path: tmpPath,
+				},
+			})
+			Expect(data).To(Equal(bs))
+			_, compressed := s.(Compressed).CompressedBytes()
+			Expect(compressed).To(BeFalse())
+		},
+		EntryDescription("%s"),
+		Entry(nil, ".csv"),
+		Entry(nil, ".json"),
+		Entry(nil, ".JSONL"),
+		Entry(nil, ".parquet"),
+	)
+
+	It("open failed", func() {
+		s := NewDecompressSource(newLocalSource(&Config{
+			Local: &LocalConfig{
+				Path: "testdata/not-exists.txt",
+			},
+		}))
+		Expect(s.Open()).To(HaveOccurred())
+	})
+
+	DescribeTable("corrupted data",
+		func(compression string) {
+			src := &closeCountSource{Source: newLocalSource(&Config{
+				Local: &LocalConfig{
+					Path: "testdata/local.txt",
+				},
+				Compression: compression,
+			})}
+			s := NewDecompressSource(src)
+			Expect(s.Open()).To(HaveOccurred())
+			Expect(src.nClose).To(Equal(1))
+		},
+		EntryDescription("%s"),
+		Entry(nil, CompressionGzip),
+		Entry(nil, CompressionXz),
+	)
+})
+
+type closeCountSource struct {
+	Source
+	nClose int
+}
+
+func (s *closeCountSource) Close() error {
+	s.nClose++
+	return s.Source.Close()
+}
+
+var _ = DescribeTable("TrimCompressionExt",
+	func(path, expect string) {
+		Expect(TrimCompressionExt(path)).To(Equal(expect))
+	},
+	EntryDescription("TrimCompressionExt(%[1]q) == %[2]q"),
+	Entry(nil, "a.csv", "a.csv"),
+	Entry(nil, "a.csv.gz", "a.csv"),
+	Entry(nil, "a.csv.ZST", "a.csv"),
+	Entry(nil, "dir.gz/a", "dir.gz/a"),
+	Entry(nil, "a", "a"),
+)
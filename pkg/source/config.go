@@ -1,5 +1,14 @@
 package source
 
+import (
+	"strings"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+)
+
+// JSONEnvelopeDebezium is the envelope of the Debezium change events, such as {"before": ..., "after": ..., "op": "c", "ts_ms": ...}.
+const JSONEnvelopeDebezium = "debezium"
+
 type (
 	Config struct {
 		Local *LocalConfig `yaml:",inline"`
@@ -9,8 +18,13 @@ type (
 		SFTP  *SFTPConfig  `yaml:"sftp,omitempty"`
 		HDFS  *HDFSConfig  `yaml:"hdfs,omitempty"`
 		GCS   *GCSConfig   `yaml:"gcs,omitempty"`
+		// Compression is one of none, gzip, zstd, bzip2, xz, lz4 and snappy,
+		// detected by the extension of the path or the magic bytes if not set.
+		Compression string `yaml:"compression,omitempty"`
 		// The following is format information
-		CSV *CSVConfig `yaml:"csv,omitempty"`
+		CSV     *CSVConfig     `yaml:"csv,omitempty"`
+		JSON    *JSONConfig    `yaml:"json,omitempty"`
+		Parquet *ParquetConfig `yaml:"parquet,omitempty"`
 	}
 
 	CSVConfig struct {
@@ -19,8 +33,31 @@ type (
 		WithHeader bool   `yaml:"withHeader,omitempty"`
 		LazyQuotes bool   `yaml:"lazyQuotes,omitempty"`
 	}
+
+	JSONConfig struct {
+		// Fields is the ordered list of json paths, such as "user.id" or "tags[0]".
+		// The n-th path is the n-th column of the record.
+		Fields []string `yaml:"fields,omitempty"`
+		// Envelope is the envelope of the json lines, only debezium is supported.
+		// The fields are looked up in the row image of the change events, see JSONEnvelopeDebezium.
+		Envelope string `yaml:"envelope,omitempty"`
+	}
+
+	ParquetConfig struct {
+		// Columns is the ordered list of column paths, such as "user.id", all leaf columns by default.
+		// The n-th column is the n-th column of the record.
+		Columns []string `yaml:"columns,omitempty"`
+	}
 )
 
+// Validate checks the envelope of the json lines, only JSONEnvelopeDebezium is supported.
+func (c *JSONConfig) Validate() error {
+	if c.Envelope != "" && !strings.EqualFold(c.Envelope, JSONEnvelopeDebezium) {
+		return errors.NewImportError(errors.ErrUnsupportedEnvelope, "envelope %s", c.Envelope)
+	}
+	return nil
+}
+
 func (c *Config) Clone() *Config {
 	cpy := *c
 	switch {
@@ -48,3 +85,24 @@ func (c *Config) Clone() *Config {
 	}
 	return &cpy
 }
+
+// Path returns the path or the key of the source.
+func (c *Config) Path() string {
+	switch {
+	case c.S3 != nil:
+		return c.S3.Key
+	case c.OSS != nil:
+		return c.OSS.Key
+	case c.FTP != nil:
+		return c.FTP.Path
+	case c.SFTP != nil:
+		return c.SFTP.Path
+	case c.HDFS != nil:
+		return c.HDFS.Path
+	case c.GCS != nil:
+		return c.GCS.Key
+	case c.Local != nil:
+		return c.Local.Path
+	}
+	return ""
+}
@@ -79,4 +79,18 @@ var _ = Describe("Config", func() {
 			Expect(c1.Local.Path).To(Equal("path"))
 		})
 	})
+
+	DescribeTable(".Path",
+		func(c *Config, path string) {
+			Expect(c.Path()).To(Equal(path))
+		},
+		Entry("S3", &Config{S3: &S3Config{Key: "s3.csv"}}, "s3.csv"),
+		Entry("OSS", &Config{OSS: &OSSConfig{Key: "oss.csv"}}, "oss.csv"),
+		Entry("FTP", &Config{FTP: &FTPConfig{Path: "ftp.csv"}}, "ftp.csv"),
+		Entry("SFTP", &Config{SFTP: &SFTPConfig{Path: "sftp.csv"}}, "sftp.csv"),
+		Entry("HDFS", &Config{HDFS: &HDFSConfig{Path: "hdfs.csv"}}, "hdfs.csv"),
+		Entry("GCS", &Config{GCS: &GCSConfig{Key: "gcs.csv"}}, "gcs.csv"),
+		Entry("Local", &Config{Local: &LocalConfig{Path: "local.csv"}}, "local.csv"),
+		Entry("Unset", &Config{}, ""),
+	)
 })
@@ -2,13 +2,15 @@ package source
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 )
 
 var (
-	_ Source  = (*localSource)(nil)
-	_ Globber = (*localSource)(nil)
+	_ Source      = (*localSource)(nil)
+	_ Globber     = (*localSource)(nil)
+	_ io.ReaderAt = (*localSource)(nil)
 )
 
 type (
@@ -72,6 +74,10 @@ func (s *localSource) Read(p []byte) (int, error) {
 	return s.f.Read(p)
 }
 
+func (s *localSource) ReadAt(p []byte, off int64) (int, error) {
+	return s.f.ReadAt(p, off)
+}
+
 func (s *localSource) Close() (err error) {
 	if s.f != nil {
 		err = s.f.Close()
@@ -27,6 +27,11 @@ type (
 )
 
 func New(c *Config) (Source, error) {
+	if c.JSON != nil {
+		if err := c.JSON.Validate(); err != nil {
+			return nil, err
+		}
+	}
 	// TODO: support blob and so on
 	switch {
 	case c.S3 != nil:
@@ -46,3 +51,16 @@ func New(c *Config) (Source, error) {
 	}
 	return nil, errors.ErrUnsetSource
 }
+
+// ReaderAt returns the io.ReaderAt of the source if it supports random access,
+// the decompressed data does not support it.
+func ReaderAt(s Source) (io.ReaderAt, bool) {
+	if ds, ok := s.(*decompressSource); ok {
+		if _, compressed := ds.CompressedBytes(); compressed {
+			return nil, false
+		}
+		s = ds.Source
+	}
+	ra, ok := s.(io.ReaderAt)
+	return ra, ok
+}
@@ -1,6 +1,8 @@
 package source
 
 import (
+	stderrors "errors"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
@@ -26,6 +28,25 @@ var _ = Describe("Source", func() {
 		Expect(s).To(BeAssignableToTypeOf(&s3Source{}))
 	})
 
+	It("unsupported envelope", func() {
+		c := Config{
+			Local: &LocalConfig{
+				Path: "path",
+			},
+			JSON: &JSONConfig{
+				Envelope: "maxwell",
+			},
+		}
+		s, err := New(&c)
+		Expect(stderrors.Is(err, errors.ErrUnsupportedEnvelope)).To(BeTrue())
+		Expect(s).To(BeNil())
+
+		c.JSON.Envelope = "Debezium"
+		s, err = New(&c)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s).To(BeAssignableToTypeOf(&localSource{}))
+	})
+
 	It("OSS", func() {
 		c := Config{
 			OSS: &OSSConfig{
@@ -2,6 +2,8 @@ package specbase
 
 import (
 	"github.com/antonmedv/expr"
+	"github.com/antonmedv/expr/ast"
+	"github.com/antonmedv/expr/parser"
 	"github.com/antonmedv/expr/vm"
 )
 
@@ -32,3 +34,39 @@ func (f *Filter) Filter(record Record) (bool, error) {
 	}
 	return out.(bool), nil
 }
+
+// Indices returns the record indices referenced by the expression.
+// It returns false if they cannot be determined, for example, Record[i] or Record is referenced entirely.
+func (f *Filter) Indices() ([]int, bool) {
+	tree, err := parser.Parse(f.Expr)
+	if err != nil {
+		return nil, false
+	}
+	v := &filterIndicesVisitor{}
+	ast.Walk(&tree.Node, v)
+	if v.nRecord != len(v.indices) {
+		return nil, false
+	}
+	return v.indices, true
+}
+
+type filterIndicesVisitor struct {
+	nRecord int
+	indices []int
+}
+
+func (v *filterIndicesVisitor) Visit(node *ast.Node) {
+	switch n := (*node).(type) {
+	case *ast.IdentifierNode:
+		if n.Value == "Record" {
+			v.nRecord++
+		}
+	case *ast.MemberNode:
+		if ident, ok := n.Node.(*ast.IdentifierNode); !ok || ident.Value != "Record" {
+			return
+		}
+		if index, ok := n.Property.(*ast.IntegerNode); ok {
+			v.indices = append(v.indices, index.Value)
+		}
+	}
+}
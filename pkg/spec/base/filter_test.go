@@ -35,4 +35,20 @@ var _ = Describe("Filter", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ok).To(BeTrue())
 	})
+
+	DescribeTable(".Indices",
+		func(e string, expectIndices []int, expectOK bool) {
+			f := Filter{
+				Expr: e,
+			}
+			indices, ok := f.Indices()
+			Expect(ok).To(Equal(expectOK))
+			Expect(indices).To(Equal(expectIndices))
+		},
+		EntryDescription("%[1]s"),
+		Entry(nil, `Record[0] == "A" or Record[2] != "C"`, []int{0, 2}, true),
+		Entry(nil, `len(Record) > 3`, nil, false),
+		Entry(nil, `Record[len(Record) - 1] == "A"`, nil, false),
+		Entry(nil, `Record[0] ==`, nil, false),
+	)
 })
@@ -7,6 +7,11 @@ const (
 	InsertMode  Mode = "INSERT"
 	UpdateMode  Mode = "UPDATE"
 	DeleteMode  Mode = "DELETE"
+	UpsertMode  Mode = "UPSERT"
+	// DeleteVertexMode deletes the vertices with all their tags and edges, only for the tags.
+	DeleteVertexMode Mode = "DELETE_VERTEX"
+	// DeleteAllRanksMode deletes the edges of all the ranks between the src and dst, only for the edges.
+	DeleteAllRanksMode Mode = "DELETE_ALL_RANKS"
 )
 
 type Mode string
@@ -19,5 +24,11 @@ func (m Mode) Convert() Mode {
 }
 
 func (m Mode) IsSupport() bool {
-	return m == InsertMode || m == UpdateMode || m == DeleteMode
+	return m == InsertMode || m == UpdateMode || m == DeleteMode || m == UpsertMode ||
+		m == DeleteVertexMode || m == DeleteAllRanksMode
+}
+
+// IsDelete returns whether the mode deletes the data, where the props are not written.
+func (m Mode) IsDelete() bool {
+	return m == DeleteMode || m == DeleteVertexMode || m == DeleteAllRanksMode
 }
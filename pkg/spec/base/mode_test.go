@@ -16,9 +16,13 @@ var _ = Describe("Mode", func() {
 		Entry(nil, InsertMode, InsertMode),
 		Entry(nil, UpdateMode, UpdateMode),
 		Entry(nil, DeleteMode, DeleteMode),
+		Entry(nil, UpsertMode, UpsertMode),
 		Entry(nil, Mode("insert"), InsertMode),
 		Entry(nil, Mode("Update"), UpdateMode),
 		Entry(nil, Mode("DELETE"), DeleteMode),
+		Entry(nil, Mode("upsert"), UpsertMode),
+		Entry(nil, Mode("delete_vertex"), DeleteVertexMode),
+		Entry(nil, Mode("Delete_All_Ranks"), DeleteAllRanksMode),
 	)
 	DescribeTable(".Convert",
 		func(m Mode, expect bool) {
@@ -30,6 +34,21 @@ var _ = Describe("Mode", func() {
 		Entry(nil, InsertMode, true),
 		Entry(nil, UpdateMode, true),
 		Entry(nil, DeleteMode, true),
+		Entry(nil, UpsertMode, true),
+		Entry(nil, DeleteVertexMode, true),
+		Entry(nil, DeleteAllRanksMode, true),
 		Entry(nil, Mode("x"), false),
 	)
+	DescribeTable(".IsDelete",
+		func(m Mode, expect bool) {
+			Expect(m.IsDelete()).To(Equal(expect))
+		},
+		EntryDescription("%[1]s => %[2]v"),
+		Entry(nil, InsertMode, false),
+		Entry(nil, UpdateMode, false),
+		Entry(nil, UpsertMode, false),
+		Entry(nil, DeleteMode, true),
+		Entry(nil, DeleteVertexMode, true),
+		Entry(nil, DeleteAllRanksMode, true),
+	)
 })
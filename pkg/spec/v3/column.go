@@ -0,0 +1,46 @@
+package specv3
+
+import (
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+)
+
+const columnKey = "column"
+
+type (
+	// Columns maps the column names to the record indices, it is built from the header of each file.
+	Columns map[string]int
+)
+
+// NewColumns builds the columns from the header, the first one is used if the names are duplicated.
+func NewColumns(header []string) Columns {
+	columns := make(Columns, len(header))
+	for i, column := range header {
+		if _, ok := columns[column]; !ok {
+			columns[column] = i
+		}
+	}
+	return columns
+}
+
+func (c Columns) Index(column string) (int, error) {
+	index, ok := c[column]
+	if !ok {
+		return 0, errors.NewImportError(errors.ErrNoColumn, "column %s not found", column)
+	}
+	return index, nil
+}
+
+// concatItemColumn returns the column name of the concat item, such as `{column: name}`.
+func concatItemColumn(item any) (string, bool) {
+	var v any
+	switch m := item.(type) {
+	case map[string]any:
+		v = m[columnKey]
+	case map[any]any:
+		v = m[columnKey]
+	default:
+		return "", false
+	}
+	column, ok := v.(string)
+	return column, ok && column != ""
+}
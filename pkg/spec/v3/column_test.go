@@ -0,0 +1,93 @@
+package specv3
+
+import (
+	stderrors "errors"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Columns", func() {
+	It("NewColumns", func() {
+		columns := NewColumns([]string{"id", "name", "id"})
+		Expect(columns).To(Equal(Columns{"id": 0, "name": 1}))
+
+		index, err := columns.Index("name")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(index).To(Equal(1))
+
+		_, err = columns.Index("age")
+		Expect(stderrors.Is(err, errors.ErrNoColumn)).To(BeTrue())
+	})
+
+	DescribeTable("concatItemColumn",
+		func(item any, expectColumn string, expectOK bool) {
+			column, ok := concatItemColumn(item)
+			Expect(ok).To(Equal(expectOK))
+			Expect(column).To(Equal(expectColumn))
+		},
+		Entry("index", 1, "", false),
+		Entry("constant", "c", "", false),
+		Entry("column", map[string]any{"column": "id"}, "id", true),
+		Entry("column any key", map[any]any{"column": "id"}, "id", true),
+		Entry("empty column", map[string]any{"column": ""}, "", false),
+		Entry("not column", map[string]any{"index": 1}, "", false),
+	)
+
+	Describe("ResolveColumns", func() {
+		columns := NewColumns([]string{"id", "name", "age", "rank", "nickname"})
+
+		It("Prop", func() {
+			prop := &Prop{Name: "name", Index: 7, Column: "name", Nullable: true, AlternativeIndices: []int{6}, AlternativeColumns: []string{"nickname"}}
+			Expect(prop.HasColumns()).To(BeTrue())
+			resolved, err := prop.ResolveColumns(columns)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved.Index).To(Equal(1))
+			Expect(resolved.AlternativeIndices).To(Equal([]int{6, 4}))
+			Expect(prop.Index).To(Equal(7))
+			Expect(prop.AlternativeIndices).To(Equal([]int{6}))
+
+			Expect((&Prop{Name: "name", Index: 1}).HasColumns()).To(BeFalse())
+
+			_, err = (&Prop{Name: "name", Column: "not-exists"}).ResolveColumns(columns)
+			Expect(stderrors.Is(err, errors.ErrNoColumn)).To(BeTrue())
+			_, err = (&Prop{Name: "name", AlternativeColumns: []string{"not-exists"}}).ResolveColumns(columns)
+			Expect(stderrors.Is(err, errors.ErrNoColumn)).To(BeTrue())
+		})
+
+		It("NodeID", func() {
+			id := &NodeID{Name: "id", ConcatItems: []any{"c", map[string]any{"column": "age"}, 0}}
+			Expect(id.HasColumns()).To(BeTrue())
+			resolved, err := id.ResolveColumns(columns)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved.ConcatItems).To(Equal([]any{"c", 2, 0}))
+			Expect(id.ConcatItems[1]).To(Equal(map[string]any{"column": "age"}))
+
+			resolved, err = (&NodeID{Name: "id", Index: 3, Column: "id"}).ResolveColumns(columns)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved.Index).To(Equal(0))
+
+			Expect((&NodeID{Name: "id", ConcatItems: []any{"c", 0}}).HasColumns()).To(BeFalse())
+
+			_, err = (&NodeID{Name: "id", Column: "not-exists"}).ResolveColumns(columns)
+			Expect(stderrors.Is(err, errors.ErrNoColumn)).To(BeTrue())
+			_, err = (&NodeID{Name: "id", ConcatItems: []any{map[string]any{"column": "not-exists"}}}).ResolveColumns(columns)
+			Expect(stderrors.Is(err, errors.ErrNoColumn)).To(BeTrue())
+		})
+
+		It("Rank", func() {
+			rank := &Rank{Column: "rank"}
+			Expect(rank.HasColumns()).To(BeTrue())
+			resolved, err := rank.ResolveColumns(columns)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved.Index).To(Equal(3))
+
+			Expect((&Rank{}).HasColumns()).To(BeFalse())
+
+			_, err = (&Rank{Column: "not-exists"}).ResolveColumns(columns)
+			Expect(stderrors.Is(err, errors.ErrNoColumn)).To(BeTrue())
+		})
+	})
+})
@@ -23,12 +23,21 @@ type (
 		Filter *specbase.Filter `yaml:"filter,omitempty"`
 
 		Mode specbase.Mode `yaml:"mode,omitempty"`
+		// When is the condition in UPDATE and UPSERT mode, such as "updated_at < {updated_at}",
+		// the placeholders are replaced by the values of the props.
+		When string `yaml:"when,omitempty"`
+		// ModeColumn picks the mode of each record from the column instead of the Mode.
+		ModeColumn *ModeColumn `yaml:"modeColumn,omitempty"`
 
 		fnStatement func(records ...Record) (string, int, error)
 		// "INSERT EDGE name(prop_name, ..., prop_name) VALUES "
-		// "UPDATE EDGE ON name "
+		// "UPDATE|UPSERT EDGE ON name "
 		// "DELETE EDGE name "
+		// " OVER name WHERE id($$) == " for the edges of all the ranks
 		statementPrefix string
+		whenTemplate    *template
+		// the statement functions of the modes of the ModeColumn
+		modeStatements map[specbase.Mode]modeStatementFunc
 	}
 
 	EdgeNodeRef struct {
@@ -92,6 +101,18 @@ func WithEdgeMode(m specbase.Mode) EdgeOption {
 	}
 }
 
+func WithEdgeWhen(when string) EdgeOption {
+	return func(e *Edge) {
+		e.When = when
+	}
+}
+
+func WithEdgeModeColumn(c *ModeColumn) EdgeOption {
+	return func(e *Edge) {
+		e.ModeColumn = c
+	}
+}
+
 func (e *Edge) Options(opts ...EdgeOption) *Edge {
 	for _, opt := range opts {
 		opt(e)
@@ -138,9 +159,26 @@ func (e *Edge) Complete() {
 	case specbase.UpdateMode:
 		e.fnStatement = e.updateStatement
 		e.statementPrefix = fmt.Sprintf("UPDATE EDGE ON %s ", utils.ConvertIdentifier(e.Name))
+	case specbase.UpsertMode:
+		e.fnStatement = e.updateStatement
+		e.statementPrefix = fmt.Sprintf("UPSERT EDGE ON %s ", utils.ConvertIdentifier(e.Name))
 	case specbase.DeleteMode:
 		e.fnStatement = e.deleteStatement
 		e.statementPrefix = fmt.Sprintf("DELETE EDGE %s ", utils.ConvertIdentifier(e.Name))
+	case specbase.DeleteAllRanksMode:
+		// It's the same as DELETE mode if the rank is configured.
+		if e.Rank != nil {
+			e.fnStatement = e.deleteStatement
+			e.statementPrefix = fmt.Sprintf("DELETE EDGE %s ", utils.ConvertIdentifier(e.Name))
+			break
+		}
+		e.fnStatement = e.deleteAllRanksStatement
+		e.statementPrefix = fmt.Sprintf(" OVER %s WHERE id($$) == ", utils.ConvertIdentifier(e.Name))
+	}
+
+	if e.ModeColumn != nil {
+		e.ModeColumn.Complete()
+		e.fnStatement = e.modeColumnStatement
 	}
 }
 
@@ -181,17 +219,125 @@ func (e *Edge) Validate() error {
 		}
 	}
 
-	if !e.Mode.IsSupport() {
-		return e.importError(errors.ErrUnsupportedMode)
+	if e.ModeColumn != nil {
+		if err := e.ModeColumn.Validate(); err != nil {
+			return e.importError(err)
+		}
 	}
+	modes := e.Modes()
 
-	if e.Mode == specbase.UpdateMode && len(e.Props) == 0 {
-		return e.importError(errors.ErrNoProps)
+	hasUpdate := false
+	for _, mode := range modes {
+		if !mode.IsSupport() || mode == specbase.DeleteVertexMode {
+			return e.importError(errors.ErrUnsupportedMode)
+		}
+		if mode == specbase.UpdateMode || mode == specbase.UpsertMode {
+			if len(e.Props) == 0 {
+				return e.importError(errors.ErrNoProps)
+			}
+			hasUpdate = true
+		}
+	}
+
+	if (e.When != "" || e.Props.HasSet()) && !hasUpdate {
+		return e.importError(errors.ErrUnsupportedMode, "when and set are only supported in UPDATE and UPSERT mode")
+	}
+
+	if e.When != "" {
+		t, err := e.Props.parseTemplate(e.When)
+		if err != nil {
+			return e.importError(err, "when %s", e.When)
+		}
+		e.whenTemplate = t
+	}
+
+	if e.ModeColumn != nil {
+		e.modeStatements = make(map[specbase.Mode]modeStatementFunc, len(modes))
+		for _, mode := range modes {
+			cpy := *e
+			cpy.Filter, cpy.ModeColumn, cpy.Mode = nil, nil, mode
+			cpy.Complete()
+			e.modeStatements[mode] = cpy.fnStatement
+		}
 	}
 
 	return nil
 }
 
+// Modes returns the modes of the edge, they are the modes of the ModeColumn if it's set.
+func (e *Edge) Modes() []specbase.Mode {
+	if e.ModeColumn != nil {
+		return e.ModeColumn.ModeList()
+	}
+	return []specbase.Mode{e.Mode.Convert()}
+}
+
+// Indices returns the record indices referenced by the edge.
+// It returns false if they cannot be determined, see Filter.Indices for details.
+func (e *Edge) Indices() ([]int, bool) {
+	var indices []int
+	if e.Filter != nil {
+		filterIndices, ok := e.Filter.Indices()
+		if !ok {
+			return nil, false
+		}
+		indices = append(indices, filterIndices...)
+	}
+	if e.Src != nil && e.Src.ID != nil {
+		indices = append(indices, e.Src.ID.Indices()...)
+	}
+	if e.Dst != nil && e.Dst.ID != nil {
+		indices = append(indices, e.Dst.ID.Indices()...)
+	}
+	if e.Rank != nil {
+		indices = append(indices, e.Rank.Indices()...)
+	}
+	if e.ModeColumn != nil {
+		indices = append(indices, e.ModeColumn.Indices()...)
+	}
+	indices = append(indices, e.Props.Indices()...)
+	return indices, true
+}
+
+// HasColumns returns whether the edge refers to the columns by names.
+func (e *Edge) HasColumns() bool {
+	return (e.Src != nil && e.Src.HasColumns()) ||
+		(e.Dst != nil && e.Dst.HasColumns()) ||
+		(e.Rank != nil && e.Rank.HasColumns()) ||
+		(e.ModeColumn != nil && e.ModeColumn.HasColumns()) ||
+		e.Props.HasColumns()
+}
+
+// ResolveColumns returns a copy of the edge whose column names are resolved to the record indices.
+func (e *Edge) ResolveColumns(columns Columns) (*Edge, error) {
+	var err error
+	cpy := *e
+	if e.Src != nil {
+		if cpy.Src, err = e.Src.ResolveColumns(columns); err != nil {
+			return nil, e.importError(err)
+		}
+	}
+	if e.Dst != nil {
+		if cpy.Dst, err = e.Dst.ResolveColumns(columns); err != nil {
+			return nil, e.importError(err)
+		}
+	}
+	if e.Rank != nil {
+		if cpy.Rank, err = e.Rank.ResolveColumns(columns); err != nil {
+			return nil, e.importError(err)
+		}
+	}
+	if e.ModeColumn != nil {
+		if cpy.ModeColumn, err = e.ModeColumn.ResolveColumns(columns); err != nil {
+			return nil, e.importError(err)
+		}
+	}
+	if cpy.Props, err = e.Props.ResolveColumns(columns); err != nil {
+		return nil, e.importError(err)
+	}
+	return &cpy, nil
+}
+
 func (e *Edge) Statement(records ...Record) (statement string, nRecord int, err error) {
 	return e.fnStatement(records...)
 }
@@ -288,19 +434,23 @@ func (e *Edge) updateStatement(records ...Record) (statement string, nRecord int
 			}
 			rankValueStatement = "@" + rankValue
 		}
-		propsSetValueList, err := e.Props.SetValueList(record)
+		propsValueList, err := e.Props.ValueList(record)
 		if err != nil {
 			return "", 0, e.importError(err)
 		}
 
-		// "UPDATE EDGE ON name "src"->"dst"@rank SET prop_name1 = prop_value1, prop_name1 = prop_value1, ...;"
+		// "UPDATE|UPSERT EDGE ON name "src"->"dst"@rank SET prop_name1 = prop_value1, prop_name1 = prop_value1, ... [WHEN condition];"
 		_, _ = buff.WriteString(e.statementPrefix)
 		_, _ = buff.WriteString(srcIDValue)
 		_, _ = buff.WriteString("->")
 		_, _ = buff.WriteString(dstIDValue)
 		_, _ = buff.WriteString(rankValueStatement)
 		_, _ = buff.WriteString(" SET ")
-		_, _ = buff.WriteStringSlice(propsSetValueList, ", ")
+		_, _ = buff.WriteStringSlice(e.Props.setValueList(propsValueList), ", ")
+		if e.whenTemplate != nil {
+			_, _ = buff.WriteString(" WHEN ")
+			_, _ = buff.WriteString(e.whenTemplate.Render(propsValueList))
+		}
 		_, _ = buff.WriteString(";")
 
 		nRecord++
@@ -362,6 +512,69 @@ func (e *Edge) deleteStatement(records ...Record) (statement string, nRecord int
 	return buff.String(), nRecord, nil
 }
 
+func (e *Edge) deleteAllRanksStatement(records ...Record) (statement string, nRecord int, err error) {
+	buff := bytebufferpool.Get()
+	defer bytebufferpool.Put(buff)
+
+	name := utils.ConvertIdentifier(e.Name)
+
+	for _, record := range records {
+		if e.Filter != nil {
+			ok, err := e.Filter.Filter(record)
+			if err != nil {
+				return "", 0, e.importError(err)
+			}
+			if !ok { // skipping those return false by Filter
+				continue
+			}
+		}
+		srcIDValue, err := e.Src.IDValue(record)
+		if err != nil {
+			return "", 0, e.importError(err)
+		}
+		dstIDValue, err := e.Dst.IDValue(record)
+		if err != nil {
+			return "", 0, e.importError(err)
+		}
+
+		// "GO FROM src OVER name WHERE id($$) == dst YIELD src(edge) AS src, dst(edge) AS dst, rank(edge) AS rank
+		//  | DELETE EDGE name $-.src -> $-.dst @ $-.rank;"
+		_, _ = buff.WriteString("GO FROM ")
+		_, _ = buff.WriteString(srcIDValue)
+		_, _ = buff.WriteString(e.statementPrefix)
+		_, _ = buff.WriteString(dstIDValue)
+		_, _ = buff.WriteString(" YIELD src(edge) AS src, dst(edge) AS dst, rank(edge) AS rank | DELETE EDGE ")
+		_, _ = buff.WriteString(name)
+		_, _ = buff.WriteString(" $-.src -> $-.dst @ $-.rank;")
+
+		nRecord++
+	}
+
+	return buff.String(), nRecord, nil
+}
+
+// modeColumnStatement groups the records by the modes of the ModeColumn, the records between
+// the same src and dst are kept in order, whatever their ranks are.
+func (e *Edge) modeColumnStatement(records ...Record) (statement string, nRecord int, err error) {
+	statement, nRecord, err = e.ModeColumn.statement(records, e.Filter, e.key, e.modeStatements)
+	if err != nil {
+		return "", 0, e.importError(err)
+	}
+	return statement, nRecord, nil
+}
+
+func (e *Edge) key(record Record) (string, error) {
+	srcIDValue, err := e.Src.IDValue(record)
+	if err != nil {
+		return "", err
+	}
+	dstIDValue, err := e.Dst.IDValue(record)
+	if err != nil {
+		return "", err
+	}
+	return srcIDValue + "->" + dstIDValue, nil
+}
+
 func (e *Edge) importError(err error, formatWithArgs ...any) *errors.ImportError {
 	return errors.AsOrNewImportError(err, formatWithArgs...).SetEdgeName(e.Name)
 }
@@ -390,6 +603,22 @@ func (n *EdgeNodeRef) IDValue(record Record) (string, error) {
 	return n.ID.Value(record)
 }
 
+func (n *EdgeNodeRef) HasColumns() bool {
+	return n.ID != nil && n.ID.HasColumns()
+}
+
+func (n *EdgeNodeRef) ResolveColumns(columns Columns) (*EdgeNodeRef, error) {
+	cpy := *n
+	if n.ID != nil {
+		id, err := n.ID.ResolveColumns(columns)
+		if err != nil {
+			return nil, n.importError(err)
+		}
+		cpy.ID = id
+	}
+	return &cpy, nil
+}
+
 func (n *EdgeNodeRef) importError(err error, formatWithArgs ...any) *errors.ImportError {
 	return errors.AsOrNewImportError(err, formatWithArgs...).SetNodeName(n.Name)
 }
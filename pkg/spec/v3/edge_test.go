@@ -53,6 +53,79 @@ var _ = Describe("Edge", func() {
 		})
 	})
 
+	Describe(".Indices", func() {
+		It("successfully", func() {
+			edge := NewEdge(
+				"name",
+				WithEdgeSrc(&EdgeNodeRef{ID: &NodeID{Index: 0}}),
+				WithEdgeDst(&EdgeNodeRef{ID: &NodeID{Index: 1}}),
+				WithRank(&Rank{Index: 2}),
+				WithEdgeProps(&Prop{Name: "prop", Index: 3}),
+				WithEdgeFilter(&specbase.Filter{Expr: `Record[4] == "A"`}),
+			)
+			indices, ok := edge.Indices()
+			Expect(ok).To(BeTrue())
+			Expect(indices).To(ConsistOf(0, 1, 2, 3, 4))
+		})
+
+		It("undetermined filter", func() {
+			edge := NewEdge(
+				"name",
+				WithEdgeFilter(&specbase.Filter{Expr: `len(Record) > 1`}),
+			)
+			indices, ok := edge.Indices()
+			Expect(ok).To(BeFalse())
+			Expect(indices).To(BeNil())
+		})
+	})
+
+	Describe(".ResolveColumns", func() {
+		columns := NewColumns([]string{"rank", "dst", "src", "weight"})
+
+		It("successfully", func() {
+			edge := NewEdge(
+				"name",
+				WithEdgeSrc(&EdgeNodeRef{ID: &NodeID{Column: "src"}}),
+				WithEdgeDst(&EdgeNodeRef{ID: &NodeID{Column: "dst"}}),
+				WithRank(&Rank{Column: "rank"}),
+				WithEdgeProps(&Prop{Name: "weight", Column: "weight"}),
+			)
+			Expect(edge.HasColumns()).To(BeTrue())
+			resolved, err := edge.ResolveColumns(columns)
+			Expect(err).NotTo(HaveOccurred())
+			indices, ok := resolved.Indices()
+			Expect(ok).To(BeTrue())
+			Expect(indices).To(Equal([]int{2, 1, 0, 3}))
+
+			// not changed
+			Expect(edge.Src.ID.Index).To(Equal(0))
+			Expect(edge.Rank.Index).To(Equal(0))
+		})
+
+		It("no columns", func() {
+			edge := NewEdge(
+				"name",
+				WithEdgeSrc(&EdgeNodeRef{ID: &NodeID{Index: 0}}),
+				WithEdgeDst(&EdgeNodeRef{ID: &NodeID{Index: 1}}),
+			)
+			Expect(edge.HasColumns()).To(BeFalse())
+		})
+
+		DescribeTable("failed",
+			func(edge *Edge) {
+				_, err := edge.ResolveColumns(columns)
+				Expect(stderrors.Is(err, errors.ErrNoColumn)).To(BeTrue())
+				e, ok := errors.AsImportError(err)
+				Expect(ok).To(BeTrue())
+				Expect(e.EdgeName()).To(Equal("name"))
+			},
+			Entry("src", NewEdge("name", WithEdgeSrc(&EdgeNodeRef{ID: &NodeID{Column: "x"}}))),
+			Entry("dst", NewEdge("name", WithEdgeDst(&EdgeNodeRef{ID: &NodeID{Column: "x"}}))),
+			Entry("rank", NewEdge("name", WithRank(&Rank{Column: "x"}))),
+			Entry("props", NewEdge("name", WithEdgeProps(&Prop{Name: "p", Column: "x"}))),
+		)
+	})
+
 	Describe(".Validate", func() {
 		It("no name", func() {
 			edge := NewEdge("")
@@ -1189,6 +1262,252 @@ var _ = Describe("Edge", func() {
 			})
 		})
 
+		When("UPSERT", func() {
+			It("successfully", func() {
+				edge := NewEdge(
+					"name",
+					WithEdgeSrc(&EdgeNodeRef{
+						Name: "srcNodeName",
+						ID: &NodeID{
+							Name:  "id",
+							Type:  ValueTypeInt,
+							Index: 0,
+						},
+					}),
+					WithEdgeDst(&EdgeNodeRef{
+						Name: "dstNodeName",
+						ID: &NodeID{
+							Name:  "id",
+							Type:  ValueTypeString,
+							Index: 1,
+						},
+					}),
+					WithRank(&Rank{
+						Index: 0,
+					}),
+					WithEdgeProps(
+						&Prop{Name: "prop1", Type: ValueTypeString, Index: 2},
+					),
+					WithEdgeFilter(&specbase.Filter{
+						Expr: `Record[0] != "2"`,
+					}),
+					WithEdgeMode(specbase.UpsertMode),
+				)
+				edge.Complete()
+				err := edge.Validate()
+				Expect(err).NotTo(HaveOccurred())
+
+				statement, nRecord, err := edge.Statement([]string{"1", "id1", "str1"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nRecord).To(Equal(1))
+				Expect(statement).To(Equal("UPSERT EDGE ON `name` 1->\"id1\"@1 SET `prop1` = \"str1\";"))
+
+				statement, nRecord, err = edge.Statement([]string{"1", "id1", "str1"}, []string{"2", "id2", "str2"}, []string{"3", "id3", "str3"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nRecord).To(Equal(2))
+				Expect(statement).To(Equal("UPSERT EDGE ON `name` 1->\"id1\"@1 SET `prop1` = \"str1\";UPSERT EDGE ON `name` 3->\"id3\"@3 SET `prop1` = \"str3\";"))
+
+				statement, nRecord, err = edge.Statement([]string{"1", "id1"})
+				Expect(err).To(HaveOccurred())
+				Expect(stderrors.Is(err, errors.ErrNoRecord)).To(BeTrue())
+				Expect(nRecord).To(Equal(0))
+				Expect(statement).To(BeEmpty())
+			})
+
+			It("validate no props failed", func() {
+				edge := NewEdge(
+					"name",
+					WithEdgeSrc(&EdgeNodeRef{Name: "srcNodeName", ID: &NodeID{Name: "id", Type: ValueTypeInt}}),
+					WithEdgeDst(&EdgeNodeRef{Name: "dstNodeName", ID: &NodeID{Name: "id", Type: ValueTypeString}}),
+					WithEdgeMode(specbase.UpsertMode),
+				)
+				err := edge.Validate()
+				Expect(err).To(HaveOccurred())
+				Expect(stderrors.Is(err, errors.ErrNoProps)).To(BeTrue())
+			})
+		})
+
+		When("mode column", func() {
+			newEdge := func(opts ...EdgeOption) *Edge {
+				return NewEdge(
+					"name",
+					append([]EdgeOption{
+						WithEdgeSrc(&EdgeNodeRef{Name: "srcNodeName", ID: &NodeID{Name: "id", Type: ValueTypeInt, Index: 0}}),
+						WithEdgeDst(&EdgeNodeRef{Name: "dstNodeName", ID: &NodeID{Name: "id", Type: ValueTypeInt, Index: 1}}),
+						WithEdgeProps(&Prop{Name: "p", Type: ValueTypeString, Index: 2}),
+						WithEdgeModeColumn(&ModeColumn{Index: 3, Modes: map[string]specbase.Mode{
+							"U": specbase.UpsertMode,
+							"D": specbase.DeleteAllRanksMode,
+						}}),
+					}, opts...)...,
+				)
+			}
+
+			It("successfully", func() {
+				edge := newEdge(WithEdgeWhen("p < {p}"))
+				edge.Complete()
+				Expect(edge.Validate()).NotTo(HaveOccurred())
+				Expect(edge.Modes()).To(Equal([]specbase.Mode{specbase.DeleteAllRanksMode, specbase.UpsertMode}))
+
+				deleteStatement := func(src, dst string) string {
+					return "GO FROM " + src + " OVER `name` WHERE id($$) == " + dst +
+						" YIELD src(edge) AS src, dst(edge) AS dst, rank(edge) AS rank | DELETE EDGE `name` $-.src -> $-.dst @ $-.rank;"
+				}
+				statement, nRecord, err := edge.Statement(
+					[]string{"1", "2", "a", "U"},
+					[]string{"1", "3", "b", "D"},
+					[]string{"1", "2", "c", "D"},
+					[]string{"1", "2", "d", "U"},
+				)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nRecord).To(Equal(4))
+				Expect(statement).To(Equal("UPSERT EDGE ON `name` 1->2 SET `p` = \"a\" WHEN p < \"a\";" +
+					deleteStatement("1", "3") + deleteStatement("1", "2") +
+					"UPSERT EDGE ON `name` 1->2 SET `p` = \"d\" WHEN p < \"d\";"))
+
+				statement, nRecord, err = edge.Statement([]string{"1"})
+				Expect(stderrors.Is(err, errors.ErrNoRecord)).To(BeTrue())
+				Expect(nRecord).To(Equal(0))
+				Expect(statement).To(BeEmpty())
+			})
+
+			It("indices and columns", func() {
+				edge := newEdge()
+				indices, ok := edge.Indices()
+				Expect(ok).To(BeTrue())
+				Expect(indices).To(ConsistOf(0, 1, 2, 3))
+				Expect(edge.HasColumns()).To(BeFalse())
+
+				edge.ModeColumn.Column = "op"
+				Expect(edge.HasColumns()).To(BeTrue())
+				resolved, err := edge.ResolveColumns(Columns{"op": 4})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resolved.ModeColumn.Index).To(Equal(4))
+
+				_, err = edge.ResolveColumns(Columns{})
+				Expect(stderrors.Is(err, errors.ErrNoColumn)).To(BeTrue())
+			})
+
+			It("unsupported tag mode", func() {
+				edge := newEdge(WithEdgeModeColumn(&ModeColumn{Modes: map[string]specbase.Mode{"D": specbase.DeleteVertexMode}}))
+				edge.Complete()
+				Expect(stderrors.Is(edge.Validate(), errors.ErrUnsupportedMode)).To(BeTrue())
+			})
+		})
+
+		When("set and when", func() {
+			It("successfully", func() {
+				edge := NewEdge(
+					"name",
+					WithEdgeSrc(&EdgeNodeRef{Name: "srcNodeName", ID: &NodeID{Name: "id", Type: ValueTypeInt, Index: 0}}),
+					WithEdgeDst(&EdgeNodeRef{Name: "dstNodeName", ID: &NodeID{Name: "id", Type: ValueTypeString, Index: 1}}),
+					WithEdgeProps(
+						&Prop{Name: "count", Type: ValueTypeInt, Index: 2, Set: "count + {value}"},
+						&Prop{Name: "last_seen", Type: ValueTypeInt, Index: 3, Set: "max(last_seen, {value})"},
+					),
+					WithEdgeWhen("{count} > 0"),
+					WithEdgeMode(specbase.UpdateMode),
+				)
+				edge.Complete()
+				Expect(edge.Validate()).NotTo(HaveOccurred())
+
+				statement, nRecord, err := edge.Statement([]string{"1", "id1", "2", "100"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nRecord).To(Equal(1))
+				Expect(statement).To(Equal("UPDATE EDGE ON `name` 1->\"id1\" SET `count` = count + 2, `last_seen` = max(last_seen, 100) WHEN 2 > 0;"))
+			})
+
+			It("unsupported mode", func() {
+				edge := NewEdge(
+					"name",
+					WithEdgeSrc(&EdgeNodeRef{Name: "srcNodeName", ID: &NodeID{Name: "id", Type: ValueTypeInt, Index: 0}}),
+					WithEdgeDst(&EdgeNodeRef{Name: "dstNodeName", ID: &NodeID{Name: "id", Type: ValueTypeString, Index: 1}}),
+					WithEdgeProps(&Prop{Name: "count", Type: ValueTypeInt, Index: 2, Set: "count + {value}"}),
+				)
+				edge.Complete()
+				err := edge.Validate()
+				Expect(stderrors.Is(err, errors.ErrUnsupportedMode)).To(BeTrue())
+			})
+
+			It("unknown placeholder", func() {
+				edge := NewEdge(
+					"name",
+					WithEdgeSrc(&EdgeNodeRef{Name: "srcNodeName", ID: &NodeID{Name: "id", Type: ValueTypeInt, Index: 0}}),
+					WithEdgeDst(&EdgeNodeRef{Name: "dstNodeName", ID: &NodeID{Name: "id", Type: ValueTypeString, Index: 1}}),
+					WithEdgeProps(&Prop{Name: "count", Type: ValueTypeInt, Index: 2}),
+					WithEdgeWhen("{value} > 0"),
+					WithEdgeMode(specbase.UpsertMode),
+				)
+				edge.Complete()
+				err := edge.Validate()
+				Expect(stderrors.Is(err, errors.ErrInvalidTemplate)).To(BeTrue())
+			})
+		})
+
+		When("DELETE_ALL_RANKS", func() {
+			newEdge := func(opts ...EdgeOption) *Edge {
+				return NewEdge(
+					"name",
+					append([]EdgeOption{
+						WithEdgeSrc(&EdgeNodeRef{Name: "srcNodeName", ID: &NodeID{Name: "id", Type: ValueTypeInt, Index: 0}}),
+						WithEdgeDst(&EdgeNodeRef{Name: "dstNodeName", ID: &NodeID{Name: "id", Type: ValueTypeString, Index: 1}}),
+						WithEdgeMode(specbase.DeleteAllRanksMode),
+					}, opts...)...,
+				)
+			}
+
+			It("successfully", func() {
+				edge := newEdge(WithEdgeFilter(&specbase.Filter{
+					Expr: `Record[1] != "A"`,
+				}))
+				edge.Complete()
+				Expect(edge.Validate()).NotTo(HaveOccurred())
+
+				statement, nRecord, err := edge.Statement([]string{"1", "B"}, []string{"2", "A"}, []string{"3", "C"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nRecord).To(Equal(2))
+				Expect(statement).To(Equal("GO FROM 1 OVER `name` WHERE id($$) == \"B\" YIELD src(edge) AS src, dst(edge) AS dst, rank(edge) AS rank" +
+					" | DELETE EDGE `name` $-.src -> $-.dst @ $-.rank;" +
+					"GO FROM 3 OVER `name` WHERE id($$) == \"C\" YIELD src(edge) AS src, dst(edge) AS dst, rank(edge) AS rank" +
+					" | DELETE EDGE `name` $-.src -> $-.dst @ $-.rank;"))
+
+				// filter failed
+				statement, nRecord, err = edge.Statement([]string{"1"})
+				Expect(err).To(HaveOccurred())
+				Expect(nRecord).To(Equal(0))
+				Expect(statement).To(BeEmpty())
+			})
+
+			It("failed dst no record", func() {
+				edge := newEdge()
+				edge.Complete()
+				Expect(edge.Validate()).NotTo(HaveOccurred())
+
+				statement, nRecord, err := edge.Statement([]string{"1"})
+				Expect(stderrors.Is(err, errors.ErrNoRecord)).To(BeTrue())
+				Expect(nRecord).To(Equal(0))
+				Expect(statement).To(BeEmpty())
+			})
+
+			It("with rank", func() {
+				edge := newEdge(WithRank(&Rank{Index: 0}))
+				edge.Complete()
+				Expect(edge.Validate()).NotTo(HaveOccurred())
+
+				statement, nRecord, err := edge.Statement([]string{"1", "B"}, []string{"2", "C"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nRecord).To(Equal(2))
+				Expect(statement).To(Equal("DELETE EDGE `name` 1->\"B\"@1, 2->\"C\"@2"))
+			})
+
+			It("unsupported tag mode", func() {
+				edge := newEdge(WithEdgeMode(specbase.DeleteVertexMode))
+				edge.Complete()
+				err := edge.Validate()
+				Expect(stderrors.Is(err, errors.ErrUnsupportedMode)).To(BeTrue())
+			})
+		})
+
 		When("DELETE", func() {
 			When("no props", func() {
 				var edge *Edge
@@ -0,0 +1,189 @@
+package specv3
+
+import (
+	"sort"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/bytebufferpool"
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+	specbase "github.com/vesoft-inc/nebula-importer/v4/pkg/spec/base"
+)
+
+type (
+	// ModeColumn picks the mode of each record from the column, such as the operation column of the change data capture.
+	ModeColumn struct {
+		Index  int    `yaml:"index"`
+		Column string `yaml:"column,omitempty"` // the column name in the header, override the Index
+		// Modes maps the values of the column to the modes, such as {I: INSERT, U: UPDATE, D: DELETE}.
+		Modes map[string]specbase.Mode `yaml:"modes"`
+	}
+
+	modeStatementFunc func(records ...Record) (string, int, error)
+
+	// modeGroup is the records of a batch in the same mode.
+	modeGroup struct {
+		mode    specbase.Mode
+		records []Record
+	}
+)
+
+func (c *ModeColumn) Complete() {
+	for value, mode := range c.Modes {
+		c.Modes[value] = mode.Convert()
+	}
+}
+
+func (c *ModeColumn) Validate() error {
+	if c.Index < 0 {
+		return c.importError(errors.ErrInvalidIndex, "mode column index %d", c.Index)
+	}
+	if len(c.Modes) == 0 {
+		return c.importError(errors.ErrUnsupportedMode, "no modes of the mode column")
+	}
+	for value, mode := range c.Modes {
+		if !mode.IsSupport() {
+			return c.importError(errors.ErrUnsupportedMode, "unsupported mode %s of value %s", mode, value)
+		}
+	}
+	return nil
+}
+
+// Mode returns the mode of the record by the value of the column.
+func (c *ModeColumn) Mode(record Record) (specbase.Mode, error) {
+	if c.Index >= len(record) {
+		return "", c.importError(errors.ErrNoRecord, "record index %d pick failed", c.Index).SetRecord(record)
+	}
+	mode, ok := c.Modes[record[c.Index]]
+	if !ok {
+		return "", c.importError(errors.ErrUnsupportedMode, "unknown mode value %s", record[c.Index]).SetRecord(record)
+	}
+	return mode, nil
+}
+
+// ModeList returns the distinct modes in order.
+func (c *ModeColumn) ModeList() []specbase.Mode {
+	modes := make([]specbase.Mode, 0, len(c.Modes))
+	seen := make(map[specbase.Mode]struct{}, len(c.Modes))
+	for _, mode := range c.Modes {
+		if _, ok := seen[mode]; !ok {
+			seen[mode] = struct{}{}
+			modes = append(modes, mode)
+		}
+	}
+	sort.Slice(modes, func(i, j int) bool { return modes[i] < modes[j] })
+	return modes
+}
+
+// Indices returns the record indices referenced by the mode column.
+func (c *ModeColumn) Indices() []int {
+	return []int{c.Index}
+}
+
+// HasColumns returns whether the mode column refers to the column by name.
+func (c *ModeColumn) HasColumns() bool {
+	return c.Column != ""
+}
+
+// ResolveColumns returns a copy of the mode column whose column name is resolved to the record index.
+func (c *ModeColumn) ResolveColumns(columns Columns) (*ModeColumn, error) {
+	cpy := *c
+	if c.Column != "" {
+		index, err := columns.Index(c.Column)
+		if err != nil {
+			return nil, c.importError(err)
+		}
+		cpy.Index = index
+	}
+	return &cpy, nil
+}
+
+// statement groups the records by their modes, and joins the statements of the groups.
+// The groups are in the order of their first records, and a new round of groups is started
+// once a record would run before the previous record of the same key, so that the records
+// of each key are applied in order.
+func (c *ModeColumn) statement(
+	records []Record,
+	filter *specbase.Filter,
+	fnKey func(Record) (string, error),
+	fnStatements map[specbase.Mode]modeStatementFunc,
+) (statement string, nRecord int, err error) {
+	buff := bytebufferpool.Get()
+	defer bytebufferpool.Put(buff)
+
+	var (
+		groups    []modeGroup
+		keyGroups = map[string]int{} // the key to the index of the group of its last record
+	)
+
+	flush := func() error {
+		for _, g := range groups {
+			s, n, err := fnStatements[g.mode](g.records...)
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				continue
+			}
+			if b := buff.Bytes(); len(b) > 0 && b[len(b)-1] != ';' {
+				_, _ = buff.WriteString(";")
+			}
+			_, _ = buff.WriteString(s)
+			nRecord += n
+		}
+		groups = groups[:0]
+		keyGroups = map[string]int{}
+		return nil
+	}
+
+	for _, record := range records {
+		if filter != nil {
+			ok, err := filter.Filter(record)
+			if err != nil {
+				return "", 0, err
+			}
+			if !ok { // skipping those return false by Filter
+				continue
+			}
+		}
+		mode, err := c.Mode(record)
+		if err != nil {
+			return "", 0, err
+		}
+		key, err := fnKey(record)
+		if err != nil {
+			return "", 0, err
+		}
+
+		index := len(groups)
+		for i := range groups {
+			if groups[i].mode == mode {
+				index = i
+				break
+			}
+		}
+		if last, ok := keyGroups[key]; ok && last > index {
+			if err = flush(); err != nil {
+				return "", 0, err
+			}
+			index = 0
+		}
+		if index == len(groups) {
+			groups = append(groups, modeGroup{mode: mode})
+		}
+		groups[index].records = append(groups[index].records, record)
+		keyGroups[key] = index
+	}
+
+	if err = flush(); err != nil {
+		return "", 0, err
+	}
+
+	if nRecord == 0 {
+		return "", 0, nil
+	}
+
+	return buff.String(), nRecord, nil
+}
+
+func (*ModeColumn) importError(err error, formatWithArgs ...any) *errors.ImportError {
+	return errors.AsOrNewImportError(err, formatWithArgs...)
+}
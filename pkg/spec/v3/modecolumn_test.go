@@ -0,0 +1,81 @@
+package specv3
+
+import (
+	stderrors "errors"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+	specbase "github.com/vesoft-inc/nebula-importer/v4/pkg/spec/base"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ModeColumn", func() {
+	It(".Complete", func() {
+		c := &ModeColumn{Modes: map[string]specbase.Mode{"I": "insert", "D": "Delete"}}
+		c.Complete()
+		Expect(c.Modes).To(Equal(map[string]specbase.Mode{"I": specbase.InsertMode, "D": specbase.DeleteMode}))
+		Expect(c.ModeList()).To(Equal([]specbase.Mode{specbase.DeleteMode, specbase.InsertMode}))
+	})
+
+	DescribeTable(".Validate",
+		func(c *ModeColumn, expectErr error) {
+			c.Complete()
+			err := c.Validate()
+			if expectErr != nil {
+				if Expect(err).To(HaveOccurred()) {
+					Expect(stderrors.Is(err, expectErr)).To(BeTrue())
+				}
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry("invalid index", &ModeColumn{Index: -1, Modes: map[string]specbase.Mode{"I": specbase.InsertMode}}, errors.ErrInvalidIndex),
+		Entry("no modes", &ModeColumn{}, errors.ErrUnsupportedMode),
+		Entry("unsupported mode", &ModeColumn{Modes: map[string]specbase.Mode{"I": "x"}}, errors.ErrUnsupportedMode),
+		Entry("normal", &ModeColumn{Index: 1, Modes: map[string]specbase.Mode{"I": specbase.InsertMode, "U": "update"}}, nil),
+	)
+
+	DescribeTable(".Mode",
+		func(record Record, expectMode specbase.Mode, expectErr error) {
+			c := &ModeColumn{Index: 1, Modes: map[string]specbase.Mode{"I": specbase.InsertMode, "D": specbase.DeleteMode}}
+			c.Complete()
+			Expect(c.Validate()).NotTo(HaveOccurred())
+			mode, err := c.Mode(record)
+			if expectErr != nil {
+				if Expect(err).To(HaveOccurred()) {
+					Expect(stderrors.Is(err, expectErr)).To(BeTrue())
+				}
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(mode).To(Equal(expectMode))
+		},
+		Entry("no record", Record{"1"}, specbase.Mode(""), errors.ErrNoRecord),
+		Entry("unknown value", Record{"1", "U"}, specbase.Mode(""), errors.ErrUnsupportedMode),
+		Entry("insert", Record{"1", "I"}, specbase.InsertMode, nil),
+		Entry("delete", Record{"1", "D"}, specbase.DeleteMode, nil),
+	)
+
+	It(".Indices", func() {
+		Expect((&ModeColumn{Index: 2}).Indices()).To(Equal([]int{2}))
+	})
+
+	It(".ResolveColumns", func() {
+		c := &ModeColumn{}
+		Expect(c.HasColumns()).To(BeFalse())
+		resolved, err := c.ResolveColumns(Columns{"op": 1})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resolved.Index).To(Equal(0))
+
+		c = &ModeColumn{Column: "op"}
+		Expect(c.HasColumns()).To(BeTrue())
+		resolved, err = c.ResolveColumns(Columns{"op": 1})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resolved.Index).To(Equal(1))
+		Expect(c.Index).To(Equal(0))
+
+		_, err = c.ResolveColumns(Columns{"id": 0})
+		Expect(stderrors.Is(err, errors.ErrNoColumn)).To(BeTrue())
+	})
+})
@@ -22,12 +22,21 @@ type (
 		Filter *specbase.Filter `yaml:"filter,omitempty"`
 
 		Mode specbase.Mode `yaml:"mode,omitempty"`
+		// When is the condition in UPDATE and UPSERT mode, such as "updated_at < {updated_at}",
+		// the placeholders are replaced by the values of the props.
+		When string `yaml:"when,omitempty"`
+		// ModeColumn picks the mode of each record from the column instead of the Mode.
+		ModeColumn *ModeColumn `yaml:"modeColumn,omitempty"`
 
 		fnStatement func(records ...Record) (string, int, error)
 		// "INSERT VERTEX name(prop_name, ..., prop_name) VALUES "
-		// "UPDATE VERTEX ON name "
+		// "UPDATE|UPSERT VERTEX ON name "
 		// "DELETE TAG name FROM "
+		// "DELETE VERTEX "
 		statementPrefix string
+		whenTemplate    *template
+		// the statement functions of the modes of the ModeColumn
+		modeStatements map[specbase.Mode]modeStatementFunc
 	}
 
 	Nodes []*Node
@@ -74,6 +83,18 @@ func WithNodeMode(m specbase.Mode) NodeOption {
 	}
 }
 
+func WithNodeWhen(when string) NodeOption {
+	return func(n *Node) {
+		n.When = when
+	}
+}
+
+func WithNodeModeColumn(c *ModeColumn) NodeOption {
+	return func(n *Node) {
+		n.ModeColumn = c
+	}
+}
+
 func (n *Node) Options(opts ...NodeOption) *Node {
 	for _, opt := range opts {
 		opt(n)
@@ -106,9 +127,20 @@ func (n *Node) Complete() {
 	case specbase.UpdateMode:
 		n.fnStatement = n.updateStatement
 		n.statementPrefix = fmt.Sprintf("UPDATE VERTEX ON %s ", utils.ConvertIdentifier(n.Name))
+	case specbase.UpsertMode:
+		n.fnStatement = n.updateStatement
+		n.statementPrefix = fmt.Sprintf("UPSERT VERTEX ON %s ", utils.ConvertIdentifier(n.Name))
 	case specbase.DeleteMode:
 		n.fnStatement = n.deleteStatement
 		n.statementPrefix = fmt.Sprintf("DELETE TAG %s FROM ", utils.ConvertIdentifier(n.Name))
+	case specbase.DeleteVertexMode:
+		n.fnStatement = n.deleteVertexStatement
+		n.statementPrefix = "DELETE VERTEX "
+	}
+
+	if n.ModeColumn != nil {
+		n.ModeColumn.Complete()
+		n.fnStatement = n.modeColumnStatement
 	}
 }
 
@@ -135,17 +167,112 @@ func (n *Node) Validate() error {
 		}
 	}
 
-	if !n.Mode.IsSupport() {
-		return n.importError(errors.ErrUnsupportedMode)
+	if n.ModeColumn != nil {
+		if err := n.ModeColumn.Validate(); err != nil {
+			return n.importError(err)
+		}
+	}
+	modes := n.Modes()
+
+	hasUpdate := false
+	for _, mode := range modes {
+		if !mode.IsSupport() || mode == specbase.DeleteAllRanksMode {
+			return n.importError(errors.ErrUnsupportedMode)
+		}
+		if mode == specbase.UpdateMode || mode == specbase.UpsertMode {
+			if len(n.Props) == 0 {
+				return n.importError(errors.ErrNoProps)
+			}
+			hasUpdate = true
+		}
+	}
+
+	if (n.When != "" || n.Props.HasSet()) && !hasUpdate {
+		return n.importError(errors.ErrUnsupportedMode, "when and set are only supported in UPDATE and UPSERT mode")
+	}
+
+	if n.When != "" {
+		t, err := n.Props.parseTemplate(n.When)
+		if err != nil {
+			return n.importError(err, "when %s", n.When)
+		}
+		n.whenTemplate = t
 	}
 
-	if n.Mode == specbase.UpdateMode && len(n.Props) == 0 {
-		return n.importError(errors.ErrNoProps)
+	if n.ModeColumn != nil {
+		n.modeStatements = make(map[specbase.Mode]modeStatementFunc, len(modes))
+		for _, mode := range modes {
+			cpy := *n
+			cpy.Filter, cpy.ModeColumn, cpy.Mode = nil, nil, mode
+			cpy.Complete()
+			n.modeStatements[mode] = cpy.fnStatement
+		}
 	}
 
 	return nil
 }
 
+// Modes returns the modes of the node, they are the modes of the ModeColumn if it's set.
+func (n *Node) Modes() []specbase.Mode {
+	if n.ModeColumn != nil {
+		return n.ModeColumn.ModeList()
+	}
+	return []specbase.Mode{n.Mode.Convert()}
+}
+
+// Indices returns the record indices referenced by the node.
+// It returns false if they cannot be determined, see Filter.Indices for details.
+func (n *Node) Indices() ([]int, bool) {
+	var indices []int
+	if n.Filter != nil {
+		filterIndices, ok := n.Filter.Indices()
+		if !ok {
+			return nil, false
+		}
+		indices = append(indices, filterIndices...)
+	}
+	if n.ID != nil {
+		indices = append(indices, n.ID.Indices()...)
+	}
+	if n.ModeColumn != nil {
+		indices = append(indices, n.ModeColumn.Indices()...)
+	}
+	indices = append(indices, n.Props.Indices()...)
+	return indices, true
+}
+
+// HasColumns returns whether the node refers to the columns by names.
+func (n *Node) HasColumns() bool {
+	return (n.ID != nil && n.ID.HasColumns()) ||
+		(n.ModeColumn != nil && n.ModeColumn.HasColumns()) ||
+		n.Props.HasColumns()
+}
+
+// ResolveColumns returns a copy of the node whose column names are resolved to the record indices.
+func (n *Node) ResolveColumns(columns Columns) (*Node, error) {
+	cpy := *n
+	if n.ID != nil {
+		id, err := n.ID.ResolveColumns(columns)
+		if err != nil {
+			return nil, n.importError(err)
+		}
+		cpy.ID = id
+	}
+	if n.ModeColumn != nil {
+		modeColumn, err := n.ModeColumn.ResolveColumns(columns)
+		if err != nil {
+			return nil, n.importError(err)
+		}
+		cpy.ModeColumn = modeColumn
+	}
+	props, err := n.Props.ResolveColumns(columns)
+	if err != nil {
+		return nil, n.importError(err)
+	}
+	cpy.Props = props
+	return &cpy, nil
+}
+
 func (n *Node) Statement(records ...Record) (statement string, nRecord int, err error) {
 	return n.fnStatement(records...)
 }
@@ -213,16 +340,20 @@ func (n *Node) updateStatement(records ...Record) (statement string, nRecord int
 		if err != nil {
 			return "", 0, n.importError(err)
 		}
-		propsSetValueList, err := n.Props.SetValueList(record)
+		propsValueList, err := n.Props.ValueList(record)
 		if err != nil {
 			return "", 0, n.importError(err)
 		}
 
-		// "UPDATE VERTEX ON name id SET prop_name1 = prop_value1, prop_name1 = prop_value1, ...;"
+		// "UPDATE|UPSERT VERTEX ON name id SET prop_name1 = prop_value1, prop_name1 = prop_value1, ... [WHEN condition];"
 		_, _ = buff.WriteString(n.statementPrefix)
 		_, _ = buff.WriteString(idValue)
 		_, _ = buff.WriteString(" SET ")
-		_, _ = buff.WriteStringSlice(propsSetValueList, ", ")
+		_, _ = buff.WriteStringSlice(n.Props.setValueList(propsValueList), ", ")
+		if n.whenTemplate != nil {
+			_, _ = buff.WriteString(" WHEN ")
+			_, _ = buff.WriteString(n.whenTemplate.Render(propsValueList))
+		}
 		_, _ = buff.WriteString(";")
 
 		nRecord++
@@ -261,6 +392,54 @@ func (n *Node) deleteStatement(records ...Record) (statement string, nRecord int
 	return buff.String(), nRecord, nil
 }
 
+func (n *Node) deleteVertexStatement(records ...Record) (statement string, nRecord int, err error) {
+	buff := bytebufferpool.Get()
+	defer bytebufferpool.Put(buff)
+
+	buff.SetString(n.statementPrefix)
+
+	for _, record := range records {
+		if n.Filter != nil {
+			ok, err := n.Filter.Filter(record)
+			if err != nil {
+				return "", 0, n.importError(err)
+			}
+			if !ok { // skipping those return false by Filter
+				continue
+			}
+		}
+		idValue, err := n.ID.Value(record)
+		if err != nil {
+			return "", 0, n.importError(err)
+		}
+
+		if nRecord > 0 {
+			_, _ = buff.WriteString(", ")
+		}
+
+		// "DELETE VERTEX id1, id2, ... WITH EDGE"
+		_, _ = buff.WriteString(idValue)
+
+		nRecord++
+	}
+
+	if nRecord == 0 {
+		return "", 0, nil
+	}
+
+	_, _ = buff.WriteString(" WITH EDGE")
+	return buff.String(), nRecord, nil
+}
+
+// modeColumnStatement groups the records by the modes of the ModeColumn, the records of each vertex are kept in order.
+func (n *Node) modeColumnStatement(records ...Record) (statement string, nRecord int, err error) {
+	statement, nRecord, err = n.ModeColumn.statement(records, n.Filter, n.ID.Value, n.modeStatements)
+	if err != nil {
+		return "", 0, n.importError(err)
+	}
+	return statement, nRecord, nil
+}
+
 func (n *Node) importError(err error, formatWithArgs ...any) *errors.ImportError {
 	return errors.AsOrNewImportError(err, formatWithArgs...).SetNodeName(n.Name)
 }
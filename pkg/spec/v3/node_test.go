@@ -107,6 +107,18 @@ var _ = Describe("Node", func() {
 			Expect(stderrors.Is(err, errors.ErrNoProps)).To(BeTrue())
 		})
 
+		It("mode validate upsert no props failed", func() {
+			node := NewNode(
+				"name",
+				WithNodeID(&NodeID{Name: "id", Type: ValueTypeInt}),
+				WithNodeMode(specbase.UpsertMode),
+			)
+			node.Complete()
+			err := node.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(stderrors.Is(err, errors.ErrNoProps)).To(BeTrue())
+		})
+
 		It("success without props", func() {
 			node := NewNode(
 				"name",
@@ -129,6 +141,79 @@ var _ = Describe("Node", func() {
 		})
 	})
 
+	Describe(".Indices", func() {
+		It("successfully", func() {
+			node := NewNode(
+				"name",
+				WithNodeID(&NodeID{Name: "id", ConcatItems: []any{"c", 1, 2}}),
+				WithNodeProps(
+					&Prop{Name: "prop1", Index: 3},
+					&Prop{Name: "prop2", Index: 4, Nullable: true, AlternativeIndices: []int{5}},
+				),
+				WithNodeFilter(&specbase.Filter{Expr: `Record[6] == "A"`}),
+			)
+			indices, ok := node.Indices()
+			Expect(ok).To(BeTrue())
+			Expect(indices).To(ConsistOf(1, 2, 3, 4, 5, 6))
+		})
+
+		It("undetermined filter", func() {
+			node := NewNode(
+				"name",
+				WithNodeID(&NodeID{Name: "id", Index: 1}),
+				WithNodeFilter(&specbase.Filter{Expr: `len(Record) > 1`}),
+			)
+			indices, ok := node.Indices()
+			Expect(ok).To(BeFalse())
+			Expect(indices).To(BeNil())
+		})
+	})
+
+	Describe(".ResolveColumns", func() {
+		columns := NewColumns([]string{"name", "id", "age"})
+
+		It("successfully", func() {
+			node := NewNode(
+				"name",
+				WithNodeID(&NodeID{Name: "id", Column: "id"}),
+				WithNodeProps(
+					&Prop{Name: "name", Column: "name"},
+					&Prop{Name: "age", Index: 2},
+				),
+			)
+			Expect(node.HasColumns()).To(BeTrue())
+			resolved, err := node.ResolveColumns(columns)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved).NotTo(BeIdenticalTo(node))
+			indices, ok := resolved.Indices()
+			Expect(ok).To(BeTrue())
+			Expect(indices).To(Equal([]int{1, 0, 2}))
+
+			// not changed
+			Expect(node.ID.Index).To(Equal(0))
+		})
+
+		It("no columns", func() {
+			node := NewNode("name", WithNodeID(&NodeID{Name: "id", Index: 1}))
+			Expect(node.HasColumns()).To(BeFalse())
+		})
+
+		It("failed", func() {
+			_, err := NewNode("name", WithNodeID(&NodeID{Name: "id", Column: "not-exists"})).ResolveColumns(columns)
+			Expect(stderrors.Is(err, errors.ErrNoColumn)).To(BeTrue())
+
+			_, err = NewNode("name",
+				WithNodeID(&NodeID{Name: "id", Column: "id"}),
+				WithNodeProps(&Prop{Name: "p", Column: "not-exists"}),
+			).ResolveColumns(columns)
+			Expect(stderrors.Is(err, errors.ErrNoColumn)).To(BeTrue())
+			e, ok := errors.AsImportError(err)
+			Expect(ok).To(BeTrue())
+			Expect(e.NodeName()).To(Equal("name"))
+			Expect(e.PropName()).To(Equal("p"))
+		})
+	})
+
 	Describe(".Statement", func() {
 		When("INSERT", func() {
 			When("no props", func() {
@@ -510,6 +595,269 @@ var _ = Describe("Node", func() {
 			})
 		})
 
+		When("UPSERT", func() {
+			It("successfully", func() {
+				node := NewNode(
+					"name",
+					WithNodeID(&NodeID{Name: "id", Type: ValueTypeInt, Index: 0}),
+					WithNodeProps(
+						&Prop{Name: "prop1", Type: ValueTypeString, Index: 1},
+						&Prop{Name: "prop2", Type: ValueTypeDouble, Index: 2},
+					),
+					WithNodeFilter(&specbase.Filter{
+						Expr: `Record[0] != "2"`,
+					}),
+					WithNodeMode(specbase.Mode("upsert")),
+				)
+				node.Complete()
+				err := node.Validate()
+				Expect(err).NotTo(HaveOccurred())
+
+				statement, nRecord, err := node.Statement([]string{"1", "str1", "1.1"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nRecord).To(Equal(1))
+				Expect(statement).To(Equal("UPSERT VERTEX ON `name` 1 SET `prop1` = \"str1\", `prop2` = 1.1;"))
+
+				statement, nRecord, err = node.Statement([]string{"1", "str1", "1.1"}, []string{"2", "str2", "2.2"}, []string{"3", "str3", "3.3"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nRecord).To(Equal(2))
+				Expect(statement).To(Equal("UPSERT VERTEX ON `name` 1 SET `prop1` = \"str1\", `prop2` = 1.1;UPSERT VERTEX ON `name` 3 SET `prop1` = \"str3\", `prop2` = 3.3;"))
+
+				statement, nRecord, err = node.Statement([]string{"1", "str1"})
+				Expect(err).To(HaveOccurred())
+				Expect(stderrors.Is(err, errors.ErrNoRecord)).To(BeTrue())
+				Expect(nRecord).To(Equal(0))
+				Expect(statement).To(BeEmpty())
+			})
+		})
+
+		When("mode column", func() {
+			newNode := func(opts ...NodeOption) *Node {
+				return NewNode(
+					"name",
+					append([]NodeOption{
+						WithNodeID(&NodeID{Name: "id", Type: ValueTypeInt, Index: 0}),
+						WithNodeProps(&Prop{Name: "prop1", Type: ValueTypeString, Index: 1}),
+						WithNodeModeColumn(&ModeColumn{Index: 2, Modes: map[string]specbase.Mode{
+							"I": specbase.InsertMode,
+							"U": "update",
+							"D": specbase.DeleteVertexMode,
+						}}),
+					}, opts...)...,
+				)
+			}
+
+			It("successfully", func() {
+				node := newNode(WithNodeFilter(&specbase.Filter{
+					Expr: `Record[1] != "x"`,
+				}))
+				node.Complete()
+				Expect(node.Validate()).NotTo(HaveOccurred())
+				Expect(node.Modes()).To(Equal([]specbase.Mode{specbase.DeleteVertexMode, specbase.InsertMode, specbase.UpdateMode}))
+
+				statement, nRecord, err := node.Statement(
+					[]string{"1", "a", "I"},
+					[]string{"2", "b", "I"},
+					[]string{"5", "x", "I"},
+					[]string{"1", "c", "U"},
+					[]string{"3", "d", "D"},
+					[]string{"2", "e", "D"},
+					// 2 is deleted before, so it's inserted after the deletion.
+					[]string{"2", "f", "I"},
+				)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nRecord).To(Equal(6))
+				Expect(statement).To(Equal("INSERT VERTEX IGNORE_EXISTED_INDEX `name`(`prop1`) VALUES 1:(\"a\"), 2:(\"b\");" +
+					"UPDATE VERTEX ON `name` 1 SET `prop1` = \"c\";" +
+					"DELETE VERTEX 3, 2 WITH EDGE;" +
+					"INSERT VERTEX IGNORE_EXISTED_INDEX `name`(`prop1`) VALUES 2:(\"f\")"))
+
+				statement, nRecord, err = node.Statement([]string{"1", "x", "I"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nRecord).To(Equal(0))
+				Expect(statement).To(BeEmpty())
+
+				statement, nRecord, err = node.Statement([]string{"1", "a", "X"})
+				Expect(stderrors.Is(err, errors.ErrUnsupportedMode)).To(BeTrue())
+				Expect(nRecord).To(Equal(0))
+				Expect(statement).To(BeEmpty())
+
+				statement, nRecord, err = node.Statement([]string{"1", "a"})
+				Expect(stderrors.Is(err, errors.ErrNoRecord)).To(BeTrue())
+				Expect(nRecord).To(Equal(0))
+				Expect(statement).To(BeEmpty())
+			})
+
+			It("indices and columns", func() {
+				node := newNode()
+				indices, ok := node.Indices()
+				Expect(ok).To(BeTrue())
+				Expect(indices).To(ConsistOf(0, 1, 2))
+				Expect(node.HasColumns()).To(BeFalse())
+
+				node.ModeColumn.Column = "op"
+				Expect(node.HasColumns()).To(BeTrue())
+				resolved, err := node.ResolveColumns(Columns{"op": 3})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resolved.ModeColumn.Index).To(Equal(3))
+				Expect(node.ModeColumn.Index).To(Equal(2))
+
+				_, err = node.ResolveColumns(Columns{})
+				Expect(stderrors.Is(err, errors.ErrNoColumn)).To(BeTrue())
+			})
+
+			It("unsupported edge mode", func() {
+				node := newNode(WithNodeModeColumn(&ModeColumn{Modes: map[string]specbase.Mode{"D": specbase.DeleteAllRanksMode}}))
+				node.Complete()
+				Expect(stderrors.Is(node.Validate(), errors.ErrUnsupportedMode)).To(BeTrue())
+			})
+
+			It("invalid mode column", func() {
+				node := newNode(WithNodeModeColumn(&ModeColumn{Index: 2}))
+				node.Complete()
+				Expect(stderrors.Is(node.Validate(), errors.ErrUnsupportedMode)).To(BeTrue())
+			})
+
+			It("when without update", func() {
+				node := newNode(
+					WithNodeModeColumn(&ModeColumn{Modes: map[string]specbase.Mode{"I": specbase.InsertMode}}),
+					WithNodeWhen("prop1 != {prop1}"),
+				)
+				node.Complete()
+				Expect(stderrors.Is(node.Validate(), errors.ErrUnsupportedMode)).To(BeTrue())
+			})
+
+			It("update without props", func() {
+				node := NewNode(
+					"name",
+					WithNodeID(&NodeID{Name: "id", Type: ValueTypeInt, Index: 0}),
+					WithNodeModeColumn(&ModeColumn{Index: 1, Modes: map[string]specbase.Mode{"U": specbase.UpsertMode}}),
+				)
+				node.Complete()
+				Expect(stderrors.Is(node.Validate(), errors.ErrNoProps)).To(BeTrue())
+			})
+		})
+
+		When("set and when", func() {
+			It("successfully", func() {
+				node := NewNode(
+					"name",
+					WithNodeID(&NodeID{Name: "id", Type: ValueTypeInt, Index: 0}),
+					WithNodeProps(
+						&Prop{Name: "score", Type: ValueTypeInt, Index: 1, Set: "score + {value}"},
+						&Prop{Name: "updated_at", Type: ValueTypeInt, Index: 2},
+					),
+					WithNodeWhen("updated_at < {updated_at}"),
+					WithNodeMode(specbase.UpsertMode),
+				)
+				node.Complete()
+				Expect(node.Validate()).NotTo(HaveOccurred())
+
+				statement, nRecord, err := node.Statement([]string{"1", "10", "100"}, []string{"2", "20", "200"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nRecord).To(Equal(2))
+				Expect(statement).To(Equal("UPSERT VERTEX ON `name` 1 SET `score` = score + 10, `updated_at` = 100 WHEN updated_at < 100;" +
+					"UPSERT VERTEX ON `name` 2 SET `score` = score + 20, `updated_at` = 200 WHEN updated_at < 200;"))
+			})
+
+			It("unsupported mode", func() {
+				node := NewNode(
+					"name",
+					WithNodeID(&NodeID{Name: "id", Type: ValueTypeInt, Index: 0}),
+					WithNodeProps(&Prop{Name: "score", Type: ValueTypeInt, Index: 1}),
+					WithNodeWhen("score < {score}"),
+				)
+				node.Complete()
+				err := node.Validate()
+				Expect(stderrors.Is(err, errors.ErrUnsupportedMode)).To(BeTrue())
+
+				node = NewNode(
+					"name",
+					WithNodeID(&NodeID{Name: "id", Type: ValueTypeInt, Index: 0}),
+					WithNodeProps(&Prop{Name: "score", Type: ValueTypeInt, Index: 1, Set: "score + {value}"}),
+					WithNodeMode(specbase.DeleteMode),
+				)
+				node.Complete()
+				err = node.Validate()
+				Expect(stderrors.Is(err, errors.ErrUnsupportedMode)).To(BeTrue())
+			})
+
+			It("unknown placeholder", func() {
+				node := NewNode(
+					"name",
+					WithNodeID(&NodeID{Name: "id", Type: ValueTypeInt, Index: 0}),
+					WithNodeProps(&Prop{Name: "score", Type: ValueTypeInt, Index: 1}),
+					WithNodeWhen("updated_at < {updated_at}"),
+					WithNodeMode(specbase.UpdateMode),
+				)
+				node.Complete()
+				err := node.Validate()
+				Expect(stderrors.Is(err, errors.ErrInvalidTemplate)).To(BeTrue())
+				e, ok := errors.AsImportError(err)
+				Expect(ok).To(BeTrue())
+				Expect(e.NodeName()).To(Equal("name"))
+			})
+		})
+
+		When("DELETE_VERTEX", func() {
+			It("successfully", func() {
+				node := NewNode(
+					"name",
+					WithNodeID(&NodeID{Name: "id", Type: ValueTypeString, Index: 0}),
+					WithNodeProps(&Prop{Name: "prop1", Type: ValueTypeString, Index: 1}),
+					WithNodeFilter(&specbase.Filter{
+						Expr: `Record[1] != "A"`,
+					}),
+					WithNodeMode(specbase.Mode("delete_vertex")),
+				)
+				node.Complete()
+				Expect(node.Validate()).NotTo(HaveOccurred())
+
+				statement, nRecord, err := node.Statement([]string{"id1", "B"}, []string{"id2", "A"}, []string{"id3", "C"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nRecord).To(Equal(2))
+				Expect(statement).To(Equal("DELETE VERTEX \"id1\", \"id3\" WITH EDGE"))
+
+				// all false
+				statement, nRecord, err = node.Statement([]string{"id2", "A"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nRecord).To(Equal(0))
+				Expect(statement).To(BeEmpty())
+
+				// filter failed
+				statement, nRecord, err = node.Statement([]string{"id1"})
+				Expect(err).To(HaveOccurred())
+				Expect(nRecord).To(Equal(0))
+				Expect(statement).To(BeEmpty())
+			})
+
+			It("failed id no record", func() {
+				node := NewNode(
+					"name",
+					WithNodeID(&NodeID{Name: "id", Type: ValueTypeString, Index: 1}),
+					WithNodeMode(specbase.DeleteVertexMode),
+				)
+				node.Complete()
+				Expect(node.Validate()).NotTo(HaveOccurred())
+
+				statement, nRecord, err := node.Statement([]string{"id1"})
+				Expect(stderrors.Is(err, errors.ErrNoRecord)).To(BeTrue())
+				Expect(nRecord).To(Equal(0))
+				Expect(statement).To(BeEmpty())
+			})
+
+			It("unsupported edge mode", func() {
+				node := NewNode(
+					"name",
+					WithNodeID(&NodeID{Name: "id", Type: ValueTypeString, Index: 0}),
+					WithNodeMode(specbase.DeleteAllRanksMode),
+				)
+				node.Complete()
+				err := node.Validate()
+				Expect(stderrors.Is(err, errors.ErrUnsupportedMode)).To(BeTrue())
+			})
+		})
+
 		When("DELETE", func() {
 			When("no props", func() {
 				var node *Node
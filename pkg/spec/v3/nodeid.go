@@ -17,7 +17,8 @@ type (
 		Name        string    `yaml:"-"`
 		Type        ValueType `yaml:"type"`
 		Index       int       `yaml:"index"`
-		ConcatItems []any     `yaml:"concatItems,omitempty"` // only support string and int, string for constant, int is for Index
+		Column      string    `yaml:"column,omitempty"`      // the column name in the header, override the Index
+		ConcatItems []any     `yaml:"concatItems,omitempty"` // only support string, int and {column: name}, string for constant, int is for Index
 		Function    *string   `yaml:"function"`
 
 		picker picker.Picker
@@ -64,6 +65,55 @@ func (id *NodeID) Value(record Record) (string, error) {
 	return val.Val, nil
 }
 
+// Indices returns the record indices referenced by the id.
+func (id *NodeID) Indices() []int {
+	if len(id.ConcatItems) > 0 {
+		concatItems := picker.ConcatItems{}
+		_ = concatItems.Add(id.ConcatItems...)
+		return concatItems.Indices()
+	}
+	return []int{id.Index}
+}
+
+// HasColumns returns whether the id refers to the columns by names.
+func (id *NodeID) HasColumns() bool {
+	if id.Column != "" {
+		return true
+	}
+	for _, item := range id.ConcatItems {
+		if _, ok := concatItemColumn(item); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveColumns returns a copy of the id whose column names are resolved to the record indices.
+func (id *NodeID) ResolveColumns(columns Columns) (*NodeID, error) {
+	cpy := *id
+	if id.Column != "" {
+		index, err := columns.Index(id.Column)
+		if err != nil {
+			return nil, id.importError(err)
+		}
+		cpy.Index = index
+	}
+	if len(id.ConcatItems) > 0 {
+		cpy.ConcatItems = make([]any, len(id.ConcatItems))
+		for i, item := range id.ConcatItems {
+			if column, ok := concatItemColumn(item); ok {
+				index, err := columns.Index(column)
+				if err != nil {
+					return nil, id.importError(err)
+				}
+				item = index
+			}
+			cpy.ConcatItems[i] = item
+		}
+	}
+	return &cpy, nil
+}
+
 func (id *NodeID) initPicker() error {
 	pickerConfig := picker.Config{
 		Type:     string(id.Type),
@@ -11,13 +11,19 @@ type (
 		Name               string    `yaml:"name"`
 		Type               ValueType `yaml:"type"`
 		Index              int       `yaml:"index"`
+		Column             string    `yaml:"column,omitempty"` // the column name in the header, override the Index
 		Nullable           bool      `yaml:"nullable"`
 		NullValue          string    `yaml:"nullValue"`
 		AlternativeIndices []int     `yaml:"alternativeIndices,omitempty"`
+		AlternativeColumns []string  `yaml:"alternativeColumns,omitempty"` // appended to the AlternativeIndices
 		DefaultValue       *string   `yaml:"defaultValue"`
+		// Set is the expression of the prop in UPDATE and UPSERT mode, such as "score + {value}",
+		// {value} is replaced by the value of the record.
+		Set string `yaml:"set,omitempty"`
 
 		convertedName string
 		picker        picker.Picker
+		setTemplate   *template
 	}
 
 	Props []*Prop
@@ -40,6 +46,13 @@ func (p *Prop) Validate() error {
 	if err := p.initPicker(); err != nil {
 		return p.importError(err, "init picker failed")
 	}
+	if p.Set != "" {
+		t, err := parseTemplate(p.Set, map[string]int{"value": 0})
+		if err != nil {
+			return p.importError(err, "set %s", p.Set)
+		}
+		p.setTemplate = t
+	}
 	return nil
 }
 
@@ -57,7 +70,53 @@ func (p *Prop) SetValue(record Record) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return p.convertedName + " = " + val, nil
+	return p.setValue(val), nil
+}
+
+func (p *Prop) setValue(val string) string {
+	if p.setTemplate != nil {
+		val = p.setTemplate.Render([]string{val})
+	}
+	return p.convertedName + " = " + val
+}
+
+// Indices returns the record indices referenced by the prop.
+func (p *Prop) Indices() []int {
+	indices := make([]int, 0, 1+len(p.AlternativeIndices))
+	indices = append(indices, p.Index)
+	if p.Nullable {
+		indices = append(indices, p.AlternativeIndices...)
+	}
+	return indices
+}
+
+// HasColumns returns whether the prop refers to the columns by names.
+func (p *Prop) HasColumns() bool {
+	return p.Column != "" || len(p.AlternativeColumns) > 0
+}
+
+// ResolveColumns returns a copy of the prop whose column names are resolved to the record indices.
+func (p *Prop) ResolveColumns(columns Columns) (*Prop, error) {
+	cpy := *p
+	if p.Column != "" {
+		index, err := columns.Index(p.Column)
+		if err != nil {
+			return nil, p.importError(err)
+		}
+		cpy.Index = index
+	}
+	if len(p.AlternativeColumns) > 0 {
+		cpy.AlternativeIndices = make([]int, 0, len(p.AlternativeIndices)+len(p.AlternativeColumns))
+		cpy.AlternativeIndices = append(cpy.AlternativeIndices, p.AlternativeIndices...)
+		for _, column := range p.AlternativeColumns {
+			index, err := columns.Index(column)
+			if err != nil {
+				return nil, p.importError(err)
+			}
+			cpy.AlternativeIndices = append(cpy.AlternativeIndices, index)
+		}
+	}
+	return &cpy, nil
 }
 
 func (p *Prop) initPicker() error {
@@ -114,15 +173,68 @@ func (ps Props) ValueList(record Record) ([]string, error) {
 }
 
 func (ps Props) SetValueList(record Record) ([]string, error) {
+	valueList, err := ps.ValueList(record)
+	if err != nil {
+		return nil, err
+	}
+	return ps.setValueList(valueList), nil
+}
+
+// setValueList returns the SET items of the props by the values returned by ValueList.
+func (ps Props) setValueList(valueList []string) []string {
 	setValueList := make([]string, 0, len(ps))
+	for i, prop := range ps {
+		setValueList = append(setValueList, prop.setValue(valueList[i]))
+	}
+	return setValueList
+}
+
+// HasSet returns whether any prop has the set expression.
+func (ps Props) HasSet() bool {
+	for _, prop := range ps {
+		if prop.Set != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTemplate parses the template whose placeholders are the names of the props, such as "{updated_at}".
+func (ps Props) parseTemplate(text string) (*template, error) {
+	names := make(map[string]int, len(ps))
+	for i, prop := range ps {
+		names[prop.Name] = i
+	}
+	return parseTemplate(text, names)
+}
+
+func (ps Props) Indices() []int {
+	indices := make([]int, 0, len(ps))
+	for _, prop := range ps {
+		indices = append(indices, prop.Indices()...)
+	}
+	return indices
+}
+
+func (ps Props) HasColumns() bool {
+	for _, prop := range ps {
+		if prop.HasColumns() {
+			return true
+		}
+	}
+	return false
+}
+
+func (ps Props) ResolveColumns(columns Columns) (Props, error) {
+	cpy := make(Props, 0, len(ps))
 	for _, prop := range ps {
-		value, err := prop.SetValue(record)
+		resolved, err := prop.ResolveColumns(columns)
 		if err != nil {
 			return nil, err
 		}
-		setValueList = append(setValueList, value)
+		cpy = append(cpy, resolved)
 	}
-	return setValueList, nil
+	return cpy, nil
 }
 
 func (ps Props) NameList() []string {
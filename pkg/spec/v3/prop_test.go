@@ -342,6 +342,39 @@ var _ = Describe("Prop", func() {
 			"`p1` = 1.1",
 			nil,
 		),
+		Entry("set",
+			&Prop{
+				Name:  "p1",
+				Type:  ValueTypeInt,
+				Index: 0,
+				Set:   "p1 + {value}",
+			},
+			Record([]string{"1"}),
+			"`p1` = p1 + 1",
+			nil,
+		),
+		Entry("set function",
+			&Prop{
+				Name:  "p1",
+				Type:  ValueTypeString,
+				Index: 0,
+				Set:   "max(p1, {value}) + {value}",
+			},
+			Record([]string{"str"}),
+			"`p1` = max(p1, \"str\") + \"str\"",
+			nil,
+		),
+		Entry("set unknown placeholder",
+			&Prop{
+				Name:  "p1",
+				Type:  ValueTypeInt,
+				Index: 0,
+				Set:   "p1 + {val}",
+			},
+			Record([]string{"1"}),
+			"",
+			errors.ErrInvalidTemplate,
+		),
 	)
 })
 
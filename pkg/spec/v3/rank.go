@@ -7,7 +7,8 @@ import (
 
 type (
 	Rank struct {
-		Index int `yaml:"index"`
+		Index  int    `yaml:"index"`
+		Column string `yaml:"column,omitempty"` // the column name in the header, override the Index
 
 		picker picker.Picker
 	}
@@ -32,6 +33,29 @@ func (r *Rank) Value(record Record) (string, error) {
 	return val.Val, nil
 }
 
+// Indices returns the record indices referenced by the rank.
+func (r *Rank) Indices() []int {
+	return []int{r.Index}
+}
+
+// HasColumns returns whether the rank refers to the column by name.
+func (r *Rank) HasColumns() bool {
+	return r.Column != ""
+}
+
+// ResolveColumns returns a copy of the rank whose column name is resolved to the record index.
+func (r *Rank) ResolveColumns(columns Columns) (*Rank, error) {
+	cpy := *r
+	if r.Column != "" {
+		index, err := columns.Index(r.Column)
+		if err != nil {
+			return nil, r.importError(err)
+		}
+		cpy.Index = index
+	}
+	return &cpy, nil
+}
+
 func (r *Rank) initPicker() error {
 	pickerConfig := picker.Config{
 		Indices: []int{r.Index},
@@ -0,0 +1,75 @@
+package specv3
+
+import (
+	"strings"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+)
+
+// template is the text with the placeholders in braces, such as "score + {value}", which are replaced by the values
+// of a record. The braces enclosing a non-identifier are kept as they are, such as the map "{a: 1}".
+type template struct {
+	texts   []string
+	indices []int
+}
+
+// parseTemplate parses the text, names maps the names of the placeholders to the indices of the values.
+func parseTemplate(text string, names map[string]int) (*template, error) {
+	t := &template{}
+	var buff strings.Builder
+	for {
+		start := strings.IndexByte(text, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(text[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+		name := text[start+1 : end]
+		if !isPlaceholderName(name) {
+			buff.WriteString(text[:start+1])
+			text = text[start+1:]
+			continue
+		}
+		index, ok := names[name]
+		if !ok {
+			return nil, errors.NewImportError(errors.ErrInvalidTemplate, "unknown placeholder {%s}", name)
+		}
+		buff.WriteString(text[:start])
+		t.texts = append(t.texts, buff.String())
+		t.indices = append(t.indices, index)
+		buff.Reset()
+		text = text[end+1:]
+	}
+	buff.WriteString(text)
+	t.texts = append(t.texts, buff.String())
+	return t, nil
+}
+
+// Render replaces the placeholders by the values.
+func (t *template) Render(values []string) string {
+	if len(t.indices) == 0 {
+		return t.texts[0]
+	}
+	var sb strings.Builder
+	for i, index := range t.indices {
+		sb.WriteString(t.texts[i])
+		sb.WriteString(values[index])
+	}
+	sb.WriteString(t.texts[len(t.texts)-1])
+	return sb.String()
+}
+
+func isPlaceholderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if c != '_' && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,37 @@
+package specv3
+
+import (
+	stderrors "errors"
+
+	"github.com/vesoft-inc/nebula-importer/v4/pkg/errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("template", func() {
+	names := map[string]int{"value": 0, "updated_at": 1}
+
+	DescribeTable("Render",
+		func(text string, values []string, expect string) {
+			t, err := parseTemplate(text, names)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t.Render(values)).To(Equal(expect))
+		},
+		Entry("empty", "", []string{"1", "2"}, ""),
+		Entry("no placeholders", "score + 1", []string{"1", "2"}, "score + 1"),
+		Entry("one placeholder", "score + {value}", []string{"1", "2"}, "score + 1"),
+		Entry("only placeholder", "{value}", []string{"1", "2"}, "1"),
+		Entry("placeholders", "{updated_at} > updated_at and {value} != {value}", []string{"1", "2"}, "2 > updated_at and 1 != 1"),
+		Entry("map", "{a: {value}}", []string{"1", "2"}, "{a: 1}"),
+		Entry("unclosed", "{value} + {value", []string{"1", "2"}, "1 + {value"),
+		Entry("empty braces", "{}{value}", []string{"1", "2"}, "{}1"),
+	)
+
+	It("unknown placeholder", func() {
+		t, err := parseTemplate("score + {score}", names)
+		Expect(stderrors.Is(err, errors.ErrInvalidTemplate)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("unknown placeholder {score}"))
+		Expect(t).To(BeNil())
+	})
+})
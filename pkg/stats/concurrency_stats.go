@@ -24,51 +24,147 @@ func (s *ConcurrencyStats) Init() {
 }
 
 func (s *ConcurrencyStats) AddTotalBytes(nBytes int64) {
-	s.mu.Lock()
-	s.s.TotalBytes += nBytes
-	s.mu.Unlock()
+	s.AddSourceTotalBytes("", nBytes)
 }
 
 func (s *ConcurrencyStats) Failed(nBytes, nRecords int64) {
-	s.mu.Lock()
-	s.s.ProcessedBytes += nBytes
-	s.s.FailedRecords += nRecords
-	s.s.TotalRecords += nRecords
-	s.mu.Unlock()
+	s.SourceFailed("", nBytes, nRecords)
 }
 
 func (s *ConcurrencyStats) Succeeded(nBytes, nRecords int64) {
-	s.mu.Lock()
-	s.s.ProcessedBytes += nBytes
-	s.s.TotalRecords += nRecords
-	s.mu.Unlock()
+	s.SourceSucceeded("", nBytes, nRecords)
+}
+
+func (s *ConcurrencyStats) Skipped(nBytes, nRecords int64) {
+	s.SourceSkipped("", nBytes, nRecords)
 }
 
 func (s *ConcurrencyStats) RequestFailed(nRecords int64) {
-	s.mu.Lock()
-	s.s.FailedRequest++
-	s.s.TotalRequest++
-	s.s.FailedProcessed += nRecords
-	s.s.TotalProcessed += nRecords
-	s.mu.Unlock()
+	s.TargetRequestFailed("", Target{}, nRecords)
 }
 
 func (s *ConcurrencyStats) RequestSucceeded(nRecords int64, latency, respTime time.Duration) {
+	s.TargetRequestSucceeded("", Target{}, nRecords, latency, respTime)
+}
+
+// AddSourceTotalBytes adds the total bytes of the source, the source is not broken down if it is empty.
+func (s *ConcurrencyStats) AddSourceTotalBytes(source string, nBytes int64) {
+	s.update(source, Target{}, func(st *Stats) {
+		st.TotalBytes += nBytes
+	})
+}
+
+func (s *ConcurrencyStats) SourceFailed(source string, nBytes, nRecords int64) {
+	s.update(source, Target{}, func(st *Stats) {
+		st.ProcessedBytes += nBytes
+		st.FailedRecords += nRecords
+		st.TotalRecords += nRecords
+	})
+}
+
+func (s *ConcurrencyStats) SourceSucceeded(source string, nBytes, nRecords int64) {
+	s.update(source, Target{}, func(st *Stats) {
+		st.ProcessedBytes += nBytes
+		st.TotalRecords += nRecords
+	})
+}
+
+func (s *ConcurrencyStats) SourceSkipped(source string, nBytes, nRecords int64) {
+	s.update(source, Target{}, func(st *Stats) {
+		st.ProcessedBytes += nBytes
+		st.SkippedRecords += nRecords
+	})
+}
+
+// TargetRequestFailed counts the failed request of the target from the source,
+// the source or the target is not broken down if it is empty.
+func (s *ConcurrencyStats) TargetRequestFailed(source string, target Target, nRecords int64) {
+	s.update(source, target, func(st *Stats) {
+		st.FailedRequest++
+		st.TotalRequest++
+		st.FailedProcessed += nRecords
+		st.TotalProcessed += nRecords
+	})
+}
+
+func (s *ConcurrencyStats) TargetRequestSucceeded(source string, target Target, nRecords int64, latency, respTime time.Duration) {
+	s.update(source, target, func(st *Stats) {
+		st.TotalRequest++
+		st.TotalLatency += latency
+		st.TotalRespTime += respTime
+		st.TotalProcessed += nRecords
+		if st.LatencyHistogram == nil {
+			st.LatencyHistogram, st.RespTimeHistogram = NewHistogram(), NewHistogram()
+		}
+		st.LatencyHistogram.Record(latency)
+		st.RespTimeHistogram.Record(respTime)
+	})
+}
+
+// update applies fn to the global stats, and the stats of the source and target if they are not empty.
+func (s *ConcurrencyStats) update(source string, target Target, fn func(*Stats)) {
 	s.mu.Lock()
-	s.s.TotalRequest++
-	s.s.TotalLatency += latency
-	s.s.TotalRespTime += respTime
-	s.s.TotalProcessed += nRecords
-	s.mu.Unlock()
+	defer s.mu.Unlock()
+
+	fn(&s.s)
+	if source != "" {
+		if s.s.Sources == nil {
+			s.s.Sources = map[string]*Stats{}
+		}
+		st, ok := s.s.Sources[source]
+		if !ok {
+			st = &Stats{}
+			s.s.Sources[source] = st
+		}
+		fn(st)
+	}
+	if target != (Target{}) {
+		if s.s.Targets == nil {
+			s.s.Targets = map[Target]*Stats{}
+		}
+		st, ok := s.s.Targets[target]
+		if !ok {
+			st = &Stats{}
+			s.s.Targets[target] = st
+		}
+		fn(st)
+	}
 }
 
+// Stats returns a copy of the stats, the start time of the sources and targets is the global one.
 func (s *ConcurrencyStats) Stats() *Stats {
 	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	cpy := s.s
-	s.mu.Unlock()
+	cpy.cloneHistograms()
+	if s.s.Sources != nil {
+		cpy.Sources = make(map[string]*Stats, len(s.s.Sources))
+		for name, st := range s.s.Sources {
+			stCpy := *st
+			stCpy.StartTime = cpy.StartTime
+			stCpy.cloneHistograms()
+			cpy.Sources[name] = &stCpy
+		}
+	}
+	if s.s.Targets != nil {
+		cpy.Targets = make(map[Target]*Stats, len(s.s.Targets))
+		for t, st := range s.s.Targets {
+			stCpy := *st
+			stCpy.StartTime = cpy.StartTime
+			stCpy.cloneHistograms()
+			cpy.Targets[t] = &stCpy
+		}
+	}
 	return &cpy
 }
 
+// cloneHistograms makes the histograms not shared with the original stats.
+func (s *Stats) cloneHistograms() {
+	s.LatencyHistogram = s.LatencyHistogram.Clone()
+	s.RespTimeHistogram = s.RespTimeHistogram.Clone()
+}
+
 func (s *ConcurrencyStats) String() string {
 	return s.Stats().String()
 }
@@ -84,6 +84,7 @@ var _ = Describe("ConcurrencyStats", func() {
 		wg.Wait()
 		concurrencyStats.Init()
 		s := concurrencyStats.Stats()
+		nSucceeded := int((sumBatches - sumFailedBatches) * 2)
 		Expect(s).To(Equal(&Stats{
 			StartTime:       initStats.StartTime,
 			ProcessedBytes:  sumBytes,
@@ -96,9 +97,116 @@ var _ = Describe("ConcurrencyStats", func() {
 			TotalRespTime:   11 * time.Millisecond * time.Duration(sumBatches-sumFailedBatches) * 2,
 			FailedProcessed: sumFailedRecords * 2,
 			TotalProcessed:  sumRecords * 2,
+
+			LatencyHistogram:  newTestHistogram(9*time.Millisecond, nSucceeded),
+			RespTimeHistogram: newTestHistogram(11*time.Millisecond, nSucceeded),
 		}))
 
 		Expect(s.Percentage()).To(Equal(100.0))
 		Expect(s.String()).To(ContainSubstring("100.00%("))
 	})
 })
+
+var _ = Describe("ConcurrencyStats Skipped", func() {
+	It("skipped", func() {
+		concurrencyStats := NewConcurrencyStats()
+		concurrencyStats.AddTotalBytes(100)
+		concurrencyStats.Skipped(40, 4)
+		concurrencyStats.Succeeded(60, 6)
+		s := concurrencyStats.Stats()
+		Expect(s.ProcessedBytes).To(Equal(int64(100)))
+		Expect(s.SkippedRecords).To(Equal(int64(4)))
+		Expect(s.TotalRecords).To(Equal(int64(6)))
+		Expect(s.Percentage()).To(Equal(100.0))
+	})
+})
+
+var _ = Describe("ConcurrencyStats breakdown", func() {
+	It("sources and targets", func() {
+		tag := Target{Kind: "tag", Name: "n1", Mode: "INSERT"}
+		edge := Target{Kind: "edge", Name: "e1", Mode: "INSERT"}
+
+		concurrencyStats := NewConcurrencyStats()
+		concurrencyStats.Init()
+		concurrencyStats.AddSourceTotalBytes("s1", 100)
+		concurrencyStats.AddSourceTotalBytes("s2", 200)
+		concurrencyStats.SourceSkipped("s1", 10, 1)
+		concurrencyStats.SourceSucceeded("s1", 90, 9)
+		concurrencyStats.SourceFailed("s2", 200, 20)
+		concurrencyStats.TargetRequestSucceeded("s1", tag, 9, time.Millisecond, 2*time.Millisecond)
+		concurrencyStats.TargetRequestSucceeded("s1", edge, 9, time.Millisecond, 2*time.Millisecond)
+		concurrencyStats.TargetRequestFailed("s2", tag, 20)
+		concurrencyStats.RequestFailed(1)
+
+		s := concurrencyStats.Stats()
+		Expect(s.TotalBytes).To(Equal(int64(300)))
+		Expect(s.TotalRequest).To(Equal(int64(4)))
+		Expect(s.FailedRequest).To(Equal(int64(2)))
+		Expect(s.Sources).To(Equal(map[string]*Stats{
+			"s1": {
+				StartTime:      s.StartTime,
+				ProcessedBytes: 100,
+				TotalBytes:     100,
+				TotalRecords:   9,
+				SkippedRecords: 1,
+				TotalRequest:   2,
+				TotalLatency:   2 * time.Millisecond,
+				TotalRespTime:  4 * time.Millisecond,
+				TotalProcessed: 18,
+
+				LatencyHistogram:  newTestHistogram(time.Millisecond, 2),
+				RespTimeHistogram: newTestHistogram(2*time.Millisecond, 2),
+			},
+			"s2": {
+				StartTime:       s.StartTime,
+				ProcessedBytes:  200,
+				TotalBytes:      200,
+				FailedRecords:   20,
+				TotalRecords:    20,
+				FailedRequest:   1,
+				TotalRequest:    1,
+				FailedProcessed: 20,
+				TotalProcessed:  20,
+			},
+		}))
+		Expect(s.Targets).To(Equal(map[Target]*Stats{
+			tag: {
+				StartTime:       s.StartTime,
+				FailedRequest:   1,
+				TotalRequest:    2,
+				TotalLatency:    time.Millisecond,
+				TotalRespTime:   2 * time.Millisecond,
+				FailedProcessed: 20,
+				TotalProcessed:  29,
+
+				LatencyHistogram:  newTestHistogram(time.Millisecond, 1),
+				RespTimeHistogram: newTestHistogram(2*time.Millisecond, 1),
+			},
+			edge: {
+				StartTime:      s.StartTime,
+				TotalRequest:   1,
+				TotalLatency:   time.Millisecond,
+				TotalRespTime:  2 * time.Millisecond,
+				TotalProcessed: 9,
+
+				LatencyHistogram:  newTestHistogram(time.Millisecond, 1),
+				RespTimeHistogram: newTestHistogram(2*time.Millisecond, 1),
+			},
+		}))
+
+		// The copies are not changed by the later updates.
+		concurrencyStats.SourceSucceeded("s1", 0, 1)
+		concurrencyStats.TargetRequestSucceeded("s1", tag, 1, time.Second, time.Second)
+		Expect(s.Sources["s1"].TotalRecords).To(Equal(int64(9)))
+		Expect(s.LatencyHistogram.Max()).To(Equal(time.Millisecond))
+		Expect(s.Targets[tag].RespTimeHistogram.Count()).To(Equal(int64(1)))
+	})
+})
+
+func newTestHistogram(d time.Duration, n int) *Histogram {
+	h := NewHistogram()
+	for i := 0; i < n; i++ {
+		h.Record(d)
+	}
+	return h
+}
@@ -0,0 +1,128 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"time"
+)
+
+const (
+	// histogramSubBucketBits is the number of bits of the sub buckets in each power of two,
+	// the relative error of the recorded values is under 1/16.
+	histogramSubBucketBits  = 4
+	histogramSubBucketCount = 1 << histogramSubBucketBits
+)
+
+// Histogram records the durations in log-linear buckets, which is similar to the HDR histogram.
+// It's not safe for concurrent use, the ConcurrencyStats guards it by the lock.
+type Histogram struct {
+	counts []int64
+	count  int64
+	max    time.Duration
+}
+
+func NewHistogram() *Histogram {
+	return &Histogram{}
+}
+
+func (h *Histogram) Record(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	index := histogramIndex(uint64(d))
+	if index >= len(h.counts) {
+		counts := make([]int64, index+1)
+		copy(counts, h.counts)
+		h.counts = counts
+	}
+	h.counts[index]++
+	h.count++
+	if d > h.max {
+		h.max = d
+	}
+}
+
+func (h *Histogram) Count() int64 {
+	if h == nil {
+		return 0
+	}
+	return h.count
+}
+
+func (h *Histogram) Max() time.Duration {
+	if h == nil {
+		return 0
+	}
+	return h.max
+}
+
+// Percentile returns the value which p percent of the recorded values are less than or equal to,
+// it's the upper bound of the bucket and never greater than the max.
+func (h *Histogram) Percentile(p float64) time.Duration {
+	if h.Count() == 0 {
+		return 0
+	}
+	rank := int64(math.Ceil(p / 100 * float64(h.count)))
+	if rank < 1 {
+		rank = 1
+	}
+	var cumulative int64
+	for i, c := range h.counts {
+		cumulative += c
+		if cumulative >= rank {
+			if v := time.Duration(histogramUpperBound(i)); v < h.max {
+				return v
+			}
+			return h.max
+		}
+	}
+	return h.max
+}
+
+// String returns the p50, p90, p99 and max, which are rounded to 3 significant digits.
+func (h *Histogram) String() string {
+	return fmt.Sprintf("{P50: %s, P90: %s, P99: %s, Max: %s}",
+		roundDuration(h.Percentile(50)), roundDuration(h.Percentile(90)), roundDuration(h.Percentile(99)), roundDuration(h.Max()))
+}
+
+// Clone returns a deep copy, it's nil if h is nil.
+func (h *Histogram) Clone() *Histogram {
+	if h == nil {
+		return nil
+	}
+	cpy := *h
+	cpy.counts = append([]int64(nil), h.counts...)
+	return &cpy
+}
+
+// histogramIndex returns the bucket of v, the values less than histogramSubBucketCount have their own buckets,
+// and each power of two of the greater values is split into histogramSubBucketCount buckets.
+func histogramIndex(v uint64) int {
+	if v < histogramSubBucketCount {
+		return int(v)
+	}
+	shift := bits.Len64(v) - histogramSubBucketBits - 1
+	sub := int(v>>shift) & (histogramSubBucketCount - 1)
+	return (shift+1)*histogramSubBucketCount + sub
+}
+
+// histogramUpperBound returns the max value of the bucket.
+func histogramUpperBound(index int) uint64 {
+	if index < histogramSubBucketCount {
+		return uint64(index)
+	}
+	shift := index/histogramSubBucketCount - 1
+	sub := uint64(index % histogramSubBucketCount)
+	lower := (histogramSubBucketCount + sub) << shift
+	return lower + (1 << shift) - 1
+}
+
+// roundDuration rounds d to 3 significant digits, the digits beyond are not accurate in the histogram.
+func roundDuration(d time.Duration) time.Duration {
+	unit := time.Duration(1)
+	for v := d; v >= 1000; v /= 10 {
+		unit *= 10
+	}
+	return d.Round(unit)
+}
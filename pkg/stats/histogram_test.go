@@ -0,0 +1,74 @@
+package stats
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Histogram", func() {
+	It("empty", func() {
+		var h *Histogram
+		Expect(h.Count()).To(BeZero())
+		Expect(h.Max()).To(BeZero())
+		Expect(h.Percentile(99)).To(BeZero())
+		Expect(h.Clone()).To(BeNil())
+		Expect(NewHistogram().Percentile(50)).To(BeZero())
+	})
+
+	It("percentiles", func() {
+		h := NewHistogram()
+		for i := 100; i >= 1; i-- {
+			h.Record(time.Duration(i) * time.Millisecond)
+		}
+		h.Record(-time.Second)
+		Expect(h.Count()).To(Equal(int64(101)))
+		Expect(h.Max()).To(Equal(100 * time.Millisecond))
+		Expect(h.Percentile(0)).To(BeZero())
+		for _, p := range []float64{50, 90, 99} {
+			expected := float64(time.Duration(p) * time.Millisecond)
+			Expect(float64(h.Percentile(p))).To(BeNumerically("~", expected, expected/16))
+			Expect(h.Percentile(p)).To(BeNumerically(">=", time.Duration(p-1)*time.Millisecond))
+		}
+		Expect(h.Percentile(100)).To(Equal(100 * time.Millisecond))
+		Expect(h.String()).To(HavePrefix("{P50: "))
+	})
+
+	It("small values", func() {
+		h := NewHistogram()
+		for i := 0; i < 16; i++ {
+			h.Record(time.Duration(i))
+		}
+		Expect(h.Percentile(50)).To(Equal(time.Duration(7)))
+		Expect(h.Percentile(100)).To(Equal(time.Duration(15)))
+	})
+
+	It("clone", func() {
+		h := NewHistogram()
+		h.Record(time.Millisecond)
+		cpy := h.Clone()
+		h.Record(time.Second)
+		Expect(cpy.Count()).To(Equal(int64(1)))
+		Expect(cpy.Max()).To(Equal(time.Millisecond))
+		Expect(h.Count()).To(Equal(int64(2)))
+	})
+
+	DescribeTable("bucket bounds",
+		func(v uint64) {
+			index := histogramIndex(v)
+			Expect(histogramUpperBound(index)).To(BeNumerically(">=", v))
+			if index > 0 {
+				Expect(histogramUpperBound(index - 1)).To(BeNumerically("<", v))
+			}
+		},
+		Entry(nil, uint64(0)),
+		Entry(nil, uint64(15)),
+		Entry(nil, uint64(16)),
+		Entry(nil, uint64(17)),
+		Entry(nil, uint64(31)),
+		Entry(nil, uint64(32)),
+		Entry(nil, uint64(1000000)),
+		Entry(nil, uint64(1<<62)),
+	)
+})
@@ -2,6 +2,7 @@ package stats
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/dustin/go-humanize"
@@ -20,9 +21,38 @@ type (
 		TotalRespTime   time.Duration // The cumulative response time.
 		FailedProcessed int64         // The number of nodes and edges that have failed to be processed.
 		TotalProcessed  int64         // The number of nodes and edges that have been processed.
+		SkippedRecords  int64         // The number of records that have been skipped when resuming.
+
+		// LatencyHistogram and RespTimeHistogram record the latency and response time of each succeeded request,
+		// they are nil if no request has succeeded.
+		LatencyHistogram  *Histogram
+		RespTimeHistogram *Histogram
+
+		// Batch and Concurrency are chosen by the adaptive controller, they are zero if it's disabled.
+		Batch       int
+		Concurrency int
+
+		// Sources are the stats of each source by the source name, the requests are of its tags and edges.
+		Sources map[string]*Stats
+		// Targets are the stats of the requests of each tag or edge in the mode, the records and bytes are not counted.
+		Targets map[Target]*Stats
+	}
+
+	// Target is the tag or edge imported by the statement builder.
+	Target struct {
+		Kind string // "tag" or "edge"
+		Name string
+		Mode string
 	}
 )
 
+func (t Target) String() string {
+	if t.Mode == "" {
+		return fmt.Sprintf("%s %s", t.Kind, t.Name)
+	}
+	return fmt.Sprintf("%s %s(%s)", t.Kind, t.Name, t.Mode)
+}
+
 func (s *Stats) IsFailed() bool {
 	return s.FailedRecords > 0 || s.FailedRequest > 0 || s.FailedProcessed > 0
 }
@@ -36,15 +66,11 @@ func (s *Stats) Percentage() float64 {
 
 func (s *Stats) String() string {
 	var (
-		duration           = time.Since(s.StartTime)
-		percentage         = s.Percentage()
-		remainingTime      = "..."
-		seconds            = duration.Seconds()
-		recordsPreSecond   float64
-		avgLatency         time.Duration
-		avgRespTime        time.Duration
-		requestPreSecond   float64
-		processedPreSecond float64
+		duration         = time.Since(s.StartTime)
+		percentage       = s.Percentage()
+		remainingTime    = "..."
+		seconds          = duration.Seconds()
+		recordsPreSecond float64
 	)
 
 	if percentage > 0 {
@@ -55,6 +81,34 @@ func (s *Stats) String() string {
 		recordsPreSecond = float64(s.TotalRecords) / seconds
 	}
 
+	str := fmt.Sprintf("%s %s "+
+		"%.2f%%(%s/%s) "+
+		"Records{Finished: %d, Failed: %d, Rate: %.2f/s}, "+
+		"%s",
+		duration.Truncate(time.Second), remainingTime,
+		percentage, humanize.IBytes(uint64(s.ProcessedBytes)), humanize.IBytes(uint64(s.TotalBytes)), //nolint:gosec
+		s.TotalRecords, s.FailedRecords, recordsPreSecond,
+		s.requestsString(seconds),
+	)
+	if s.Batch > 0 {
+		str += fmt.Sprintf(", Adaptive{Batch: %d, Concurrency: %d}", s.Batch, s.Concurrency)
+	}
+	return str
+}
+
+// RequestsString returns the stats of the requests only, such as the stats of a target.
+func (s *Stats) RequestsString() string {
+	return s.requestsString(time.Since(s.StartTime).Seconds())
+}
+
+func (s *Stats) requestsString(seconds float64) string {
+	var (
+		avgLatency         time.Duration
+		avgRespTime        time.Duration
+		requestPreSecond   float64
+		processedPreSecond float64
+	)
+
 	if s.TotalRequest > 0 {
 		avgLatency = s.TotalLatency / time.Duration(s.TotalRequest)
 		avgRespTime = s.TotalRespTime / time.Duration(s.TotalRequest)
@@ -64,15 +118,40 @@ func (s *Stats) String() string {
 		processedPreSecond = float64(s.TotalProcessed) / seconds
 	}
 
-	return fmt.Sprintf("%s %s "+
-		"%.2f%%(%s/%s) "+
-		"Records{Finished: %d, Failed: %d, Rate: %.2f/s}, "+
-		"Requests{Finished: %d, Failed: %d, Latency: %s/%s, Rate: %.2f/s}, "+
+	str := fmt.Sprintf("Requests{Finished: %d, Failed: %d, Latency: %s/%s, Rate: %.2f/s}, "+
 		"Processed{Finished: %d, Failed: %d, Rate: %.2f/s}",
-		duration.Truncate(time.Second), remainingTime,
-		percentage, humanize.IBytes(uint64(s.ProcessedBytes)), humanize.IBytes(uint64(s.TotalBytes)), //nolint:gosec
-		s.TotalRecords, s.FailedRecords, recordsPreSecond,
 		s.TotalRequest, s.FailedRequest, avgLatency, avgRespTime, requestPreSecond,
 		s.TotalProcessed, s.FailedProcessed, processedPreSecond,
 	)
+	if s.LatencyHistogram.Count() > 0 {
+		// The averages hide the tail latencies.
+		str += fmt.Sprintf(", Latency%s, RespTime%s", s.LatencyHistogram, s.RespTimeHistogram)
+	}
+	return str
+}
+
+// Breakdown returns the stats of each source and target in lines, sorted by the names.
+func (s *Stats) Breakdown() []string {
+	lines := make([]string, 0, len(s.Sources)+len(s.Targets))
+
+	sources := make([]string, 0, len(s.Sources))
+	for name := range s.Sources {
+		sources = append(sources, name)
+	}
+	sort.Strings(sources)
+	for _, name := range sources {
+		lines = append(lines, fmt.Sprintf("source %s: %s", name, s.Sources[name]))
+	}
+
+	targets := make([]Target, 0, len(s.Targets))
+	for t := range s.Targets {
+		targets = append(targets, t)
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].String() < targets[j].String()
+	})
+	for _, t := range targets {
+		lines = append(lines, fmt.Sprintf("%s: %s", t, s.Targets[t].RequestsString()))
+	}
+	return lines
 }
@@ -32,5 +32,58 @@ var _ = Describe("Stats", func() {
 			Expect(s.IsFailed()).To(Equal(true))
 			Expect(s.String()).Should(Equal("10s 20s 33.33%(100 KiB/300 KiB) Records{Finished: 1234, Failed: 23, Rate: 123.40/s}, Requests{Finished: 12, Failed: 1, Latency: 1s/2s, Rate: 1.20/s}, Processed{Finished: 5, Failed: 2, Rate: 0.50/s}"))
 		})
+		It("adaptive", func() {
+			s := &Stats{
+				StartTime:   time.Now(),
+				Batch:       256,
+				Concurrency: 16,
+			}
+			Expect(s.String()).Should(HaveSuffix("Processed{Finished: 0, Failed: 0, Rate: 0.00/s}, Adaptive{Batch: 256, Concurrency: 16}"))
+		})
+
+		It("percentiles", func() {
+			s := &Stats{
+				StartTime:         time.Now().Add(-time.Second * 10),
+				TotalRequest:      2,
+				TotalLatency:      3 * time.Millisecond,
+				TotalRespTime:     6 * time.Millisecond,
+				TotalProcessed:    2,
+				LatencyHistogram:  newTestHistogram(time.Millisecond, 1),
+				RespTimeHistogram: newTestHistogram(2*time.Millisecond, 1),
+			}
+			s.LatencyHistogram.Record(2 * time.Millisecond)
+			s.RespTimeHistogram.Record(4 * time.Millisecond)
+			Expect(s.RequestsString()).Should(Equal("Requests{Finished: 2, Failed: 0, Latency: 1.5ms/3ms, Rate: 0.20/s}, Processed{Finished: 2, Failed: 0, Rate: 0.20/s}, " +
+				"Latency{P50: 1.02ms, P90: 2ms, P99: 2ms, Max: 2ms}, RespTime{P50: 2.03ms, P90: 4ms, P99: 4ms, Max: 4ms}"))
+		})
+	})
+
+	It(".Breakdown", func() {
+		startTime := time.Now().Add(-time.Second * 10)
+		s := &Stats{
+			StartTime: startTime,
+			Sources: map[string]*Stats{
+				"s2": {StartTime: startTime, TotalBytes: 1024, ProcessedBytes: 1024, TotalRecords: 10},
+				"s1": {StartTime: startTime},
+			},
+			Targets: map[Target]*Stats{
+				{Kind: "tag", Name: "n1", Mode: "INSERT"}: {
+					StartTime:      startTime,
+					TotalRequest:   10,
+					FailedRequest:  1,
+					TotalLatency:   10 * time.Second,
+					TotalRespTime:  20 * time.Second,
+					TotalProcessed: 100,
+				},
+				{Kind: "edge", Name: "e1"}: {StartTime: startTime},
+			},
+		}
+		Expect(s.Breakdown()).To(Equal([]string{
+			"source s1: 10s ... 0.00%(0 B/0 B) Records{Finished: 0, Failed: 0, Rate: 0.00/s}, Requests{Finished: 0, Failed: 0, Latency: 0s/0s, Rate: 0.00/s}, Processed{Finished: 0, Failed: 0, Rate: 0.00/s}",
+			"source s2: 10s 0s 100.00%(1.0 KiB/1.0 KiB) Records{Finished: 10, Failed: 0, Rate: 1.00/s}, Requests{Finished: 0, Failed: 0, Latency: 0s/0s, Rate: 0.00/s}, Processed{Finished: 0, Failed: 0, Rate: 0.00/s}",
+			"edge e1: Requests{Finished: 0, Failed: 0, Latency: 0s/0s, Rate: 0.00/s}, Processed{Finished: 0, Failed: 0, Rate: 0.00/s}",
+			"tag n1(INSERT): Requests{Finished: 10, Failed: 1, Latency: 1s/2s, Rate: 1.00/s}, Processed{Finished: 100, Failed: 0, Rate: 10.00/s}",
+		}))
+		Expect((&Stats{}).Breakdown()).To(BeEmpty())
 	})
 })